@@ -0,0 +1,267 @@
+package main
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/exporter"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"eth2-exporter/version"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `usage: backfill <command> [flags]
+
+commands:
+  export     export the epochs --from to --to into the db, an interrupted export resumes unless --restart is set
+  verify     compare the blocks in the db with the blocks on the node, exits with 1 on mismatch
+  repair     re-export all epochs that are missing in the db or differ from the node
+  fix-canon  update the canonical status of the blocks in the db from the node
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	configPath := fs.String("config", "config/default.config.yml", "Path to the config file")
+	from := fs.Uint64("from", 0, "First epoch of the range")
+	to := fs.Uint64("to", 0, "Last epoch of the range, defaults to the head epoch of the node")
+	dryRun := fs.Bool("dry-run", false, "Only print what would change without writing to the db")
+	restart := fs.Bool("restart", false, "Discard the checkpoint of an interrupted export and start again at --from")
+	fs.Parse(os.Args[2:])
+
+	logrus.WithField("config", *configPath).WithField("version", version.Version).Printf("starting")
+	cfg := &types.Config{}
+	err := utils.ReadConfig(cfg, *configPath)
+	if err != nil {
+		logrus.Fatalf("error reading config file: %v", err)
+	}
+	utils.Config = cfg
+
+	db.MustInitDB(&types.DatabaseConfig{
+		Username: cfg.WriterDatabase.Username,
+		Password: cfg.WriterDatabase.Password,
+		Name:     cfg.WriterDatabase.Name,
+		Host:     cfg.WriterDatabase.Host,
+		Port:     cfg.WriterDatabase.Port,
+	}, &types.DatabaseConfig{
+		Username: cfg.ReaderDatabase.Username,
+		Password: cfg.ReaderDatabase.Password,
+		Name:     cfg.ReaderDatabase.Name,
+		Host:     cfg.ReaderDatabase.Host,
+		Port:     cfg.ReaderDatabase.Port,
+	})
+	defer db.ReaderDb.Close()
+	defer db.WriterDb.Close()
+
+	client, err := rpc.NewIndexerClient()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	if *to == 0 {
+		head, err := client.GetChainHead()
+		if err != nil {
+			logrus.Fatalf("error retrieving chain head: %v", err)
+		}
+		*to = head.HeadEpoch
+	}
+	if *from > *to {
+		logrus.Fatalf("invalid epoch range %v-%v", *from, *to)
+	}
+
+	switch command {
+	case "export":
+		err = export(client, *from, *to, *dryRun, *restart)
+	case "verify":
+		err = verify(client, *from, *to)
+	case "repair":
+		err = repair(client, *from, *to, *dryRun)
+	case "fix-canon":
+		err = fixCanon(client, *from, *to, *dryRun)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		logrus.Error(err)
+		os.Exit(1)
+	}
+}
+
+// export will export all epochs of the range, resuming an interrupted export of the same range unless restart is set
+func export(client rpc.Client, from, to uint64, dryRun, restart bool) error {
+	epochs := make([]uint64, 0, to-from+1)
+	for epoch := from; epoch <= to; epoch++ {
+		epochs = append(epochs, epoch)
+	}
+
+	if dryRun {
+		existing, err := existingEpochs(from, to)
+		if err != nil {
+			return err
+		}
+		for _, epoch := range epochs {
+			if existing[epoch] {
+				fmt.Printf("would re-export epoch %v\n", epoch)
+			} else {
+				fmt.Printf("would export epoch %v\n", epoch)
+			}
+		}
+		return nil
+	}
+
+	if restart {
+		err := db.DeleteBackfillCheckpoint("cmd-export", from)
+		if err != nil {
+			return fmt.Errorf("error deleting checkpoint of the export: %v", err)
+		}
+	}
+	return exporter.Backfill("cmd-export", epochs, client)
+}
+
+// verify will compare the blocks in the db with the blocks on the node and fail if any of them differ
+func verify(client rpc.Client, from, to uint64) error {
+	epochs, err := mismatchedEpochs(client, from, to)
+	if err != nil {
+		return err
+	}
+	if len(epochs) > 0 {
+		return fmt.Errorf("found %v epochs between %v and %v that differ from the node", len(epochs), from, to)
+	}
+	logrus.Infof("all blocks of epochs %v-%v match the node", from, to)
+	return nil
+}
+
+// repair will re-export all epochs that are missing in the db or differ from the node
+func repair(client rpc.Client, from, to uint64, dryRun bool) error {
+	epochs, err := mismatchedEpochs(client, from, to)
+	if err != nil {
+		return err
+	}
+	if len(epochs) == 0 {
+		logrus.Infof("nothing to repair in epochs %v-%v", from, to)
+		return nil
+	}
+
+	if dryRun {
+		for _, epoch := range epochs {
+			fmt.Printf("would re-export epoch %v\n", epoch)
+		}
+		return fmt.Errorf("found %v epochs between %v and %v that need to be repaired", len(epochs), from, to)
+	}
+
+	// the epochs to repair are determined from scratch on every run, a checkpoint of a previous run must not skip any of them
+	err = db.DeleteBackfillCheckpoint("cmd-repair", epochs[0])
+	if err != nil {
+		return fmt.Errorf("error deleting checkpoint of the repair: %v", err)
+	}
+	return exporter.Backfill("cmd-repair", epochs, client)
+}
+
+// fixCanon will update the canonical status of all blocks of the range that differ from the node
+func fixCanon(client rpc.Client, from, to uint64, dryRun bool) error {
+	changed := 0
+	for epoch := from; epoch <= to; epoch++ {
+		nodeBlocks, err := client.GetBlockStatusByEpoch(epoch)
+		if err != nil {
+			return fmt.Errorf("error retrieving block status of epoch %v: %v", epoch, err)
+		}
+
+		startSlot := epoch * utils.Config.Chain.Config.SlotsPerEpoch
+		dbStatus, err := db.GetBlocksStatus(startSlot, startSlot+utils.Config.Chain.Config.SlotsPerEpoch-1)
+		if err != nil {
+			return err
+		}
+
+		update := make([]*types.CanonBlock, 0)
+		for _, block := range nodeBlocks {
+			status, found := dbStatus[fmt.Sprintf("%x", block.BlockRoot)]
+			if !found {
+				continue
+			}
+			if (block.Canonical && status != "1") || (!block.Canonical && status != "3") {
+				if dryRun {
+					fmt.Printf("would mark block %x at slot %v as canonical=%v (status %v in db)\n", block.BlockRoot, block.Slot, block.Canonical, status)
+				}
+				update = append(update, block)
+			}
+		}
+		changed += len(update)
+
+		if !dryRun {
+			err = db.SetBlockStatus(update)
+			if err != nil {
+				return fmt.Errorf("error saving block status of epoch %v: %v", epoch, err)
+			}
+		}
+	}
+
+	if dryRun && changed > 0 {
+		return fmt.Errorf("found %v blocks between epochs %v and %v with a wrong canonical status", changed, from, to)
+	}
+	logrus.Infof("updated the canonical status of %v blocks of epochs %v-%v", changed, from, to)
+	return nil
+}
+
+// mismatchedEpochs will return all epochs of the range that are missing in the db or contain blocks that differ from the node
+func mismatchedEpochs(client rpc.Client, from, to uint64) ([]uint64, error) {
+	dbBlocks, err := db.GetLastPendingAndProposedBlocks(from, to)
+	if err != nil {
+		return nil, err
+	}
+	nodeBlocks, err := exporter.GetLastBlocks(from, to, client)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving blocks from the node: %v", err)
+	}
+	existing, err := existingEpochs(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	epochs := make(map[uint64]bool)
+	for epoch := from; epoch <= to; epoch++ {
+		if !existing[epoch] {
+			fmt.Printf("epoch %v is missing in the db\n", epoch)
+			epochs[epoch] = true
+		}
+	}
+	for _, mismatch := range exporter.CompareBlocks(dbBlocks, nodeBlocks) {
+		fmt.Printf("block %v of epoch %v %v\n", mismatch.Key, mismatch.Epoch, mismatch.Reason)
+		epochs[mismatch.Epoch] = true
+	}
+
+	sorted := make([]uint64, 0, len(epochs))
+	for epoch := range epochs {
+		sorted = append(sorted, epoch)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted, nil
+}
+
+// existingEpochs will return the set of epochs of the range that are present in the db
+func existingEpochs(from, to uint64) (map[uint64]bool, error) {
+	epochs, err := db.GetAllEpochs()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uint64]bool)
+	for _, epoch := range epochs {
+		if epoch >= from && epoch <= to {
+			existing[epoch] = true
+		}
+	}
+	return existing, nil
+}
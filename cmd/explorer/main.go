@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/gob"
+	"encoding/hex"
 	"eth2-exporter/db"
 	ethclients "eth2-exporter/ethClients"
 	"eth2-exporter/exporter"
@@ -15,7 +16,6 @@ import (
 	"eth2-exporter/version"
 	"flag"
 	"fmt"
-	"math/big"
 	"net/http"
 	"time"
 
@@ -25,6 +25,7 @@ import (
 
 	_ "eth2-exporter/docs"
 
+	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/phyber/negroni-gzip/gzip"
@@ -74,6 +75,15 @@ func main() {
 	})
 	defer db.ReaderDb.Close()
 	defer db.WriterDb.Close()
+
+	if cfg.WriterDatabase.MigrateOnStartup {
+		applied, err := db.MigrateUp(db.WriterDb)
+		if err != nil {
+			logrus.Fatalf("error applying db migrations: %v", err)
+		}
+		logrus.Infof("applied %v db migrations", applied)
+	}
+
 	db.MustInitFrontendDB(&types.DatabaseConfig{
 		Username: cfg.Frontend.WriterDatabase.Username,
 		Password: cfg.Frontend.WriterDatabase.Password,
@@ -105,45 +115,25 @@ func main() {
 	}
 
 	if utils.Config.Indexer.Enabled {
-		var rpcClient rpc.Client
-
-		chainID := new(big.Int).SetUint64(utils.Config.Chain.Config.DepositChainID)
-		if utils.Config.Indexer.Node.Type == "prysm" {
-			if utils.Config.Indexer.Node.PageSize == 0 {
-				logrus.Printf("setting default rpc page size to 500")
-				utils.Config.Indexer.Node.PageSize = 500
-			}
-			rpcClient, err = rpc.NewPrysmClient(cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.GRPCPort, "http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
-			if err != nil {
-				logrus.Fatal(err)
-			}
-		} else if utils.Config.Indexer.Node.Type == "lighthouse" {
-			rpcClient, err = rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
-			if err != nil {
-				logrus.Fatal(err)
-			}
-		} else {
-			logrus.Fatalf("invalid note type %v specified. supported node types are prysm and lighthouse", utils.Config.Indexer.Node.Type)
+		rpcClient, err := rpc.NewIndexerClient()
+		if err != nil {
+			logrus.Fatal(err)
 		}
 
 		if utils.Config.Indexer.OneTimeExport.Enabled {
-			if len(utils.Config.Indexer.OneTimeExport.Epochs) > 0 {
-				logrus.Infof("onetimeexport epochs: %+v", utils.Config.Indexer.OneTimeExport.Epochs)
-				for _, epoch := range utils.Config.Indexer.OneTimeExport.Epochs {
-					err := exporter.ExportEpoch(epoch, rpcClient)
-					if err != nil {
-						logrus.Fatal(err)
-					}
-				}
+			epochs := utils.Config.Indexer.OneTimeExport.Epochs
+			if len(epochs) > 0 {
+				logrus.Infof("onetimeexport epochs: %+v", epochs)
 			} else {
 				logrus.Infof("onetimeexport epochs: %v-%v", utils.Config.Indexer.OneTimeExport.StartEpoch, utils.Config.Indexer.OneTimeExport.EndEpoch)
 				for epoch := utils.Config.Indexer.OneTimeExport.StartEpoch; epoch <= utils.Config.Indexer.OneTimeExport.EndEpoch; epoch++ {
-					err := exporter.ExportEpoch(epoch, rpcClient)
-					if err != nil {
-						logrus.Fatal(err)
-					}
+					epochs = append(epochs, epoch)
 				}
 			}
+			err := exporter.Backfill("onetimeexport", epochs, rpcClient)
+			if err != nil {
+				logrus.Fatal(err)
+			}
 			return
 		}
 
@@ -165,7 +155,10 @@ func main() {
 		apiV1Router.HandleFunc("/block/{slot}/attesterslashings", handlers.ApiBlockAttesterSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/block/{slot}/proposerslashings", handlers.ApiBlockProposerSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/block/{slot}/voluntaryexits", handlers.ApiBlockVoluntaryExits).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/tx/{hash}", handlers.ApiExecutionTransaction).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/address/{address}", handlers.ApiAddress).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/sync_committee/{period}", handlers.ApiSyncCommittee).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/sync_committee/{period}/performance", handlers.ApiSyncCommitteePerformance).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/eth1deposit/{txhash}", handlers.ApiEth1Deposit).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/leaderboard", handlers.ApiValidatorLeaderboard).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}", handlers.ApiValidator).Methods("GET", "OPTIONS")
@@ -176,12 +169,18 @@ func main() {
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/deposits", handlers.ApiValidatorDeposits).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawals", handlers.ApiValidatorWithdrawals).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/total_withdrawals", handlers.ApiValidatorTotalWithdrawals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/rewards", handlers.ApiValidatorRewards).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawalcredentials", handlers.ApiValidatorWithdrawalCredentials).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/next_withdrawal", handlers.ApiValidatorNextWithdrawal).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationefficiency", handlers.ApiValidatorAttestationEfficiency).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationeffectiveness", handlers.ApiValidatorAttestationEffectiveness).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationcorrectness", handlers.ApiValidatorAttestationCorrectness).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/duties", handlers.ApiDuties).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/stats/{index}", handlers.ApiValidatorDailyStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/eth1/{address}", handlers.ApiValidatorByEth1Address).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validators/queue", handlers.ApiValidatorQueue).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/graffitiwall", handlers.ApiGraffitiwall).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/reorgs", handlers.ApiReorgs).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/chart/{chart}", handlers.ApiChart).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/user/token", handlers.APIGetToken).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/dashboard/data/balances", handlers.DashboardDataBalance).Methods("GET", "OPTIONS")   // new app versions
@@ -221,6 +220,11 @@ func main() {
 		// apiV1AuthRouter.Use(utils.CORSMiddleware)
 		// apiV1AuthRouter.Use(utils.AuthorizedAPIMiddleware)
 
+		apiV1AdminRouter := apiV1Router.PathPrefix("/admin").Subrouter()
+		apiV1AdminRouter.HandleFunc("/export_failures", handlers.ApiAdminExportFailures).Methods("GET", "OPTIONS")
+		apiV1AdminRouter.HandleFunc("/export_failures/{epoch}/retry", handlers.ApiAdminExportFailureRetry).Methods("POST", "OPTIONS")
+		apiV1AdminRouter.Use(utils.AdminAPIMiddleware)
+
 		router.HandleFunc("/api/healthz", handlers.ApiHealthz).Methods("GET", "HEAD")
 		router.HandleFunc("/api/healthz-loadbalancer", handlers.ApiHealthzLoadbalancer).Methods("GET", "HEAD")
 
@@ -239,17 +243,17 @@ func main() {
 
 			utils.InitSessionStore(cfg.Frontend.SessionSecret)
 
-			// csrfBytes, err := hex.DecodeString(cfg.Frontend.CsrfAuthKey)
-			// if err != nil {
-			// 	logrus.WithError(err).Error("error decoding csrf auth key falling back to empty csrf key")
-			// }
+			csrfBytes, err := hex.DecodeString(cfg.Frontend.CsrfAuthKey)
+			if err != nil {
+				logrus.WithError(err).Error("error decoding csrf auth key falling back to empty csrf key")
+			}
 
-			// csrfHandler := csrf.Protect(
-			// 	csrfBytes,
-			// 	csrf.FieldName("CsrfField"),
-			// 	csrf.Secure(!cfg.Frontend.CsrfInsecure),
-			// 	csrf.Path("/"),
-			// )
+			csrfHandler := csrf.Protect(
+				csrfBytes,
+				csrf.FieldName("CsrfField"),
+				csrf.Secure(!cfg.Frontend.CsrfInsecure),
+				csrf.Path("/"),
+			)
 
 			router.HandleFunc("/", handlers.Index).Methods("GET")
 			router.HandleFunc("/latestState", handlers.LatestState).Methods("GET")
@@ -258,6 +262,8 @@ func main() {
 			router.HandleFunc("/block/{slotOrHash}", handlers.Block).Methods("GET")
 			router.HandleFunc("/block/{slotOrHash}/deposits", handlers.BlockDepositData).Methods("GET")
 			router.HandleFunc("/block/{slotOrHash}/votes", handlers.BlockVoteData).Methods("GET")
+			router.HandleFunc("/tx/{hash}", handlers.Tx).Methods("GET")
+			router.HandleFunc("/address/{address}", handlers.Address).Methods("GET")
 			router.HandleFunc("/blocks", handlers.Blocks).Methods("GET")
 			router.HandleFunc("/blocks/data", handlers.BlocksData).Methods("GET")
 			router.HandleFunc("/vis", handlers.Vis).Methods("GET")
@@ -305,8 +311,13 @@ func main() {
 			router.HandleFunc("/dashboard/data/proposalshistory", handlers.DashboardDataProposalsHistory).Methods("GET")
 			router.HandleFunc("/dashboard/data/validators", handlers.DashboardDataValidators).Methods("GET")
 			router.HandleFunc("/dashboard/data/effectiveness", handlers.DashboardDataEffectiveness).Methods("GET")
+			router.HandleFunc("/dashboard/data/attestationcorrectness", handlers.DashboardDataAttestationCorrectness).Methods("GET")
+			router.HandleFunc("/dashboard/data/duties", handlers.DashboardDataDuties).Methods("GET")
 			router.HandleFunc("/dashboard/data/earnings", handlers.DashboardDataEarnings).Methods("GET")
+			router.HandleFunc("/dashboard/data/withdrawalcredentials", handlers.DashboardDataWithdrawalCredentials).Methods("GET")
 			router.HandleFunc("/graffitiwall", handlers.Graffitiwall).Methods("GET")
+			router.HandleFunc("/reorgs", handlers.Reorgs).Methods("GET")
+			router.HandleFunc("/reorgs/data", handlers.ReorgsData).Methods("GET")
 			router.HandleFunc("/calculator", handlers.StakingCalculator).Methods("GET")
 			router.HandleFunc("/search", handlers.Search).Methods("POST")
 			router.HandleFunc("/search/{type}/{search}", handlers.SearchAhead).Methods("GET")
@@ -406,10 +417,6 @@ func main() {
 			// authRouter.HandleFunc("/rewards/subscribe", handlers.RewardNotificationSubscribe).Methods("POST")
 			// authRouter.HandleFunc("/rewards/unsubscribe", handlers.RewardNotificationUnsubscribe).Methods("POST")
 			// authRouter.HandleFunc("/rewards/subscriptions/data", handlers.RewardGetUserSubscriptions).Methods("POST")
-			// authRouter.HandleFunc("/webhooks", handlers.NotificationWebhookPage).Methods("GET")
-			// authRouter.HandleFunc("/webhooks/add", handlers.UsersAddWebhook).Methods("POST")
-			// authRouter.HandleFunc("/webhooks/{webhookID}/update", handlers.UsersEditWebhook).Methods("POST")
-			// authRouter.HandleFunc("/webhooks/{webhookID}/delete", handlers.UsersDeleteWebhook).Methods("POST")
 
 			// err = initStripe(authRouter)
 			// if err != nil {
@@ -419,6 +426,15 @@ func main() {
 			// authRouter.Use(handlers.UserAuthMiddleware)
 			// authRouter.Use(csrfHandler)
 
+			webhookRouter := router.PathPrefix("/user/webhooks").Subrouter()
+			webhookRouter.HandleFunc("", handlers.NotificationWebhookPage).Methods("GET")
+			webhookRouter.HandleFunc("/add", handlers.UsersAddWebhook).Methods("POST")
+			webhookRouter.HandleFunc("/{webhookID}/update", handlers.UsersEditWebhook).Methods("POST")
+			webhookRouter.HandleFunc("/{webhookID}/delete", handlers.UsersDeleteWebhook).Methods("POST")
+			webhookRouter.HandleFunc("/deliveries/{deliveryID}/resend", handlers.UsersResendWebhookDelivery).Methods("POST")
+			webhookRouter.Use(handlers.UserAuthMiddleware)
+			webhookRouter.Use(csrfHandler)
+
 			if utils.Config.Frontend.Debug {
 				templatesHandler := http.FileServer(http.Dir("templates"))
 				router.PathPrefix("/templates").Handler(http.StripPrefix("/templates/", templatesHandler))
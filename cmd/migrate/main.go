@@ -0,0 +1,97 @@
+package main
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"eth2-exporter/version"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `usage: migrate <command> [flags]
+
+commands:
+  up      apply all pending migrations
+  down    roll back the last --steps applied migrations
+  status  list all migrations and when they were applied
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	configPath := fs.String("config", "config/default.config.yml", "Path to the config file")
+	steps := fs.Int("steps", 1, "Number of migrations to roll back")
+	fs.Parse(os.Args[2:])
+
+	logrus.WithField("config", *configPath).WithField("version", version.Version).Printf("starting")
+	cfg := &types.Config{}
+	err := utils.ReadConfig(cfg, *configPath)
+	if err != nil {
+		logrus.Fatalf("error reading config file: %v", err)
+	}
+	utils.Config = cfg
+
+	db.MustInitDB(&types.DatabaseConfig{
+		Username: cfg.WriterDatabase.Username,
+		Password: cfg.WriterDatabase.Password,
+		Name:     cfg.WriterDatabase.Name,
+		Host:     cfg.WriterDatabase.Host,
+		Port:     cfg.WriterDatabase.Port,
+	}, &types.DatabaseConfig{
+		Username: cfg.ReaderDatabase.Username,
+		Password: cfg.ReaderDatabase.Password,
+		Name:     cfg.ReaderDatabase.Name,
+		Host:     cfg.ReaderDatabase.Host,
+		Port:     cfg.ReaderDatabase.Port,
+	})
+	defer db.ReaderDb.Close()
+	defer db.WriterDb.Close()
+
+	switch command {
+	case "up":
+		var applied int
+		applied, err = db.MigrateUp(db.WriterDb)
+		logrus.Infof("applied %v migrations", applied)
+	case "down":
+		var rolledBack int
+		rolledBack, err = db.MigrateDown(db.WriterDb, *steps)
+		logrus.Infof("rolled back %v migrations", rolledBack)
+	case "status":
+		err = status()
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		logrus.Error(err)
+		os.Exit(1)
+	}
+}
+
+// status will print every known migration and the time it was applied
+func status() error {
+	migrations, err := db.GetMigrationsStatus(db.WriterDb)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		appliedAt := "pending"
+		if migration.AppliedAt != nil {
+			appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%05d  %-40v  %v\n", migration.Version, migration.Name, appliedAt)
+	}
+	return nil
+}
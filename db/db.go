@@ -506,6 +506,32 @@ func GetLastPendingAndProposedBlocks(startEpoch, endEpoch uint64) ([]*types.Mini
 	return blocks, nil
 }
 
+// GetHeadBlock will return the proposed block with the highest slot from the database
+func GetHeadBlock() (*types.MinimalBlock, error) {
+	block := &types.MinimalBlock{}
+	err := WriterDb.Get(block, "SELECT epoch, slot, blockroot, parentroot FROM blocks WHERE status = '1' ORDER BY slot DESC LIMIT 1")
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving head block from DB: %v", err)
+	}
+	return block, nil
+}
+
+// GetProposedBlockRoot will return the root of the proposed block at a slot, or nil if the slot has no proposed block in the database
+func GetProposedBlockRoot(slot uint64) ([]byte, error) {
+	var blockRoot []byte
+	err := WriterDb.Get(&blockRoot, "SELECT blockroot FROM blocks WHERE slot = $1 AND status = '1' LIMIT 1", slot)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block root at slot %v from DB: %v", slot, err)
+	}
+	return blockRoot, nil
+}
+
 // GetBlocks will return all blocks for a range of epochs from the database
 func GetBlocks(startEpoch, endEpoch uint64) ([]*types.MinimalBlock, error) {
 	var blocks []*types.MinimalBlock
@@ -638,6 +664,56 @@ func SetBlockStatus(blocks []*types.CanonBlock) error {
 	return tx.Commit()
 }
 
+// GetBlocksStatus will return the status of all blocks between the start and end slot, indexed by the hex encoded block root
+func GetBlocksStatus(startSlot, endSlot uint64) (map[string]string, error) {
+	rows := []struct {
+		BlockRoot []byte `db:"blockroot"`
+		Status    string `db:"status"`
+	}{}
+	err := WriterDb.Select(&rows, "SELECT blockroot, status FROM blocks WHERE slot >= $1 AND slot <= $2", startSlot, endSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block status for slots %v-%v: %v", startSlot, endSlot, err)
+	}
+
+	status := make(map[string]string, len(rows))
+	for _, row := range rows {
+		status[fmt.Sprintf("%x", row.BlockRoot)] = row.Status
+	}
+	return status, nil
+}
+
+// SaveReorg will roll back the operations of the orphaned blocks of a reorg, mark the blocks as orphaned and record the reorg
+func SaveReorg(reorg *types.Reorg, orphanedBlocks [][]byte) error {
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	roots := pq.ByteaArray(orphanedBlocks)
+	for _, table := range []string{"blocks_transactions", "blocks_proposerslashings", "blocks_attesterslashings", "blocks_attestations", "blocks_deposits", "blocks_voluntaryexits", "blocks_withdrawals", "blocks_bls_change", "blocks_blobs", "blocks_blob_sidecars", "blocks_transactions_receipts", "blocks_transactions_logs"} {
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE block_root = ANY($1)", table), roots)
+		if err != nil {
+			return fmt.Errorf("error rolling back %v of orphaned blocks: %v", table, err)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE blocks SET status = '3' WHERE blockroot = ANY($1)", roots)
+	if err != nil {
+		return fmt.Errorf("error marking blocks as orphaned: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO reorgs (slot, depth, common_ancestor_slot, old_head_slot, old_head_root, new_head_slot, new_head_root, orphaned_blocks, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		reorg.Slot, reorg.Depth, reorg.CommonAncestorSlot, reorg.OldHeadSlot, reorg.OldHeadRoot, reorg.NewHeadSlot, reorg.NewHeadRoot, reorg.OrphanedBlocks, reorg.DetectedAt)
+	if err != nil {
+		return fmt.Errorf("error saving reorg: %v", err)
+	}
+
+	return tx.Commit()
+}
+
 // SaveValidatorQueue will save the validator queue into the database
 func SaveValidatorQueue(validators *types.ValidatorQueue) error {
 	_, err := WriterDb.Exec(`
@@ -769,6 +845,17 @@ func SaveEpoch(data *types.EpochData) error {
 
 	validatorBalanceAverage := new(big.Int).Div(validatorBalanceSum, new(big.Int).SetInt64(int64(validatorsCount)))
 
+	// the participation is stored as unknown if the node could not provide it, the epoch status update fills it in later
+	finalized := false
+	var eligibleEther, votedEther sql.NullInt64
+	var participationRate sql.NullFloat64
+	if data.EpochParticipationStats != nil {
+		finalized = data.EpochParticipationStats.Finalized
+		eligibleEther = sql.NullInt64{Int64: int64(data.EpochParticipationStats.EligibleEther), Valid: true}
+		votedEther = sql.NullInt64{Int64: int64(data.EpochParticipationStats.VotedEther), Valid: true}
+		participationRate = sql.NullFloat64{Float64: float64(data.EpochParticipationStats.GlobalParticipationRate), Valid: true}
+	}
+
 	_, err = tx.Exec(`
 		INSERT INTO epochs (
 			epoch,
@@ -798,9 +885,9 @@ func SaveEpoch(data *types.EpochData) error {
 			averagevalidatorbalance = excluded.averagevalidatorbalance,
 			totalvalidatorbalance   = excluded.totalvalidatorbalance,
 			finalized               = excluded.finalized,
-			eligibleether           = excluded.eligibleether,
-			globalparticipationrate = excluded.globalparticipationrate,
-			votedether              = excluded.votedether`,
+			eligibleether           = COALESCE(excluded.eligibleether, epochs.eligibleether),
+			globalparticipationrate = COALESCE(excluded.globalparticipationrate, epochs.globalparticipationrate),
+			votedether              = COALESCE(excluded.votedether, epochs.votedether)`,
 		data.Epoch,
 		len(data.Blocks),
 		proposerSlashingsCount,
@@ -811,10 +898,10 @@ func SaveEpoch(data *types.EpochData) error {
 		validatorsCount,
 		validatorBalanceAverage.Uint64(),
 		validatorBalanceSum.Uint64(),
-		data.EpochParticipationStats.Finalized,
-		data.EpochParticipationStats.EligibleEther,
-		data.EpochParticipationStats.GlobalParticipationRate,
-		data.EpochParticipationStats.VotedEther)
+		finalized,
+		eligibleEther,
+		participationRate,
+		votedEther)
 
 	if err != nil {
 		return fmt.Errorf("error executing save epoch statement: %w", err)
@@ -1524,8 +1611,8 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 	}()
 
 	stmtBlock, err := tx.Prepare(`
-		INSERT INTO blocks (epoch, slot, blockroot, parentroot, stateroot, signature, randaoreveal, graffiti, graffiti_text, eth1data_depositroot, eth1data_depositcount, eth1data_blockhash, syncaggregate_bits, syncaggregate_signature, proposerslashingscount, attesterslashingscount, attestationscount, depositscount, voluntaryexitscount, syncaggregate_participation, proposer, status, exec_parent_hash, exec_fee_recipient, exec_state_root, exec_receipts_root, exec_logs_bloom, exec_random, exec_block_number, exec_gas_limit, exec_gas_used, exec_timestamp, exec_extra_data, exec_base_fee_per_gas, exec_block_hash, exec_transactions_count)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36)
+		INSERT INTO blocks (epoch, slot, blockroot, parentroot, stateroot, signature, randaoreveal, graffiti, graffiti_text, eth1data_depositroot, eth1data_depositcount, eth1data_blockhash, syncaggregate_bits, syncaggregate_signature, proposerslashingscount, attesterslashingscount, attestationscount, depositscount, voluntaryexitscount, syncaggregate_participation, proposer, status, exec_parent_hash, exec_fee_recipient, exec_state_root, exec_receipts_root, exec_logs_bloom, exec_random, exec_block_number, exec_gas_limit, exec_gas_used, exec_timestamp, exec_extra_data, exec_base_fee_per_gas, exec_block_hash, exec_transactions_count, exec_blob_gas_used, exec_excess_blob_gas, blobcount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39)
 		ON CONFLICT (slot, blockroot) DO NOTHING`)
 	if err != nil {
 		return err
@@ -1533,8 +1620,8 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 	defer stmtBlock.Close()
 
 	stmtTransaction, err := tx.Prepare(`
-		INSERT INTO blocks_transactions (block_slot, block_index, block_root, raw, txhash, nonce, gas_price, gas_limit, sender, recipient, amount, payload, max_priority_fee_per_gas, max_fee_per_gas)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		INSERT INTO blocks_transactions (block_slot, block_index, block_root, raw, txhash, nonce, gas_price, gas_limit, sender, recipient, amount, payload, max_priority_fee_per_gas, max_fee_per_gas, max_fee_per_blob_gas, blob_versioned_hashes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (block_slot, block_index) DO NOTHING`)
 	if err != nil {
 		return err
@@ -1619,6 +1706,15 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 	}
 	defer stmtBLSChange.Close()
 
+	stmtBlobs, err := tx.Prepare(`
+		INSERT INTO blocks_blobs (block_slot, block_root, blob_index, kzg_commitment, versioned_hash, tx_hash)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (block_slot, block_root, blob_index) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmtBlobs.Close()
+
 	slots := make([]uint64, 0, len(blocks))
 	for slot := range blocks {
 		slots = append(slots, slot)
@@ -1680,6 +1776,11 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 			baseFeePerGas := uint64(0)
 			blockHash := []byte{}
 			txCount := 0
+			var blobGasUsed, excessBlobGas *uint64
+			if b.ExecutionPayload != nil && utils.ForkAtSlot(b.Slot).Has(utils.ForkFeatureBlobs) {
+				blobGasUsed = &b.ExecutionPayload.BlobGasUsed
+				excessBlobGas = &b.ExecutionPayload.ExcessBlobGas
+			}
 			if b.ExecutionPayload != nil {
 				parentHash = b.ExecutionPayload.ParentHash
 				feeRecipient = b.ExecutionPayload.FeeRecipient
@@ -1733,6 +1834,9 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 				baseFeePerGas,
 				blockHash,
 				txCount,
+				blobGasUsed,
+				excessBlobGas,
+				len(b.BlobKZGCommitments),
 			)
 			if err != nil {
 				return fmt.Errorf("error executing stmtBlocks for block %v: %w", b.Slot, err)
@@ -1746,7 +1850,7 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 			if payload := b.ExecutionPayload; payload != nil {
 				for i, tx := range payload.Transactions {
 					_, err := stmtTransaction.Exec(b.Slot, i, b.BlockRoot,
-						tx.Raw, tx.TxHash, tx.AccountNonce, tx.Price, tx.GasLimit, tx.Sender, tx.Recipient, tx.Amount, tx.Payload, tx.MaxPriorityFeePerGas, tx.MaxFeePerGas)
+						tx.Raw, tx.TxHash, tx.AccountNonce, tx.Price, tx.GasLimit, tx.Sender, tx.Recipient, tx.Amount, tx.Payload, tx.MaxPriorityFeePerGas, tx.MaxFeePerGas, tx.MaxFeePerBlobGas, pq.ByteaArray(tx.BlobVersionedHashes))
 					if err != nil {
 						return fmt.Errorf("error executing stmtTransaction for block %v: %v", b.Slot, err)
 					}
@@ -1754,6 +1858,27 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sql.Tx) error {
 			}
 			logger.Tracef("done, took %v", time.Since(n))
 
+			n = time.Now()
+			logger.Tracef("writing blobs data")
+			if len(b.BlobKZGCommitments) > 0 {
+				blobTxs := make(map[string][]byte)
+				if b.ExecutionPayload != nil {
+					for _, tx := range b.ExecutionPayload.Transactions {
+						for _, hash := range tx.BlobVersionedHashes {
+							blobTxs[string(hash)] = tx.TxHash
+						}
+					}
+				}
+				for i, commitment := range b.BlobKZGCommitments {
+					versionedHash := utils.KZGCommitmentToVersionedHash(commitment)
+					_, err := stmtBlobs.Exec(b.Slot, b.BlockRoot, i, commitment, versionedHash, blobTxs[string(versionedHash)])
+					if err != nil {
+						return fmt.Errorf("error executing stmtBlobs for block %v: %v: %v", b.Slot, i, err)
+					}
+				}
+			}
+			logger.Tracef("done, took %v", time.Since(n))
+
 			n = time.Now()
 			logger.Tracef("writing withdrawal data")
 
@@ -1940,6 +2065,104 @@ func UpdateEpochFinalization() error {
 	return err
 }
 
+// MarkEpochsFinalized will mark all epochs before the finalized checkpoint epoch as finalized
+func MarkEpochsFinalized(checkpointEpoch uint64) error {
+	_, err := WriterDb.Exec(`UPDATE epochs SET finalized = true WHERE epoch < $1 AND NOT finalized`, checkpointEpoch)
+	return err
+}
+
+// GetBackfillCheckpoint will return the last epoch saved by the backfill with the given name and start epoch
+func GetBackfillCheckpoint(name string, startEpoch uint64) (uint64, bool, error) {
+	var lastEpoch uint64
+	err := WriterDb.Get(&lastEpoch, `SELECT last_epoch FROM backfill_checkpoints WHERE name = $1 AND start_epoch = $2`, name, startEpoch)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return lastEpoch, true, nil
+}
+
+// SaveBackfillCheckpoint will store the last epoch saved by the backfill with the given name and start epoch
+func SaveBackfillCheckpoint(name string, startEpoch, lastEpoch uint64) error {
+	_, err := WriterDb.Exec(`
+		INSERT INTO backfill_checkpoints (name, start_epoch, last_epoch, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (name, start_epoch) DO UPDATE SET
+			last_epoch = excluded.last_epoch,
+			updated_at = excluded.updated_at`, name, startEpoch, lastEpoch)
+	return err
+}
+
+// DeleteBackfillCheckpoint will remove the checkpoint of the backfill with the given name and start epoch
+func DeleteBackfillCheckpoint(name string, startEpoch uint64) error {
+	_, err := WriterDb.Exec(`DELETE FROM backfill_checkpoints WHERE name = $1 AND start_epoch = $2`, name, startEpoch)
+	return err
+}
+
+// SaveExportFailure will record a failed export of an epoch. The next retry is scheduled with an exponential backoff
+// starting at baseBackoff and capped at maxBackoff.
+func SaveExportFailure(epoch uint64, exportErr error, baseBackoff, maxBackoff time.Duration) (*types.ExportFailure, error) {
+	failure := &types.ExportFailure{}
+	err := WriterDb.Get(failure, `
+		INSERT INTO export_failures (epoch, error, attempts, first_failed_at, last_failed_at, next_retry_at)
+		VALUES ($1, $2, 1, NOW(), NOW(), NOW() + $3::float8 * interval '1 second')
+		ON CONFLICT (epoch) DO UPDATE SET
+			error = excluded.error,
+			attempts = export_failures.attempts + 1,
+			last_failed_at = excluded.last_failed_at,
+			next_retry_at = NOW() + LEAST($3::float8 * power(2, export_failures.attempts), $4::float8) * interval '1 second'
+		RETURNING epoch, error, attempts, first_failed_at, last_failed_at, next_retry_at`,
+		epoch, exportErr.Error(), baseBackoff.Seconds(), maxBackoff.Seconds())
+	return failure, err
+}
+
+// DeleteExportFailure will remove an epoch from the export failures after it has been exported successfully
+func DeleteExportFailure(epoch uint64) error {
+	_, err := WriterDb.Exec("DELETE FROM export_failures WHERE epoch = $1", epoch)
+	return err
+}
+
+// RetryExportFailureNow will schedule the next retry of a failed epoch export to the current time
+func RetryExportFailureNow(epoch uint64) (bool, error) {
+	res, err := WriterDb.Exec("UPDATE export_failures SET next_retry_at = NOW() WHERE epoch = $1", epoch)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows > 0, err
+}
+
+// GetExportFailures will return all epochs that failed to export ordered by epoch
+func GetExportFailures() ([]*types.ExportFailure, error) {
+	failures := []*types.ExportFailure{}
+	err := ReaderDb.Select(&failures, "SELECT epoch, error, attempts, first_failed_at, last_failed_at, next_retry_at FROM export_failures ORDER BY epoch")
+	return failures, err
+}
+
+// GetExportFailuresAwaitingRetry will return the failed epochs whose next retry is still in the future
+func GetExportFailuresAwaitingRetry() (map[uint64]*types.ExportFailure, error) {
+	failures := []*types.ExportFailure{}
+	err := WriterDb.Select(&failures, "SELECT epoch, error, attempts, first_failed_at, last_failed_at, next_retry_at FROM export_failures WHERE next_retry_at > NOW()")
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving export failures: %v", err)
+	}
+
+	failuresMap := make(map[uint64]*types.ExportFailure, len(failures))
+	for _, failure := range failures {
+		failuresMap[failure.Epoch] = failure
+	}
+	return failuresMap, nil
+}
+
+// GetExportFailureCount will return the number of epochs that failed to export
+func GetExportFailureCount() (uint64, error) {
+	var count uint64
+	err := WriterDb.Get(&count, "SELECT COUNT(*) FROM export_failures")
+	return count, err
+}
+
 // GetTotalValidatorsCount will return the total-validator-count
 func GetTotalValidatorsCount() (uint64, error) {
 	var totalCount uint64
@@ -1997,7 +2220,7 @@ func GetTotalEligibleEther() (uint64, error) {
 	var total uint64
 
 	err := ReaderDb.Get(&total, `
-		SELECT eligibleether FROM epochs ORDER BY epoch desc LIMIT 1
+		SELECT eligibleether FROM epochs WHERE eligibleether IS NOT NULL ORDER BY epoch desc LIMIT 1
 	`)
 	if err == sql.ErrNoRows {
 		return 0, nil
@@ -2664,3 +2887,954 @@ func GetAllValidatorTotalWithdrawals(slot uint64) (map[uint64]uint64, error) {
 	}
 	return allValidatorBalances, err
 }
+
+// GetBlocksMissingBlobSidecars will return the proposed blocks from minSlot on that contain blobs whose sidecars have not been saved yet
+func GetBlocksMissingBlobSidecars(minSlot, limit uint64) ([]*types.MinimalBlock, error) {
+	blocks := []*types.MinimalBlock{}
+	err := WriterDb.Select(&blocks, `
+		SELECT blocks.epoch, blocks.slot, blocks.blockroot, blocks.parentroot
+		FROM blocks
+		WHERE blocks.slot >= $1 AND blocks.status = '1' AND blocks.blobcount > 0
+			AND (blocks.blob_sidecars_retry_at IS NULL OR blocks.blob_sidecars_retry_at <= NOW())
+			AND NOT EXISTS (SELECT 1 FROM blocks_blob_sidecars WHERE block_slot = blocks.slot AND block_root = blocks.blockroot)
+		ORDER BY blocks.slot
+		LIMIT $2`, minSlot, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving blocks missing blob sidecars: %v", err)
+	}
+	return blocks, nil
+}
+
+// SetBlobSidecarsRetry will delay the next request of the blob sidecars of a block with an exponential backoff starting
+// at baseBackoff and capped at maxBackoff
+func SetBlobSidecarsRetry(slot uint64, blockRoot []byte, baseBackoff, maxBackoff time.Duration) error {
+	_, err := WriterDb.Exec(`
+		UPDATE blocks SET
+			blob_sidecars_retry_at = NOW() + LEAST($3::float8 * power(2, blob_sidecars_attempts), $4::float8) * interval '1 second',
+			blob_sidecars_attempts = blob_sidecars_attempts + 1
+		WHERE slot = $1 AND blockroot = $2`, slot, blockRoot, baseBackoff.Seconds(), maxBackoff.Seconds())
+	if err != nil {
+		return fmt.Errorf("error delaying blob sidecars of block %#x: %v", blockRoot, err)
+	}
+	return nil
+}
+
+// SaveBlobSidecars will save the blob sidecars of a block
+func SaveBlobSidecars(sidecars []*types.BlobSidecar) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, sidecar := range sidecars {
+		_, err = tx.NamedExec(`
+			INSERT INTO blocks_blob_sidecars (block_slot, block_root, blob_index, kzg_commitment, kzg_proof, size)
+			VALUES (:block_slot, :block_root, :blob_index, :kzg_commitment, :kzg_proof, :size)
+			ON CONFLICT (block_slot, block_root, blob_index) DO NOTHING`, sidecar)
+		if err != nil {
+			return fmt.Errorf("error saving blob sidecar %v of block %#x: %v", sidecar.Index, sidecar.BlockRoot, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetEpochsMissingParticipation will return the epochs before maxEpoch whose participation is unknown, latest first
+func GetEpochsMissingParticipation(maxEpoch, limit uint64) ([]uint64, error) {
+	epochs := []uint64{}
+	err := WriterDb.Select(&epochs, `
+		SELECT epoch
+		FROM epochs
+		WHERE epoch < $1 AND globalparticipationrate IS NULL
+		ORDER BY epoch DESC
+		LIMIT $2`, maxEpoch, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving epochs missing participation: %v", err)
+	}
+	return epochs, nil
+}
+
+// GetEpochsMissingRewards will return the finalized epochs from minEpoch on whose validator rewards have not been exported yet, latest first.
+// Epochs whose export failed are left out until their next retry and for good after maxAttempts failed attempts.
+func GetEpochsMissingRewards(minEpoch, maxAttempts, limit uint64) ([]uint64, error) {
+	epochs := []uint64{}
+	err := WriterDb.Select(&epochs, `
+		SELECT epoch
+		FROM epochs
+		WHERE epoch >= $1 AND finalized AND NOT rewards_exported
+			AND rewards_attempts < $2 AND (rewards_retry_at IS NULL OR rewards_retry_at <= NOW())
+		ORDER BY epoch DESC
+		LIMIT $3`, minEpoch, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving epochs missing rewards: %v", err)
+	}
+	return epochs, nil
+}
+
+// SetRewardsRetry will delay the next export of the rewards of an epoch with an exponential backoff starting at
+// baseBackoff and capped at maxBackoff, it returns the number of failed attempts
+func SetRewardsRetry(epoch uint64, baseBackoff, maxBackoff time.Duration) (uint64, error) {
+	var attempts uint64
+	err := WriterDb.Get(&attempts, `
+		UPDATE epochs SET
+			rewards_retry_at = NOW() + LEAST($2::float8 * power(2, rewards_attempts), $3::float8) * interval '1 second',
+			rewards_attempts = rewards_attempts + 1
+		WHERE epoch = $1
+		RETURNING rewards_attempts`, epoch, baseBackoff.Seconds(), maxBackoff.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("error delaying rewards of epoch %v: %v", epoch, err)
+	}
+	return attempts, nil
+}
+
+// SaveValidatorRewards will save the rewards of all validators in an epoch and mark the rewards of the epoch as exported
+func SaveValidatorRewards(epoch uint64, rewards map[uint64]*types.ValidatorRewards) error {
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("db_save_validator_rewards").Observe(time.Since(start).Seconds())
+	}()
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	validatorRewards := make([]*types.ValidatorRewards, 0, len(rewards))
+	for _, r := range rewards {
+		validatorRewards = append(validatorRewards, r)
+	}
+
+	batchSize := 5000
+	nArgs := 12
+	for b := 0; b < len(validatorRewards); b += batchSize {
+		end := b + batchSize
+		if len(validatorRewards) < end {
+			end = len(validatorRewards)
+		}
+
+		valueStrings := make([]string, 0, batchSize)
+		valueArgs := make([]interface{}, 0, batchSize*nArgs)
+		for i, r := range validatorRewards[b:end] {
+			placeholders := make([]string, nArgs)
+			for j := range placeholders {
+				placeholders[j] = fmt.Sprintf("$%d", i*nArgs+j+1)
+			}
+			valueStrings = append(valueStrings, "("+strings.Join(placeholders, ", ")+")")
+			valueArgs = append(valueArgs, r.ValidatorIndex, epoch, r.Source, r.Target, r.Head, r.InclusionDelay, r.Inactivity, r.SyncCommittee, r.ProposerAttestations, r.ProposerSyncAggregate, r.ProposerSlashingInclusion, epoch/1575)
+		}
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO validator_rewards_p (validatorindex, epoch, source, target, head, inclusion_delay, inactivity, sync_committee, proposer_attestations, proposer_sync_aggregate, proposer_slashing_inclusion, week)
+			VALUES %s
+			ON CONFLICT (validatorindex, week, epoch) DO UPDATE SET
+				source                      = EXCLUDED.source,
+				target                      = EXCLUDED.target,
+				head                        = EXCLUDED.head,
+				inclusion_delay             = EXCLUDED.inclusion_delay,
+				inactivity                  = EXCLUDED.inactivity,
+				sync_committee              = EXCLUDED.sync_committee,
+				proposer_attestations       = EXCLUDED.proposer_attestations,
+				proposer_sync_aggregate     = EXCLUDED.proposer_sync_aggregate,
+				proposer_slashing_inclusion = EXCLUDED.proposer_slashing_inclusion`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return fmt.Errorf("error saving validator rewards of epoch %v: %v", epoch, err)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE epochs SET rewards_exported = true WHERE epoch = $1", epoch)
+	if err != nil {
+		return fmt.Errorf("error marking rewards of epoch %v as exported: %v", epoch, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetValidatorRewardsSum will return the sum of each reward component of a validator from startEpoch on
+func GetValidatorRewardsSum(validatorIndex, startEpoch uint64) (*types.ValidatorRewards, error) {
+	rewards := &types.ValidatorRewards{ValidatorIndex: validatorIndex, Epoch: startEpoch}
+	err := ReaderDb.Get(rewards, `
+		SELECT
+			COALESCE(SUM(source), 0) AS source,
+			COALESCE(SUM(target), 0) AS target,
+			COALESCE(SUM(head), 0) AS head,
+			COALESCE(SUM(inclusion_delay), 0) AS inclusion_delay,
+			COALESCE(SUM(inactivity), 0) AS inactivity,
+			COALESCE(SUM(sync_committee), 0) AS sync_committee,
+			COALESCE(SUM(proposer_attestations), 0) AS proposer_attestations,
+			COALESCE(SUM(proposer_sync_aggregate), 0) AS proposer_sync_aggregate,
+			COALESCE(SUM(proposer_slashing_inclusion), 0) AS proposer_slashing_inclusion
+		FROM validator_rewards_p
+		WHERE validatorindex = $1 AND week >= $2 / 1575 AND epoch >= $2`, validatorIndex, startEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving rewards of validator %v: %v", validatorIndex, err)
+	}
+	return rewards, nil
+}
+
+// GetValidatorsRewards will return the rewards of the validators from fromEpoch to toEpoch, both inclusive, latest epoch first
+func GetValidatorsRewards(validators []uint64, fromEpoch uint64, toEpoch uint64) ([]*types.ValidatorRewards, error) {
+	rewards := []*types.ValidatorRewards{}
+	err := ReaderDb.Select(&rewards, `
+		SELECT validatorindex, epoch, source, target, head, inclusion_delay, inactivity, sync_committee, proposer_attestations, proposer_sync_aggregate, proposer_slashing_inclusion
+		FROM validator_rewards_p
+		WHERE validatorindex = ANY($1) AND week >= $2 / 1575 AND week <= $3 / 1575 AND epoch >= $2 AND epoch <= $3
+		ORDER BY epoch DESC, validatorindex`, pq.Array(validators), fromEpoch, toEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting rewards for validators: %+v: %w", validators, err)
+	}
+	return rewards, nil
+}
+
+// GetBlocksMissingReceipts will return the latest proposed blocks with an execution payload whose transaction receipts have not been saved yet
+func GetBlocksMissingReceipts(limit uint64) ([]*types.ExecutionRewardsBlock, error) {
+	blocks := []*types.ExecutionRewardsBlock{}
+	err := WriterDb.Select(&blocks, `
+		SELECT
+			blocks.slot,
+			blocks.blockroot,
+			COALESCE(blocks.exec_base_fee_per_gas, 0) AS exec_base_fee_per_gas,
+			blocks.exec_transactions_count,
+			ARRAY(SELECT txhash FROM blocks_transactions WHERE block_slot = blocks.slot ORDER BY block_index) AS txhashes
+		FROM blocks
+		WHERE blocks.exec_priority_fees IS NULL AND blocks.exec_block_number IS NOT NULL AND blocks.status = '1'
+			AND (blocks.exec_receipts_retry_at IS NULL OR blocks.exec_receipts_retry_at <= NOW())
+		ORDER BY blocks.slot DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving blocks missing receipts: %v", err)
+	}
+	return blocks, nil
+}
+
+// SetBlockReceiptsRetry will delay the next export of the receipts of a block with an exponential backoff starting at
+// baseBackoff and capped at maxBackoff
+func SetBlockReceiptsRetry(slot uint64, blockRoot []byte, baseBackoff, maxBackoff time.Duration) error {
+	_, err := WriterDb.Exec(`
+		UPDATE blocks SET
+			exec_receipts_retry_at = NOW() + LEAST($3::float8 * power(2, exec_receipts_attempts), $4::float8) * interval '1 second',
+			exec_receipts_attempts = exec_receipts_attempts + 1
+		WHERE slot = $1 AND blockroot = $2`, slot, blockRoot, baseBackoff.Seconds(), maxBackoff.Seconds())
+	if err != nil {
+		return fmt.Errorf("error delaying receipts of block %#x: %v", blockRoot, err)
+	}
+	return nil
+}
+
+// SaveBlockReceipts will save the receipts and logs of the transactions of a block together with the sum of the
+// priority fees in wei that the fee recipient of the block received
+func SaveBlockReceipts(slot uint64, blockRoot []byte, receipts []*types.TransactionReceipt, priorityFees *big.Int) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, receipt := range receipts {
+		_, err = tx.NamedExec(`
+			INSERT INTO blocks_transactions_receipts (block_slot, block_index, block_root, txhash, status, gas_used, effective_gas_price, contract_address, logs_count)
+			VALUES (:block_slot, :block_index, :block_root, :txhash, :status, :gas_used, :effective_gas_price, :contract_address, :logs_count)
+			ON CONFLICT (block_slot, block_root, block_index) DO UPDATE SET
+				txhash              = EXCLUDED.txhash,
+				status              = EXCLUDED.status,
+				gas_used            = EXCLUDED.gas_used,
+				effective_gas_price = EXCLUDED.effective_gas_price,
+				contract_address    = EXCLUDED.contract_address,
+				logs_count          = EXCLUDED.logs_count`, receipt)
+		if err != nil {
+			return fmt.Errorf("error saving receipt of transaction %#x: %v", receipt.TxHash, err)
+		}
+
+		for _, log := range receipt.Logs {
+			_, err = tx.NamedExec(`
+				INSERT INTO blocks_transactions_logs (block_slot, block_root, log_index, block_index, txhash, address, topics, data)
+				VALUES (:block_slot, :block_root, :log_index, :block_index, :txhash, :address, :topics, :data)
+				ON CONFLICT (block_slot, block_root, log_index) DO UPDATE SET
+					block_index = EXCLUDED.block_index,
+					txhash      = EXCLUDED.txhash,
+					address     = EXCLUDED.address,
+					topics      = EXCLUDED.topics,
+					data        = EXCLUDED.data`, log)
+			if err != nil {
+				return fmt.Errorf("error saving log %v of transaction %#x: %v", log.LogIndex, receipt.TxHash, err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE blocks SET exec_priority_fees = $3 WHERE slot = $1 AND blockroot = $2`, slot, blockRoot, priorityFees.String())
+	if err != nil {
+		return fmt.Errorf("error saving execution rewards of block %#x at slot %v: %v", blockRoot, slot, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetExecutionTransaction will return the transaction with the given hash together with its receipt if it has
+// been exported, returns nil if the transaction is not known
+func GetExecutionTransaction(txHash []byte) (*types.TxPageData, error) {
+	txs := []*types.TxPageData{}
+	err := ReaderDb.Select(&txs, `
+		SELECT
+			blocks_transactions.block_slot,
+			blocks_transactions.block_index,
+			blocks.blockroot AS block_root,
+			blocks.status::int AS block_status,
+			COALESCE(blocks.exec_block_number, 0) AS exec_block_number,
+			COALESCE(blocks.exec_base_fee_per_gas, 0) AS exec_base_fee_per_gas,
+			blocks_transactions.txhash,
+			blocks_transactions.nonce,
+			blocks_transactions.gas_price,
+			blocks_transactions.gas_limit,
+			blocks_transactions.sender,
+			blocks_transactions.recipient,
+			blocks_transactions.amount,
+			blocks_transactions.payload,
+			blocks_transactions.max_priority_fee_per_gas,
+			blocks_transactions.max_fee_per_gas,
+			blocks_transactions_receipts.status AS receipt_status,
+			blocks_transactions_receipts.gas_used,
+			blocks_transactions_receipts.effective_gas_price,
+			blocks_transactions_receipts.contract_address,
+			blocks_transactions_receipts.logs_count
+		FROM blocks_transactions
+		INNER JOIN blocks ON blocks.slot = blocks_transactions.block_slot AND blocks.blockroot = blocks_transactions.block_root AND blocks.status IN ('1', '3')
+		LEFT JOIN blocks_transactions_receipts ON blocks_transactions_receipts.block_slot = blocks_transactions.block_slot AND blocks_transactions_receipts.block_root = blocks_transactions.block_root AND blocks_transactions_receipts.block_index = blocks_transactions.block_index
+		WHERE blocks_transactions.txhash = $1
+		ORDER BY blocks.status, blocks_transactions.block_slot DESC
+		LIMIT 1`, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving transaction %#x: %v", txHash, err)
+	}
+	if len(txs) == 0 {
+		return nil, nil
+	}
+	return txs[0], nil
+}
+
+// GetTransactionLogs will return the logs emitted by the transaction at the given position of the block with the given root
+func GetTransactionLogs(slot uint64, blockRoot []byte, blockIndex uint64) ([]*types.TransactionLog, error) {
+	logs := []*types.TransactionLog{}
+	err := ReaderDb.Select(&logs, `
+		SELECT block_slot, block_root, log_index, block_index, txhash, address, topics, data
+		FROM blocks_transactions_logs
+		WHERE block_slot = $1 AND block_root = $2 AND block_index = $3
+		ORDER BY log_index`, slot, blockRoot, blockIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving logs of transaction %v at slot %v: %v", blockIndex, slot, err)
+	}
+	return logs, nil
+}
+
+// GetAddressTransactions will return the latest transactions of canonical blocks that were sent or received by the address
+func GetAddressTransactions(address []byte, limit uint64) ([]*types.AddressPageTransaction, error) {
+	txs := []*types.AddressPageTransaction{}
+	err := ReaderDb.Select(&txs, `
+		SELECT
+			blocks_transactions.block_slot,
+			blocks_transactions.block_index,
+			blocks_transactions.txhash,
+			blocks_transactions.sender,
+			blocks_transactions.recipient,
+			blocks_transactions.amount,
+			blocks_transactions_receipts.status AS receipt_status,
+			blocks_transactions_receipts.contract_address
+		FROM blocks_transactions
+		INNER JOIN blocks ON blocks.slot = blocks_transactions.block_slot AND blocks.blockroot = blocks_transactions.block_root AND blocks.status = '1'
+		LEFT JOIN blocks_transactions_receipts ON blocks_transactions_receipts.block_slot = blocks_transactions.block_slot AND blocks_transactions_receipts.block_root = blocks_transactions.block_root AND blocks_transactions_receipts.block_index = blocks_transactions.block_index
+		WHERE blocks_transactions.sender = $1 OR blocks_transactions.recipient = $1
+		ORDER BY blocks_transactions.block_slot DESC, blocks_transactions.block_index DESC
+		LIMIT $2`, address, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving transactions of address %#x: %v", address, err)
+	}
+	return txs, nil
+}
+
+// GetAddressDeposits will return the latest eth1 deposits sent from the address
+func GetAddressDeposits(address []byte, limit uint64) ([]*types.Eth1Deposit, error) {
+	deposits := []*types.Eth1Deposit{}
+	err := ReaderDb.Select(&deposits, `
+		SELECT tx_hash, tx_index, block_number, EXTRACT(epoch FROM block_ts)::bigint AS block_ts, from_address, publickey, withdrawal_credentials, amount, valid_signature
+		FROM eth1_deposits
+		WHERE from_address = $1
+		ORDER BY block_number DESC, tx_index DESC
+		LIMIT $2`, address, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deposits of address %#x: %v", address, err)
+	}
+	return deposits, nil
+}
+
+// GetAddressFeeRecipientBlocks will return the latest proposed blocks whose fee recipient is the address
+func GetAddressFeeRecipientBlocks(address []byte, limit uint64) ([]*types.AddressPageBlock, error) {
+	blocks := []*types.AddressPageBlock{}
+	err := ReaderDb.Select(&blocks, `
+		SELECT slot, blockroot, proposer, COALESCE(exec_block_number, 0) AS exec_block_number, exec_priority_fees::text AS exec_priority_fees
+		FROM blocks
+		WHERE exec_fee_recipient = $1 AND status = '1'
+		ORDER BY slot DESC
+		LIMIT $2`, address, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving fee recipient blocks of address %#x: %v", address, err)
+	}
+	return blocks, nil
+}
+
+// GetAddressValidators will return the validators whose 0x01 withdrawal credentials point to the address
+func GetAddressValidators(address []byte, limit uint64) ([]*types.AddressPageValidator, error) {
+	credentials := append([]byte{0x01}, make([]byte, 11)...)
+	credentials = append(credentials, address...)
+
+	validators := []*types.AddressPageValidator{}
+	err := ReaderDb.Select(&validators, `
+		SELECT validatorindex, pubkey, balance, status
+		FROM validators
+		WHERE withdrawalcredentials = $1
+		ORDER BY validatorindex
+		LIMIT $2`, credentials, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators of address %#x: %v", address, err)
+	}
+	return validators, nil
+}
+
+// GetValidatorsWithdrawalCredentials will return the current withdrawal credentials of the validators
+func GetValidatorsWithdrawalCredentials(validators []uint64) (map[uint64][]byte, error) {
+	rows := []struct {
+		Validatorindex        uint64 `db:"validatorindex"`
+		WithdrawalCredentials []byte `db:"withdrawalcredentials"`
+	}{}
+	err := WriterDb.Select(&rows, `SELECT validatorindex, withdrawalcredentials FROM validators WHERE validatorindex = ANY($1)`, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving withdrawal credentials of validators: %v", err)
+	}
+
+	credentials := make(map[uint64][]byte, len(rows))
+	for _, row := range rows {
+		credentials[row.Validatorindex] = row.WithdrawalCredentials
+	}
+	return credentials, nil
+}
+
+// SaveBLSChangePool will replace the saved bls to execution change pool with the changes, the time a change was first
+// seen is kept for changes that are still in the pool
+func SaveBLSChangePool(changes []*types.PendingBLSChange) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	indices := make([]uint64, 0, len(changes))
+	for _, change := range changes {
+		indices = append(indices, change.Validatorindex)
+	}
+	// changes leave the pool once they are included in a block or dropped by the node
+	_, err = tx.Exec(`DELETE FROM bls_change_pool WHERE NOT validatorindex = ANY($1)`, pq.Array(indices))
+	if err != nil {
+		return fmt.Errorf("error deleting bls to execution changes that left the pool: %v", err)
+	}
+
+	for _, change := range changes {
+		_, err = tx.NamedExec(`
+			INSERT INTO bls_change_pool (validatorindex, signature, pubkey, address, valid_signature, pubkey_matches, first_seen)
+			VALUES (:validatorindex, :signature, :pubkey, :address, :valid_signature, :pubkey_matches, :first_seen)
+			ON CONFLICT (validatorindex) DO UPDATE SET
+				signature       = EXCLUDED.signature,
+				pubkey          = EXCLUDED.pubkey,
+				address         = EXCLUDED.address,
+				valid_signature = EXCLUDED.valid_signature,
+				pubkey_matches  = EXCLUDED.pubkey_matches`, change)
+		if err != nil {
+			return fmt.Errorf("error saving bls to execution change of validator %v: %v", change.Validatorindex, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPendingBLSChanges will return the bls to execution changes of the validators that are waiting in the operation pool
+func GetPendingBLSChanges(validators []uint64) ([]*types.PendingBLSChange, error) {
+	changes := []*types.PendingBLSChange{}
+	err := ReaderDb.Select(&changes, `
+		SELECT validatorindex, pubkey, address, signature, valid_signature, pubkey_matches, first_seen
+		FROM bls_change_pool
+		WHERE validatorindex = ANY($1)
+		ORDER BY validatorindex`, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending bls to execution changes: %v", err)
+	}
+	return changes, nil
+}
+
+// GetWithdrawalCredentialsStatus will return the withdrawal credentials of the validators together with the slot their
+// bls to execution change was included at and their pending change if there is one
+func GetWithdrawalCredentialsStatus(validators []uint64) ([]*types.WithdrawalCredentialsStatus, error) {
+	status := []*types.WithdrawalCredentialsStatus{}
+	err := ReaderDb.Select(&status, `
+		SELECT
+			validators.validatorindex,
+			validators.withdrawalcredentials,
+			COALESCE((
+				SELECT MAX(bls.block_slot)
+				FROM blocks_bls_change bls
+				INNER JOIN blocks b ON b.blockroot = bls.block_root AND b.status = '1'
+				WHERE bls.validatorindex = validators.validatorindex
+			), 0) AS included_slot
+		FROM validators
+		WHERE validators.validatorindex = ANY($1)
+		ORDER BY validators.validatorindex`, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving withdrawal credentials status of validators: %v", err)
+	}
+
+	pending, err := GetPendingBLSChanges(validators)
+	if err != nil {
+		return nil, err
+	}
+	pendingByIndex := make(map[uint64]*types.PendingBLSChange, len(pending))
+	for _, change := range pending {
+		pendingByIndex[change.Validatorindex] = change
+	}
+	for _, s := range status {
+		s.PendingChange = pendingByIndex[s.Validatorindex]
+	}
+	return status, nil
+}
+
+// GetWithdrawalSweepValidators will return the state of all validators that is relevant for the withdrawal sweep, ordered by index
+func GetWithdrawalSweepValidators() ([]*types.WithdrawalSweepValidator, error) {
+	validators := []*types.WithdrawalSweepValidator{}
+	err := ReaderDb.Select(&validators, `
+		SELECT
+			v.validatorindex,
+			v.withdrawalcredentials,
+			v.withdrawableepoch,
+			v.effectivebalance,
+			v.balance,
+			COALESCE(p.performance7d, 0) AS performance7d
+		FROM validators v
+		LEFT JOIN validator_performance p ON p.validatorindex = v.validatorindex
+		ORDER BY v.validatorindex`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators for the withdrawal sweep: %v", err)
+	}
+	return validators, nil
+}
+
+// GetWithdrawalSweepPosition will return the latest canonical block containing withdrawals, returns nil if there is none
+func GetWithdrawalSweepPosition() (*types.WithdrawalSweepPosition, error) {
+	position := &types.WithdrawalSweepPosition{}
+	err := ReaderDb.Get(position, `
+		WITH latest AS (
+			SELECT w.block_slot AS slot, w.block_root
+			FROM blocks_withdrawals w
+			INNER JOIN blocks b ON b.blockroot = w.block_root AND b.status = '1'
+			ORDER BY w.block_slot DESC
+			LIMIT 1
+		)
+		SELECT
+			latest.slot,
+			(SELECT w.validatorindex FROM blocks_withdrawals w WHERE w.block_root = latest.block_root ORDER BY w.withdrawalindex LIMIT 1) AS first_validatorindex,
+			(SELECT w.validatorindex FROM blocks_withdrawals w WHERE w.block_root = latest.block_root ORDER BY w.withdrawalindex DESC LIMIT 1) AS last_validatorindex,
+			(SELECT COUNT(*) FROM blocks_withdrawals w WHERE w.block_root = latest.block_root) AS count,
+			(SELECT COUNT(*) FROM blocks b WHERE b.slot > latest.slot AND b.status = '1' AND b.exec_block_hash IS NOT NULL) AS blocks_since,
+			(SELECT MAX(b.slot) FROM blocks b) AS latest_slot
+		FROM latest`)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving position of the withdrawal sweep: %v", err)
+	}
+	return position, nil
+}
+
+// SaveWithdrawalPredictions will replace the predicted withdrawals of all validators
+func SaveWithdrawalPredictions(predictions []*types.WithdrawalPrediction) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM withdrawal_predictions`)
+	if err != nil {
+		return fmt.Errorf("error deleting withdrawal predictions: %v", err)
+	}
+
+	batchSize := 5000 // max parameters: 65535
+	for b := 0; b < len(predictions); b += batchSize {
+		start := b
+		end := b + batchSize
+		if len(predictions) < end {
+			end = len(predictions)
+		}
+
+		numArgs := 7
+		valueStrings := make([]string, 0, batchSize)
+		valueArgs := make([]interface{}, 0, batchSize*numArgs)
+		for i, p := range predictions[start:end] {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*numArgs+1, i*numArgs+2, i*numArgs+3, i*numArgs+4, i*numArgs+5, i*numArgs+6, i*numArgs+7))
+			valueArgs = append(valueArgs, p.Validatorindex, p.Address, p.Slot, p.Amount, p.IsFull, p.IncomePerSlot, p.CycleSlots)
+		}
+		stmt := fmt.Sprintf(`
+			INSERT INTO withdrawal_predictions (validatorindex, address, slot, amount, is_full, income_per_slot, cycle_slots)
+			VALUES %s`, strings.Join(valueStrings, ","))
+		_, err = tx.Exec(stmt, valueArgs...)
+		if err != nil {
+			return fmt.Errorf("error saving withdrawal predictions: %v", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetWithdrawalPredictions will return the predicted withdrawals of the validators that happen after the given slot
+func GetWithdrawalPredictions(validators []uint64, afterSlot uint64) ([]*types.WithdrawalPrediction, error) {
+	predictions := []*types.WithdrawalPrediction{}
+	err := ReaderDb.Select(&predictions, `
+		SELECT validatorindex, address, slot, amount, is_full, income_per_slot, cycle_slots
+		FROM withdrawal_predictions
+		WHERE validatorindex = ANY($1) AND slot > $2
+		ORDER BY validatorindex`, pq.Array(validators), afterSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving withdrawal predictions: %v", err)
+	}
+	return predictions, nil
+}
+
+// GetAddressWithdrawalPredictions will return the predicted withdrawals of the validators that withdraw to the address
+func GetAddressWithdrawalPredictions(address []byte) ([]*types.WithdrawalPrediction, error) {
+	predictions := []*types.WithdrawalPrediction{}
+	err := ReaderDb.Select(&predictions, `
+		SELECT validatorindex, address, slot, amount, is_full, income_per_slot, cycle_slots
+		FROM withdrawal_predictions
+		WHERE address = $1
+		ORDER BY slot`, address)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving withdrawal predictions of address %#x: %v", address, err)
+	}
+	return predictions, nil
+}
+
+// GetSyncCommitteePerformance will return the performance of the members of the sync committee of a period together
+// with their sync committee rewards, if validators is empty all members of the committee are returned
+func GetSyncCommitteePerformance(period uint64, validators []uint64) ([]*types.SyncCommitteePerformance, error) {
+	firstEpoch := utils.FirstEpochOfSyncPeriod(period)
+	lastEpoch := utils.FirstEpochOfSyncPeriod(period+1) - 1
+	firstSlot := firstEpoch * utils.Config.Chain.Config.SlotsPerEpoch
+	lastSlot := (lastEpoch+1)*utils.Config.Chain.Config.SlotsPerEpoch - 1
+
+	if validators == nil {
+		validators = []uint64{}
+	}
+
+	performance := []*types.SyncCommitteePerformance{}
+	err := ReaderDb.Select(&performance, `
+		SELECT
+			sc.period,
+			sc.validatorindex,
+			COALESCE(SUM(CASE WHEN sa.status = 1 THEN 1 ELSE 0 END), 0) AS participated,
+			COALESCE(SUM(CASE WHEN sa.status = 2 THEN 1 ELSE 0 END), 0) AS missed_own,
+			COALESCE(SUM(CASE WHEN sa.status = 3 THEN 1 ELSE 0 END), 0) AS missed_empty_slot,
+			COALESCE(SUM(CASE WHEN sa.status = 0 THEN 1 ELSE 0 END), 0) AS scheduled
+		FROM (SELECT DISTINCT period, validatorindex FROM sync_committees WHERE period = $1 AND (cardinality($2::int[]) = 0 OR validatorindex = ANY($2))) sc
+		LEFT JOIN sync_assignments_p sa ON sa.validatorindex = sc.validatorindex AND sa.week >= $3 AND sa.week <= $4 AND sa.slot >= $5 AND sa.slot <= $6
+		GROUP BY sc.period, sc.validatorindex
+		ORDER BY sc.validatorindex`,
+		period, pq.Array(validators), utils.WeekOfSlot(firstSlot), utils.WeekOfSlot(lastSlot), firstSlot, lastSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync committee performance of period %v: %v", period, err)
+	}
+	if len(performance) == 0 {
+		return performance, nil
+	}
+
+	members := make([]uint64, 0, len(performance))
+	for _, p := range performance {
+		members = append(members, p.Validatorindex)
+	}
+	rewards := []struct {
+		Validatorindex uint64 `db:"validatorindex"`
+		Rewards        int64  `db:"rewards"`
+		Penalties      int64  `db:"penalties"`
+	}{}
+	err = ReaderDb.Select(&rewards, `
+		SELECT
+			validatorindex,
+			COALESCE(SUM(GREATEST(sync_committee, 0)), 0) AS rewards,
+			COALESCE(SUM(LEAST(sync_committee, 0)), 0) AS penalties
+		FROM validator_rewards_p
+		WHERE week >= $1 AND week <= $2 AND epoch >= $3 AND epoch <= $4 AND validatorindex = ANY($5)
+		GROUP BY validatorindex`,
+		firstEpoch/1575, lastEpoch/1575, firstEpoch, lastEpoch, pq.Array(members))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync committee rewards of period %v: %v", period, err)
+	}
+
+	rewardsByIndex := make(map[uint64]int, len(rewards))
+	for i, r := range rewards {
+		rewardsByIndex[r.Validatorindex] = i
+	}
+	for _, p := range performance {
+		if i, ok := rewardsByIndex[p.Validatorindex]; ok {
+			p.Rewards = rewards[i].Rewards
+			p.Penalties = rewards[i].Penalties
+		}
+	}
+	return performance, nil
+}
+
+// GetValidatorSyncCommitteePeriods will return the sync committee periods a validator is a member of, latest first
+func GetValidatorSyncCommitteePeriods(validatorindex uint64) ([]uint64, error) {
+	periods := []uint64{}
+	err := ReaderDb.Select(&periods, `SELECT DISTINCT period FROM sync_committees WHERE validatorindex = $1 ORDER BY period DESC`, validatorindex)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync committee periods of validator %v: %v", validatorindex, err)
+	}
+	return periods, nil
+}
+
+// GetEpochsMissingAttestationEvaluation will return the finalized epochs whose attestations have not been evaluated yet, latest first.
+// The latest finalized epoch is skipped as its attestations can still be included in the following epoch
+func GetEpochsMissingAttestationEvaluation(limit uint64) ([]uint64, error) {
+	epochs := []uint64{}
+	err := WriterDb.Select(&epochs, `
+		SELECT epoch
+		FROM epochs
+		WHERE finalized AND NOT attestations_evaluated AND epoch < (SELECT COALESCE(MAX(epoch), 0) FROM epochs WHERE finalized)
+		ORDER BY epoch DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving epochs missing attestation evaluation: %v", err)
+	}
+	return epochs, nil
+}
+
+// GetIncludedAttestations will return the attestations of an epoch that are included in canonical blocks, ordered by inclusion.
+// Attestations can be included until the end of the next epoch, limiting the block slots to that window lets the query
+// use the primary key of blocks_attestations.
+func GetIncludedAttestations(epoch uint64) ([]*types.IncludedAttestation, error) {
+	attestations := []*types.IncludedAttestation{}
+	err := WriterDb.Select(&attestations, `
+		SELECT ba.block_slot, ba.validators, ba.slot, ba.beaconblockroot, ba.source_epoch, ba.source_root, ba.target_epoch, ba.target_root
+		FROM blocks_attestations ba
+		INNER JOIN blocks b ON b.slot = ba.block_slot AND b.blockroot = ba.block_root AND b.status = '1'
+		WHERE ba.block_slot >= $1 AND ba.block_slot < $3 AND ba.slot >= $1 AND ba.slot < $2
+		ORDER BY ba.block_slot, ba.block_index`,
+		epoch*utils.Config.Chain.Config.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.Config.SlotsPerEpoch, (epoch+2)*utils.Config.Chain.Config.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving included attestations of epoch %v: %v", epoch, err)
+	}
+	return attestations, nil
+}
+
+// GetCanonicalBlockRoots will return the roots of the canonical blocks between fromSlot and toSlot ordered by slot,
+// including the latest canonical block before fromSlot so that the block root at every slot of the range is known
+func GetCanonicalBlockRoots(fromSlot, toSlot uint64) ([]*types.CanonicalBlockRoot, error) {
+	roots := []*types.CanonicalBlockRoot{}
+	err := WriterDb.Select(&roots, `
+		SELECT slot, blockroot
+		FROM blocks
+		WHERE status = '1' AND slot <= $2 AND slot >= (SELECT COALESCE(MAX(slot), 0) FROM blocks WHERE status = '1' AND slot <= $1)
+		ORDER BY slot`, fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving canonical block roots from slot %v to %v: %v", fromSlot, toSlot, err)
+	}
+	return roots, nil
+}
+
+// SaveAttestationEvaluations will save the evaluations of the attestations of an epoch and mark the epoch as evaluated,
+// the statistics of the day of the epoch are updated if they have already been exported
+func SaveAttestationEvaluations(epoch uint64, evaluations []*types.AttestationEvaluation) error {
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("db_save_attestation_evaluations").Observe(time.Since(start).Seconds())
+	}()
+
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	validators := make([]int64, len(evaluations))
+	inclusionDelays := make([]int64, len(evaluations))
+	head := make([]bool, len(evaluations))
+	target := make([]bool, len(evaluations))
+	source := make([]bool, len(evaluations))
+	for i, e := range evaluations {
+		validators[i] = int64(e.Validatorindex)
+		inclusionDelays[i] = int64(e.InclusionDelay)
+		head[i] = e.HeadCorrect
+		target[i] = e.TargetCorrect
+		source[i] = e.SourceCorrect
+	}
+
+	_, err = tx.Exec(`
+		UPDATE attestation_assignments_p aa SET
+			inclusion_delay = e.inclusion_delay,
+			head_correct    = e.head_correct,
+			target_correct  = e.target_correct,
+			source_correct  = e.source_correct
+		FROM (
+			SELECT
+				UNNEST($3::int[])  AS validatorindex,
+				UNNEST($4::int[])  AS inclusion_delay,
+				UNNEST($5::bool[]) AS head_correct,
+				UNNEST($6::bool[]) AS target_correct,
+				UNNEST($7::bool[]) AS source_correct
+		) e
+		WHERE aa.week = $1 AND aa.epoch = $2 AND aa.validatorindex = e.validatorindex`,
+		epoch/1575, epoch, pq.Array(validators), pq.Array(inclusionDelays), pq.Array(head), pq.Array(target), pq.Array(source))
+	if err != nil {
+		return fmt.Errorf("error saving attestation evaluations of epoch %v: %v", epoch, err)
+	}
+
+	_, err = tx.Exec("UPDATE epochs SET attestations_evaluated = true WHERE epoch = $1", epoch)
+	if err != nil {
+		return fmt.Errorf("error marking attestations of epoch %v as evaluated: %v", epoch, err)
+	}
+
+	epochsPerDay := (24 * 60 * 60) / utils.Config.Chain.Config.SlotsPerEpoch / utils.Config.Chain.Config.SecondsPerSlot
+	day := epoch / epochsPerDay
+	var statsExported bool
+	err = tx.QueryRow(`SELECT COALESCE((SELECT status FROM validator_stats_status WHERE day = $1), false)`, day).Scan(&statsExported)
+	if err != nil {
+		return fmt.Errorf("error retrieving statistics status of day %v: %v", day, err)
+	}
+	if statsExported {
+		err = writeAttestationCorrectnessStatistics(tx, day*epochsPerDay, (day+1)*epochsPerDay-1, day)
+		if err != nil {
+			return fmt.Errorf("error updating attestation statistics of day %v: %v", day, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAttestationCorrectness will return the aggregated evaluations of the attestations of the validators from startEpoch on
+func GetAttestationCorrectness(validators []uint64, startEpoch uint64) ([]*types.AttestationCorrectness, error) {
+	correctness := []*types.AttestationCorrectness{}
+	err := ReaderDb.Select(&correctness, `
+		SELECT
+			validatorindex,
+			COUNT(inclusion_delay) AS included,
+			COALESCE(SUM(inclusion_delay), 0) AS inclusion_delay_sum,
+			COALESCE(SUM(CASE WHEN head_correct THEN 1 ELSE 0 END), 0) AS correct_head,
+			COALESCE(SUM(CASE WHEN target_correct THEN 1 ELSE 0 END), 0) AS correct_target,
+			COALESCE(SUM(CASE WHEN source_correct THEN 1 ELSE 0 END), 0) AS correct_source
+		FROM attestation_assignments_p
+		WHERE week >= $1 / 1575 AND epoch >= $1 AND validatorindex = ANY($2) AND inclusion_delay IS NOT NULL
+		GROUP BY validatorindex
+		ORDER BY validatorindex`, startEpoch, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestation correctness of validators: %v", err)
+	}
+	return correctness, nil
+}
+
+// GetAttestationCorrectnessStatistics will return the aggregated evaluations of all attestations of the validators from the daily statistics
+func GetAttestationCorrectnessStatistics(validators []uint64) ([]*types.AttestationCorrectness, error) {
+	correctness := []*types.AttestationCorrectness{}
+	err := ReaderDb.Select(&correctness, `
+		SELECT
+			validatorindex,
+			COALESCE(SUM(included_attestations), 0) AS included,
+			COALESCE(SUM(inclusion_delay_sum), 0) AS inclusion_delay_sum,
+			COALESCE(SUM(correct_head_attestations), 0) AS correct_head,
+			COALESCE(SUM(correct_target_attestations), 0) AS correct_target,
+			COALESCE(SUM(correct_source_attestations), 0) AS correct_source
+		FROM validator_stats
+		WHERE validatorindex = ANY($1)
+		GROUP BY validatorindex
+		ORDER BY validatorindex`, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestation correctness statistics of validators: %v", err)
+	}
+	return correctness, nil
+}
+
+// SaveProposerDuties will save the proposer duties of an epoch, duties of slots that were already saved are replaced
+// as they change if the dependent block is reorged
+func SaveProposerDuties(epoch uint64, duties []*types.ProposerDuty) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, duty := range duties {
+		_, err = tx.Exec(`
+			INSERT INTO proposer_duties (slot, epoch, validatorindex, dependent_root)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (slot) DO UPDATE SET
+				epoch          = EXCLUDED.epoch,
+				validatorindex = EXCLUDED.validatorindex,
+				dependent_root = EXCLUDED.dependent_root`,
+			duty.Slot, epoch, duty.Validatorindex, duty.DependentRoot)
+		if err != nil {
+			return fmt.Errorf("error saving proposer duty of slot %v: %v", duty.Slot, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteProposerDutiesBefore will delete the proposer duties of all epochs before the given epoch
+func DeleteProposerDutiesBefore(epoch uint64) error {
+	_, err := WriterDb.Exec(`DELETE FROM proposer_duties WHERE epoch < $1`, epoch)
+	if err != nil {
+		return fmt.Errorf("error deleting proposer duties before epoch %v: %v", epoch, err)
+	}
+	return nil
+}
+
+// GetUpcomingProposerDuties will return the proposer duties of the validators from the given slot on, ordered by slot
+func GetUpcomingProposerDuties(validators []uint64, fromSlot uint64) ([]*types.ProposerDuty, error) {
+	duties := []*types.ProposerDuty{}
+	err := ReaderDb.Select(&duties, `
+		SELECT slot, validatorindex, dependent_root
+		FROM proposer_duties
+		WHERE slot >= $1 AND validatorindex = ANY($2)
+		ORDER BY slot`, fromSlot, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving upcoming proposer duties of validators: %v", err)
+	}
+	return duties, nil
+}
+
+// GetUpcomingSyncCommitteeDuties will return the sync committee memberships of the validators from the given period on,
+// the committee of the next period is known one period in advance
+func GetUpcomingSyncCommitteeDuties(validators []uint64, fromPeriod uint64) ([]*types.SyncCommitteeDuty, error) {
+	duties := []*types.SyncCommitteeDuty{}
+	err := ReaderDb.Select(&duties, `
+		SELECT DISTINCT period, validatorindex
+		FROM sync_committees
+		WHERE period >= $1 AND validatorindex = ANY($2)
+		ORDER BY period, validatorindex`, fromPeriod, pq.Array(validators))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving upcoming sync committee duties of validators: %v", err)
+	}
+	return duties, nil
+}
+
+// GetValidatorIndicesByPubkeys will return the indices of the validators with the public keys, indexed by hex encoded public key
+func GetValidatorIndicesByPubkeys(pubkeys [][]byte) (map[string]uint64, error) {
+	indices := make(map[string]uint64, len(pubkeys))
+	if len(pubkeys) == 0 {
+		return indices, nil
+	}
+
+	var rows []struct {
+		Validatorindex uint64 `db:"validatorindex"`
+		Pubkey         []byte `db:"pubkey"`
+	}
+	err := ReaderDb.Select(&rows, "SELECT validatorindex, pubkey FROM validators WHERE pubkey = ANY($1)", pq.ByteaArray(pubkeys))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator indices: %v", err)
+	}
+	for _, row := range rows {
+		indices[hex.EncodeToString(row.Pubkey)] = row.Validatorindex
+	}
+	return indices, nil
+}
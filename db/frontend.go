@@ -261,7 +261,7 @@ func AddSubscription(userID uint64, network string, eventName types.EventName, e
 	nowEpoch := utils.TimeToEpoch(now)
 
 	var onConflictDo string = "NOTHING"
-	if strings.HasPrefix(string(eventName), "monitoring_") || eventName == types.RocketpoolColleteralMaxReached || eventName == types.RocketpoolColleteralMinReached {
+	if strings.HasPrefix(string(eventName), "monitoring_") || eventName == types.RocketpoolColleteralMaxReached || eventName == types.RocketpoolColleteralMinReached || eventName == types.ValidatorWithdrawalEventName {
 		onConflictDo = "UPDATE SET event_threshold = $6"
 	}
 
@@ -888,3 +888,148 @@ func GetDataTablesState(user uint64, key string) (*types.DataTableSaveState, err
 
 	return &state, err
 }
+
+// GetUserNotificationSettings will return the delivery preferences of the users indexed by user id, users that never
+// changed their preferences are missing
+func GetUserNotificationSettings(userIDs []uint64) (map[uint64]*types.UserNotificationSettings, error) {
+	settingsByUserID := make(map[uint64]*types.UserNotificationSettings)
+	if len(userIDs) == 0 {
+		return settingsByUserID, nil
+	}
+
+	var settings []*types.UserNotificationSettings
+	err := FrontendWriterDB.Select(&settings, `
+		SELECT user_id, delivery, timezone, quiet_hours_start, quiet_hours_end, last_digest
+		FROM users_notification_settings
+		WHERE user_id = ANY($1)`, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving notification settings: %v", err)
+	}
+	for _, s := range settings {
+		settingsByUserID[s.UserID] = s
+	}
+	return settingsByUserID, nil
+}
+
+// GetDigestNotificationSettings will return the delivery preferences of all users that receive digests
+func GetDigestNotificationSettings() ([]*types.UserNotificationSettings, error) {
+	var settings []*types.UserNotificationSettings
+	err := FrontendWriterDB.Select(&settings, `
+		SELECT user_id, delivery, timezone, quiet_hours_start, quiet_hours_end, last_digest
+		FROM users_notification_settings
+		WHERE delivery != $1`, types.ImmediateNotificationDelivery)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving digest notification settings: %v", err)
+	}
+	return settings, nil
+}
+
+// SaveUserNotificationSettings will store the delivery preferences of a user
+func SaveUserNotificationSettings(settings *types.UserNotificationSettings) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_notification_settings (user_id, delivery, timezone, quiet_hours_start, quiet_hours_end)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			delivery = excluded.delivery,
+			timezone = excluded.timezone,
+			quiet_hours_start = excluded.quiet_hours_start,
+			quiet_hours_end = excluded.quiet_hours_end`,
+		settings.UserID, settings.Delivery, settings.Timezone, settings.QuietHoursStart, settings.QuietHoursEnd)
+	if err != nil {
+		return fmt.Errorf("error saving notification settings of user %v: %v", settings.UserID, err)
+	}
+	return nil
+}
+
+// AddNotificationDigestItems will collect notifications of a user for the next digest
+func AddNotificationDigestItems(userID uint64, notifications []types.Notification, useDB *sqlx.DB) error {
+	batchSize := 5000
+	for start := 0; start < len(notifications); start += batchSize {
+		end := start + batchSize
+		if end > len(notifications) {
+			end = len(notifications)
+		}
+
+		valueStrings := make([]string, 0, end-start)
+		valueArgs := make([]interface{}, 0, (end-start)*3+1)
+		valueArgs = append(valueArgs, userID)
+		for i, n := range notifications[start:end] {
+			valueStrings = append(valueStrings, fmt.Sprintf("($1, $%d, $%d, $%d, now())", i*3+2, i*3+3, i*3+4))
+			valueArgs = append(valueArgs, n.GetEventName(), n.GetEventFilter(), n.GetEpoch())
+		}
+		_, err := useDB.Exec(fmt.Sprintf(`
+			INSERT INTO users_notification_digest_items (user_id, event_name, event_filter, epoch, created)
+			VALUES %s`, strings.Join(valueStrings, ",")), valueArgs...)
+		if err != nil {
+			return fmt.Errorf("error adding notification digest items of user %v: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// GetNotificationDigestItems will return the notifications collected for the digest of a user up to the item with the
+// given id, aggregated per event and filter
+func GetNotificationDigestItems(userID, maxID uint64, q sqlx.Queryer) ([]*types.NotificationDigestItem, error) {
+	var items []*types.NotificationDigestItem
+	err := sqlx.Select(q, &items, `
+		SELECT event_name, event_filter, count(*) AS count, min(epoch) AS first_epoch, max(epoch) AS last_epoch
+		FROM users_notification_digest_items
+		WHERE user_id = $1 AND id <= $2
+		GROUP BY event_name, event_filter
+		ORDER BY event_name, count(*) DESC, event_filter`, userID, maxID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving notification digest items of user %v: %v", userID, err)
+	}
+	return items, nil
+}
+
+// telegramLinkTokenValidity is the time a user has to send a link token to the telegram bot
+const telegramLinkTokenValidity = time.Hour * 24
+
+// GetTelegramLinkToken will return the token a user sends to the telegram bot to link a chat, a new token is created if
+// the user has none or it expired
+func GetTelegramLinkToken(userID uint64) (string, error) {
+	var token string
+	err := FrontendWriterDB.Get(&token, `
+		INSERT INTO users_telegram_link_tokens (user_id, token, created) VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			token = CASE WHEN users_telegram_link_tokens.created < NOW() - $3::float8 * interval '1 second' THEN excluded.token ELSE users_telegram_link_tokens.token END,
+			created = CASE WHEN users_telegram_link_tokens.created < NOW() - $3::float8 * interval '1 second' THEN excluded.created ELSE users_telegram_link_tokens.created END
+		RETURNING token`, userID, utils.RandomString(32), telegramLinkTokenValidity.Seconds())
+	if err != nil {
+		return "", fmt.Errorf("error retrieving telegram link token of user %v: %v", userID, err)
+	}
+	return token, nil
+}
+
+// LinkTelegramChat will make the chat the telegram destination of the user the token belongs to and activate the
+// channel. A token can only be used once, the returned user id is 0 if the token is unknown or expired.
+func LinkTelegramChat(token, chatID string) (uint64, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var userID uint64
+	err = tx.Get(&userID, `
+		DELETE FROM users_telegram_link_tokens
+		WHERE token = $1 AND created >= NOW() - $2::float8 * interval '1 second'
+		RETURNING user_id`, token, telegramLinkTokenValidity.Seconds())
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving telegram link token: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO users_notification_channels (user_id, channel, active, destination) VALUES ($1, $2, true, $3)
+		ON CONFLICT (user_id, channel) DO UPDATE SET
+			active = excluded.active,
+			destination = excluded.destination`, userID, types.TelegramNotificationChannel, chatID)
+	if err != nil {
+		return 0, fmt.Errorf("error linking telegram chat of user %v: %v", userID, err)
+	}
+	return userID, tx.Commit()
+}
@@ -0,0 +1,262 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationsLockID is the id of the postgres advisory lock held while migrations are applied
+const migrationsLockID = 4711
+
+var migrationFileRE = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change of the db schema
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus holds a migration together with the time it was applied, AppliedAt is nil for pending migrations
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// loadMigrations will read all embedded migrations ordered by version
+func loadMigrations() ([]*Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+
+	migrationsByVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRE.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("error invalid migration file name %v", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing version of migration %v: %v", entry.Name(), err)
+		}
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %v: %v", entry.Name(), err)
+		}
+
+		migration, found := migrationsByVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrationsByVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("error migrations %v_%v and %v share the same version", version, migration.Name, entry.Name())
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("error migration %v_%v has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// lockMigrations will create the schema_migrations table if needed and acquire the migrations lock,
+// so that only one process applies migrations at a time. The returned function releases the lock.
+func lockMigrations(db *sqlx.DB) (func(), error) {
+	conn, err := db.Connx(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring db connection: %v", err)
+	}
+	_, err = conn.ExecContext(context.Background(), "SELECT pg_advisory_lock($1)", migrationsLockID)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error acquiring migrations lock: %v", err)
+	}
+	unlock := func() {
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID)
+		if err != nil {
+			logger.Errorf("error releasing migrations lock: %v", err)
+		}
+		conn.Close()
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    int                         not null,
+			name       varchar(200)                not null,
+			applied_at timestamp without time zone not null,
+			primary key (version)
+		)`)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("error creating schema_migrations table: %v", err)
+	}
+	return unlock, nil
+}
+
+// appliedMigrations will return the time every applied migration was applied at, indexed by version
+func appliedMigrations(db *sqlx.DB) (map[uint64]time.Time, error) {
+	rows := []struct {
+		Version   uint64    `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}{}
+	err := db.Select(&rows, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving applied migrations: %v", err)
+	}
+
+	applied := make(map[uint64]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// MigrateUp will apply all pending migrations in order, every migration runs in its own transaction
+func MigrateUp(db *sqlx.DB) (int, error) {
+	unlock, err := lockMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range migrations {
+		if _, found := applied[migration.Version]; found {
+			continue
+		}
+
+		logger.Infof("applying migration %v_%v", migration.Version, migration.Name)
+		tx, err := db.Beginx()
+		if err != nil {
+			return count, fmt.Errorf("error starting db transaction: %v", err)
+		}
+		_, err = tx.Exec(migration.Up)
+		if err != nil {
+			tx.Rollback()
+			return count, fmt.Errorf("error applying migration %v_%v: %v", migration.Version, migration.Name, err)
+		}
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, NOW())", migration.Version, migration.Name)
+		if err != nil {
+			tx.Rollback()
+			return count, fmt.Errorf("error recording migration %v_%v: %v", migration.Version, migration.Name, err)
+		}
+		err = tx.Commit()
+		if err != nil {
+			return count, fmt.Errorf("error committing migration %v_%v: %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrateDown will roll back the last steps applied migrations in reverse order
+func MigrateDown(db *sqlx.DB, steps int) (int, error) {
+	unlock, err := lockMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := migrations[i]
+		if _, found := applied[migration.Version]; !found {
+			continue
+		}
+		if migration.Down == "" {
+			return count, fmt.Errorf("error migration %v_%v can not be rolled back as it has no down file", migration.Version, migration.Name)
+		}
+
+		logger.Infof("rolling back migration %v_%v", migration.Version, migration.Name)
+		tx, err := db.Beginx()
+		if err != nil {
+			return count, fmt.Errorf("error starting db transaction: %v", err)
+		}
+		_, err = tx.Exec(migration.Down)
+		if err != nil {
+			tx.Rollback()
+			return count, fmt.Errorf("error rolling back migration %v_%v: %v", migration.Version, migration.Name, err)
+		}
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		if err != nil {
+			tx.Rollback()
+			return count, fmt.Errorf("error removing migration %v_%v: %v", migration.Version, migration.Name, err)
+		}
+		err = tx.Commit()
+		if err != nil {
+			return count, fmt.Errorf("error committing rollback of migration %v_%v: %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// GetMigrationsStatus will return all known migrations together with the time they were applied
+func GetMigrationsStatus(db *sqlx.DB) ([]*MigrationStatus, error) {
+	unlock, err := lockMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]*MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		s := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, found := applied[migration.Version]; found {
+			s.AppliedAt = &appliedAt
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
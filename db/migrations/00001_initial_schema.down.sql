@@ -0,0 +1,61 @@
+drop table if exists eth_store_stats;
+drop table if exists rocketpool_network_stats;
+drop table if exists rocketpool_dao_members;
+drop table if exists rocketpool_dao_proposals_member_votes;
+drop table if exists rocketpool_dao_proposals;
+drop table if exists rocketpool_nodes;
+drop table if exists rocketpool_minipools;
+drop table if exists rocketpool_export_status;
+drop table if exists backfill_checkpoints;
+drop table if exists export_failures;
+drop table if exists reorgs;
+drop table if exists finality_checkpoints;
+drop table if exists staking_pools_chart;
+drop table if exists price;
+drop table if exists stake_pools_stats;
+drop table if exists api_statistics;
+drop table if exists chart_images;
+drop table if exists mails_sent;
+drop table if exists users_webhooks;
+drop table if exists validator_tags;
+drop table if exists users_validators_tags;
+drop table if exists notification_queue;
+drop table if exists users_notification_channels;
+drop table if exists users_subscriptions;
+drop table if exists users_clients;
+drop table if exists users_devices;
+drop table if exists oauth_codes;
+drop table if exists oauth_apps;
+drop table if exists users_app_subscriptions;
+drop table if exists users_stripe_subscriptions;
+drop table if exists users_datatable;
+drop table if exists users;
+drop table if exists eth1_deposits;
+drop table if exists graffitiwall;
+drop table if exists network_liveness;
+drop table if exists blocks_voluntaryexits;
+drop table if exists blocks_deposits;
+drop table if exists blocks_attestations;
+drop table if exists blocks_attesterslashings;
+drop table if exists blocks_proposerslashings;
+drop table if exists blocks_transactions;
+drop table if exists blocks;
+drop table if exists epochs;
+drop table if exists validatorqueue_exit;
+drop table if exists validatorqueue_activation;
+drop table if exists queue;
+drop table if exists validator_attestation_streaks;
+drop table if exists validator_stats_status;
+drop table if exists validator_stats;
+drop table if exists validator_balances_recent;
+drop table if exists validator_balances_p;
+drop table if exists sync_committees;
+drop table if exists sync_assignments_p;
+drop table if exists attestation_assignments_p;
+drop table if exists proposal_assignments;
+drop table if exists validator_performance;
+drop table if exists validator_set;
+drop table if exists validator_names;
+drop table if exists validator_pool;
+drop table if exists validators;
+drop type if exists notification_channels;
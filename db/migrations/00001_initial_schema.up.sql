@@ -1,4 +1,4 @@
-create extension pg_trgm; /* trigram extension for faster text-search */
+create extension if not exists pg_trgm; /* trigram extension for faster text-search */
 
 /*
 This table is used to store the current state (latest exported epoch) of all validators
@@ -6,8 +6,7 @@ It also acts as a lookup-table to store the index-pubkey association
 In order to save db space we only use the unique validator index in all other tables
 In the future it is better to replace this table with an in memory cache (redis)
 */
-drop table if exists validators;
-create table validators
+create table if not exists validators
 (
     validatorindex             int         not null,
     pubkey                     bytea       not null,
@@ -28,33 +27,30 @@ create table validators
     status                     varchar(20) not null default '',
     primary key (validatorindex)
 );
-create index idx_validators_pubkey on validators (pubkey);
-create index idx_validators_pubkeyhex on validators (pubkeyhex);
-create index idx_validators_pubkeyhex_pattern_pos on validators (pubkeyhex varchar_pattern_ops);
-create index idx_validators_status on validators (status);
-create index idx_validators_balanceactivation on validators (balanceactivation);
-create index idx_validators_activationepoch on validators (activationepoch);
+create index if not exists idx_validators_pubkey on validators (pubkey);
+create index if not exists idx_validators_pubkeyhex on validators (pubkeyhex);
+create index if not exists idx_validators_pubkeyhex_pattern_pos on validators (pubkeyhex varchar_pattern_ops);
+create index if not exists idx_validators_status on validators (status);
+create index if not exists idx_validators_balanceactivation on validators (balanceactivation);
+create index if not exists idx_validators_activationepoch on validators (activationepoch);
 
-drop table if exists validator_pool;
-create table validator_pool
+create table if not exists validator_pool
 (
     publickey bytea not null,
     pool      varchar(40),
     primary key (publickey)
 );
 
-drop table if exists validator_names;
-create table validator_names
+create table if not exists validator_names
 (
     publickey bytea not null,
     name      varchar(40),
     primary key (publickey)
 );
-create index idx_validator_names_publickey on validator_names (publickey);
-create index idx_validator_names_name on validator_names(name);
+create index if not exists idx_validator_names_publickey on validator_names (publickey);
+create index if not exists idx_validator_names_name on validator_names(name);
 
-drop table if exists validator_set;
-create table validator_set
+create table if not exists validator_set
 (
     epoch                      int    not null,
     validatorindex             int    not null,
@@ -68,8 +64,7 @@ create table validator_set
     primary key (validatorindex, epoch)
 );
 
-drop table if exists validator_performance;
-create table validator_performance
+create table if not exists validator_performance
 (
     validatorindex  int    not null,
     balance         bigint not null,
@@ -80,15 +75,14 @@ create table validator_performance
     rank7d          int    not null,
     primary key (validatorindex)
 );
-create index idx_validator_performance_balance on validator_performance (balance);
-create index idx_validator_performance_performance1d on validator_performance (performance1d);
-create index idx_validator_performance_performance7d on validator_performance (performance7d);
-create index idx_validator_performance_performance31d on validator_performance (performance31d);
-create index idx_validator_performance_performance365d on validator_performance (performance365d);
-create index idx_validator_performance_rank7d on validator_performance (rank7d);
+create index if not exists idx_validator_performance_balance on validator_performance (balance);
+create index if not exists idx_validator_performance_performance1d on validator_performance (performance1d);
+create index if not exists idx_validator_performance_performance7d on validator_performance (performance7d);
+create index if not exists idx_validator_performance_performance31d on validator_performance (performance31d);
+create index if not exists idx_validator_performance_performance365d on validator_performance (performance365d);
+create index if not exists idx_validator_performance_rank7d on validator_performance (rank7d);
 
-drop table if exists proposal_assignments;
-create table proposal_assignments
+create table if not exists proposal_assignments
 (
     epoch          int not null,
     validatorindex int not null,
@@ -96,10 +90,9 @@ create table proposal_assignments
     status         int not null, /* Can be 0 = scheduled, 1 executed, 2 missed */
     primary key (epoch, validatorindex, proposerslot)
 );
-create index idx_proposal_assignments_epoch on proposal_assignments (epoch);
+create index if not exists idx_proposal_assignments_epoch on proposal_assignments (epoch);
 
-drop table if exists attestation_assignments_p;
-create table attestation_assignments_p
+create table if not exists attestation_assignments_p
 (
     epoch          int not null,
     validatorindex int not null,
@@ -111,19 +104,18 @@ create table attestation_assignments_p
     primary key (validatorindex, week, epoch)
 ) PARTITION BY LIST (week);
 
-CREATE TABLE attestation_assignments_0 PARTITION OF attestation_assignments_p FOR VALUES IN (0);
-CREATE TABLE attestation_assignments_1 PARTITION OF attestation_assignments_p FOR VALUES IN (1);
-CREATE TABLE attestation_assignments_2 PARTITION OF attestation_assignments_p FOR VALUES IN (2);
-CREATE TABLE attestation_assignments_3 PARTITION OF attestation_assignments_p FOR VALUES IN (3);
-CREATE TABLE attestation_assignments_4 PARTITION OF attestation_assignments_p FOR VALUES IN (4);
-CREATE TABLE attestation_assignments_5 PARTITION OF attestation_assignments_p FOR VALUES IN (5);
-CREATE TABLE attestation_assignments_6 PARTITION OF attestation_assignments_p FOR VALUES IN (6);
-CREATE TABLE attestation_assignments_7 PARTITION OF attestation_assignments_p FOR VALUES IN (7);
-CREATE TABLE attestation_assignments_8 PARTITION OF attestation_assignments_p FOR VALUES IN (8);
-CREATE TABLE attestation_assignments_9 PARTITION OF attestation_assignments_p FOR VALUES IN (9);
+CREATE TABLE IF NOT EXISTS attestation_assignments_0 PARTITION OF attestation_assignments_p FOR VALUES IN (0);
+CREATE TABLE IF NOT EXISTS attestation_assignments_1 PARTITION OF attestation_assignments_p FOR VALUES IN (1);
+CREATE TABLE IF NOT EXISTS attestation_assignments_2 PARTITION OF attestation_assignments_p FOR VALUES IN (2);
+CREATE TABLE IF NOT EXISTS attestation_assignments_3 PARTITION OF attestation_assignments_p FOR VALUES IN (3);
+CREATE TABLE IF NOT EXISTS attestation_assignments_4 PARTITION OF attestation_assignments_p FOR VALUES IN (4);
+CREATE TABLE IF NOT EXISTS attestation_assignments_5 PARTITION OF attestation_assignments_p FOR VALUES IN (5);
+CREATE TABLE IF NOT EXISTS attestation_assignments_6 PARTITION OF attestation_assignments_p FOR VALUES IN (6);
+CREATE TABLE IF NOT EXISTS attestation_assignments_7 PARTITION OF attestation_assignments_p FOR VALUES IN (7);
+CREATE TABLE IF NOT EXISTS attestation_assignments_8 PARTITION OF attestation_assignments_p FOR VALUES IN (8);
+CREATE TABLE IF NOT EXISTS attestation_assignments_9 PARTITION OF attestation_assignments_p FOR VALUES IN (9);
 
-drop table if exists sync_assignments_p;
-create table sync_assignments_p
+create table if not exists sync_assignments_p
 (
     slot           int not null,
     validatorindex int not null,
@@ -132,8 +124,7 @@ create table sync_assignments_p
     primary key (validatorindex, week, slot)
 ) PARTITION BY LIST (week);
 
-drop table if exists sync_committees;
-create table sync_committees
+create table if not exists sync_committees
 (
     period         int not null,
     validatorindex int not null,
@@ -141,8 +132,7 @@ create table sync_committees
     primary key (period, validatorindex, committeeindex)
 );
 
-drop table if exists validator_balances_p;
-create table validator_balances_p
+create table if not exists validator_balances_p
 (
     epoch            int    not null,
     validatorindex   int    not null,
@@ -152,31 +142,29 @@ create table validator_balances_p
     primary key (validatorindex, week, epoch)
 ) PARTITION BY LIST (week);
 
-CREATE TABLE validator_balances_0 PARTITION OF validator_balances_p FOR VALUES IN (0);
-CREATE TABLE validator_balances_1 PARTITION OF validator_balances_p FOR VALUES IN (1);
-CREATE TABLE validator_balances_2 PARTITION OF validator_balances_p FOR VALUES IN (2);
-CREATE TABLE validator_balances_3 PARTITION OF validator_balances_p FOR VALUES IN (3);
-CREATE TABLE validator_balances_4 PARTITION OF validator_balances_p FOR VALUES IN (4);
-CREATE TABLE validator_balances_5 PARTITION OF validator_balances_p FOR VALUES IN (5);
-CREATE TABLE validator_balances_6 PARTITION OF validator_balances_p FOR VALUES IN (6);
-CREATE TABLE validator_balances_7 PARTITION OF validator_balances_p FOR VALUES IN (7);
-CREATE TABLE validator_balances_8 PARTITION OF validator_balances_p FOR VALUES IN (8);
-CREATE TABLE validator_balances_9 PARTITION OF validator_balances_p FOR VALUES IN (9);
+CREATE TABLE IF NOT EXISTS validator_balances_0 PARTITION OF validator_balances_p FOR VALUES IN (0);
+CREATE TABLE IF NOT EXISTS validator_balances_1 PARTITION OF validator_balances_p FOR VALUES IN (1);
+CREATE TABLE IF NOT EXISTS validator_balances_2 PARTITION OF validator_balances_p FOR VALUES IN (2);
+CREATE TABLE IF NOT EXISTS validator_balances_3 PARTITION OF validator_balances_p FOR VALUES IN (3);
+CREATE TABLE IF NOT EXISTS validator_balances_4 PARTITION OF validator_balances_p FOR VALUES IN (4);
+CREATE TABLE IF NOT EXISTS validator_balances_5 PARTITION OF validator_balances_p FOR VALUES IN (5);
+CREATE TABLE IF NOT EXISTS validator_balances_6 PARTITION OF validator_balances_p FOR VALUES IN (6);
+CREATE TABLE IF NOT EXISTS validator_balances_7 PARTITION OF validator_balances_p FOR VALUES IN (7);
+CREATE TABLE IF NOT EXISTS validator_balances_8 PARTITION OF validator_balances_p FOR VALUES IN (8);
+CREATE TABLE IF NOT EXISTS validator_balances_9 PARTITION OF validator_balances_p FOR VALUES IN (9);
 
-drop table if exists validator_balances_recent;
-create table validator_balances_recent
+create table if not exists validator_balances_recent
 (
     epoch          int    not null,
     validatorindex int    not null,
     balance        bigint not null,
     primary key (epoch, validatorindex)
 );
-create index idx_validator_balances_recent_epoch on validator_balances_recent (epoch);
-create index idx_validator_balances_recent_validatorindex on validator_balances_recent (validatorindex);
-create index idx_validator_balances_recent_balance on validator_balances_recent (balance);
+create index if not exists idx_validator_balances_recent_epoch on validator_balances_recent (epoch);
+create index if not exists idx_validator_balances_recent_validatorindex on validator_balances_recent (validatorindex);
+create index if not exists idx_validator_balances_recent_balance on validator_balances_recent (balance);
 
-drop table if exists validator_stats;
-create table validator_stats
+create table if not exists validator_stats
 (
     validatorindex          int not null,
     day                     int not null,
@@ -202,18 +190,16 @@ create table validator_stats
     deposits_amount         bigint,
     primary key (validatorindex, day)
 );
-create index idx_validator_stats_day on validator_stats (day);
+create index if not exists idx_validator_stats_day on validator_stats (day);
 
-drop table if exists validator_stats_status;
-create table validator_stats_status
+create table if not exists validator_stats_status
 (
     day    int     not null,
     status boolean not null,
     primary key (day)
 );
 
-drop table if exists validator_attestation_streaks;
-create table validator_attestation_streaks
+create table if not exists validator_attestation_streaks
 (
     validatorindex int     not null,
     status         int     not null,
@@ -223,13 +209,12 @@ create table validator_attestation_streaks
     current        boolean not null,
     primary key (validatorindex, status, start)
 );
-create index idx_validator_attestation_streaks_validatorindex on validator_attestation_streaks (validatorindex);
-create index idx_validator_attestation_streaks_status on validator_attestation_streaks (status);
-create index idx_validator_attestation_streaks_length on validator_attestation_streaks (length);
-create index idx_validator_attestation_streaks_start on validator_attestation_streaks (start);
+create index if not exists idx_validator_attestation_streaks_validatorindex on validator_attestation_streaks (validatorindex);
+create index if not exists idx_validator_attestation_streaks_status on validator_attestation_streaks (status);
+create index if not exists idx_validator_attestation_streaks_length on validator_attestation_streaks (length);
+create index if not exists idx_validator_attestation_streaks_start on validator_attestation_streaks (start);
 
-drop table if exists queue;
-create table queue
+create table if not exists queue
 (
     ts                        timestamp without time zone,
     entering_validators_count int not null,
@@ -237,24 +222,21 @@ create table queue
     primary key (ts)
 );
 
-drop table if exists validatorqueue_activation;
-create table validatorqueue_activation
+create table if not exists validatorqueue_activation
 (
     index     int   not null,
     publickey bytea not null,
     primary key (index, publickey)
 );
 
-drop table if exists validatorqueue_exit;
-create table validatorqueue_exit
+create table if not exists validatorqueue_exit
 (
     index     int   not null,
     publickey bytea not null,
     primary key (index, publickey)
 );
 
-drop table if exists epochs;
-create table epochs
+create table if not exists epochs
 (
     epoch                   int    not null,
     blockscount             int    not null default 0,
@@ -273,8 +255,7 @@ create table epochs
     primary key (epoch)
 );
 
-drop table if exists blocks;
-create table blocks
+create table if not exists blocks
 (
     epoch                       int     not null,
     slot                        int     not null,
@@ -318,13 +299,12 @@ create table blocks
 
     primary key (slot, blockroot)
 );
-create index idx_blocks_proposer on blocks (proposer);
-create index idx_blocks_epoch on blocks (epoch);
-create index idx_blocks_graffiti_text on blocks using gin (graffiti_text gin_trgm_ops);
-create index idx_blocks_blockrootstatus on blocks (blockroot, status);
+create index if not exists idx_blocks_proposer on blocks (proposer);
+create index if not exists idx_blocks_epoch on blocks (epoch);
+create index if not exists idx_blocks_graffiti_text on blocks using gin (graffiti_text gin_trgm_ops);
+create index if not exists idx_blocks_blockrootstatus on blocks (blockroot, status);
 
-drop table if exists blocks_transactions;
-create table blocks_transactions
+create table if not exists blocks_transactions
 (
     block_slot         int    not null,
     block_index        int    not null,
@@ -343,8 +323,7 @@ create table blocks_transactions
     primary key (block_slot, block_index)
 );
 
-drop table if exists blocks_proposerslashings;
-create table blocks_proposerslashings
+create table if not exists blocks_proposerslashings
 (
     block_slot         int    not null,
     block_index        int    not null,
@@ -363,8 +342,7 @@ create table blocks_proposerslashings
     primary key (block_slot, block_index)
 );
 
-drop table if exists blocks_attesterslashings;
-create table blocks_attesterslashings
+create table if not exists blocks_attesterslashings
 (
     block_slot                   int       not null,
     block_index                  int       not null,
@@ -390,8 +368,7 @@ create table blocks_attesterslashings
     primary key (block_slot, block_index)
 );
 
-drop table if exists blocks_attestations;
-create table blocks_attestations
+create table if not exists blocks_attestations
 (
     block_slot      int   not null,
     block_index     int   not null,
@@ -408,12 +385,11 @@ create table blocks_attestations
     target_root     bytea not null,
     primary key (block_slot, block_index)
 );
-create index idx_blocks_attestations_beaconblockroot on blocks_attestations (beaconblockroot);
-create index idx_blocks_attestations_source_root on blocks_attestations (source_root);
-create index idx_blocks_attestations_target_root on blocks_attestations (target_root);
+create index if not exists idx_blocks_attestations_beaconblockroot on blocks_attestations (beaconblockroot);
+create index if not exists idx_blocks_attestations_source_root on blocks_attestations (source_root);
+create index if not exists idx_blocks_attestations_target_root on blocks_attestations (target_root);
 
-drop table if exists blocks_deposits;
-create table blocks_deposits
+create table if not exists blocks_deposits
 (
     block_slot            int    not null,
     block_index           int    not null,
@@ -426,8 +402,7 @@ create table blocks_deposits
     primary key (block_slot, block_index)
 );
 
-drop table if exists blocks_voluntaryexits;
-create table blocks_voluntaryexits
+create table if not exists blocks_voluntaryexits
 (
     block_slot     int   not null,
     block_index    int   not null,
@@ -438,8 +413,7 @@ create table blocks_voluntaryexits
     primary key (block_slot, block_index)
 );
 
-drop table if exists network_liveness;
-create table network_liveness
+create table if not exists network_liveness
 (
     ts                     timestamp without time zone,
     headepoch              int not null,
@@ -449,8 +423,7 @@ create table network_liveness
     primary key (ts)
 );
 
-drop table if exists graffitiwall;
-create table graffitiwall
+create table if not exists graffitiwall
 (
     x         int  not null,
     y         int  not null,
@@ -460,8 +433,7 @@ create table graffitiwall
     primary key (x, y)
 );
 
-drop table if exists eth1_deposits;
-create table eth1_deposits
+create table if not exists eth1_deposits
 (
     tx_hash                bytea                       not null,
     tx_input               bytea                       not null,
@@ -478,11 +450,10 @@ create table eth1_deposits
     valid_signature        bool                        not null,
     primary key (tx_hash, merkletree_index)
 );
-create index idx_eth1_deposits on eth1_deposits (publickey);
-create index idx_eth1_deposits_from_address on eth1_deposits (from_address);
+create index if not exists idx_eth1_deposits on eth1_deposits (publickey);
+create index if not exists idx_eth1_deposits_from_address on eth1_deposits (from_address);
 
-drop table if exists users;
-create table users
+create table if not exists users
 (
     id                      serial                 not null unique,
     password                character varying(256) not null,
@@ -498,8 +469,7 @@ create table users
     primary key (id, email)
 );
 
-drop table if exists users_datatable;
-create table users_datatable
+create table if not exists users_datatable
 (
     user_id        int                         not null,
     key            character varying(256)      not null,
@@ -508,8 +478,7 @@ create table users_datatable
     primary key (user_id, key) 
 );
 
-drop table if exists users_stripe_subscriptions;
-create table users_stripe_subscriptions
+create table if not exists users_stripe_subscriptions
 (
     subscription_id character varying(256) unique not null,
     customer_id     character varying(256)        not null,
@@ -520,8 +489,7 @@ create table users_stripe_subscriptions
     primary key (customer_id, subscription_id, price_id)
 );
 
-drop table if exists users_app_subscriptions;
-create table users_app_subscriptions
+create table if not exists users_app_subscriptions
 (
     id              serial                        not null,
     user_id         int                           not null,
@@ -539,10 +507,9 @@ create table users_app_subscriptions
     receipt_hash    character varying(1024)        not null unique,
     subscription_id character varying(256)         default ''
 );
-create index idx_user_app_subscriptions on users_app_subscriptions (user_id);
+create index if not exists idx_user_app_subscriptions on users_app_subscriptions (user_id);
 
-drop table if exists oauth_apps;
-create table oauth_apps
+create table if not exists oauth_apps
 (
     id           serial                      not null,
     owner_id     int                         not null,
@@ -553,8 +520,7 @@ create table oauth_apps
     primary key (id, redirect_uri)
 );
 
-drop table if exists oauth_codes;
-create table oauth_codes
+create table if not exists oauth_codes
 (
     id         serial                      not null,
     user_id    int                         not null,
@@ -566,8 +532,7 @@ create table oauth_codes
     primary key (user_id, app_id, client_id)
 );
 
-drop table if exists users_devices;
-create table users_devices
+create table if not exists users_devices
 (
     id                 serial                      not null,
     user_id            int                         not null,
@@ -581,8 +546,7 @@ create table users_devices
     primary key (user_id, refresh_token)
 );
 
-drop table if exists users_clients;
-create table users_clients
+create table if not exists users_clients
 (
     id             serial                      not null,
     user_id        int                         not null,
@@ -593,9 +557,7 @@ create table users_clients
     primary key (user_id, client)
 );
 
-
-drop table if exists users_subscriptions;
-create table users_subscriptions
+create table if not exists users_subscriptions
 (
     id                serial                      not null,
     user_id           int                         not null,
@@ -609,12 +571,14 @@ create table users_subscriptions
     unsubscribe_hash  bytea                        ,
     primary key (user_id, event_name, event_filter)
 );
-create index idx_users_subscriptions_unsubscribe_hash on users_subscriptions (unsubscribe_hash);
+create index if not exists idx_users_subscriptions_unsubscribe_hash on users_subscriptions (unsubscribe_hash);
 
-CREATE TYPE notification_channels as ENUM ('webhook_discord', 'webhook', 'email', 'push');
+DO $$ BEGIN
+    CREATE TYPE notification_channels as ENUM ('webhook_discord', 'webhook', 'email', 'push');
+EXCEPTION WHEN duplicate_object THEN null;
+END $$;
 
-drop table if exists users_notification_channels;
-create table users_notification_channels
+create table if not exists users_notification_channels
 (
     user_id int                   not null,
     channel notification_channels not null,
@@ -622,8 +586,7 @@ create table users_notification_channels
     primary key (user_id, channel)
 );
 
-drop table if exists notification_queue;
-create table notification_queue(
+create table if not exists notification_queue(
     id                  serial not null,
     created             timestamp without time zone not null,
     sent                timestamp without time zone, -- record when the transaction was dispatched
@@ -645,8 +608,7 @@ create table notification_queue(
 --     primary key(user_id, event_name, event_filter, sent_ts)
 -- );
 
-drop table if exists users_validators_tags;
-create table users_validators_tags
+create table if not exists users_validators_tags
 (
     user_id             int                    not null,
     validator_publickey bytea                  not null,
@@ -654,16 +616,14 @@ create table users_validators_tags
     primary key (user_id, validator_publickey, tag)
 );
 
-drop table if exists validator_tags;
-create table validator_tags
+create table if not exists validator_tags
 (
     publickey bytea                  not null,
     tag       character varying(100) not null,
     primary key (publickey, tag)
 );
 
-drop table if exists users_webhooks;
-create table users_webhooks
+create table if not exists users_webhooks
 (   
     id                serial                  not null,
     user_id           int                     not null,
@@ -678,8 +638,7 @@ create table users_webhooks
     primary key (user_id, id)
 );
 
-drop table if exists mails_sent;
-create table mails_sent
+create table if not exists mails_sent
 (
     email character varying(100)      not null,
     ts    timestamp without time zone not null,
@@ -687,15 +646,13 @@ create table mails_sent
     primary key (email, ts)
 );
 
-drop table if exists chart_images;
-create table chart_images
+create table if not exists chart_images
 (
     name  varchar(100) not null primary key,
     image bytea        not null
 );
 
-drop table if exists api_statistics;
-create table api_statistics
+create table if not exists api_statistics
 (
     ts     timestamp without time zone not null,
     apikey varchar(64)                 not null,
@@ -704,7 +661,6 @@ create table api_statistics
     primary key (ts, apikey, call)
 );
 
-drop table if exists stats_meta_p;
 CREATE TABLE stats_meta_p (
                               id 				    bigserial,
                               version 			int 				        not null default 1,
@@ -720,7 +676,6 @@ CREATE TABLE stats_meta_p (
 
 ) PARTITION BY LIST (day);
 
-drop table if exists stats_process;
 CREATE TABLE stats_process (
                                id 				bigserial 			primary key,
 
@@ -739,9 +694,8 @@ CREATE TABLE stats_process (
 
                                foreign key(meta_id) references stats_meta(id)
 );
-create index idx_stats_process_metaid on stats_process (meta_id);
+create index if not exists idx_stats_process_metaid on stats_process (meta_id);
 
-drop table if exists stats_add_beaconnode;
 CREATE TABLE stats_add_beaconnode (
                                       id 					bigserial 		primary key,
 
@@ -759,9 +713,8 @@ CREATE TABLE stats_add_beaconnode (
 
                                       foreign key(general_id) references stats_process(id)
 );
-create index idx_stats_beaconnode_generalid on stats_add_beaconnode (general_id);
+create index if not exists idx_stats_beaconnode_generalid on stats_add_beaconnode (general_id);
 
-drop table if exists stats_add_validator;
 CREATE TABLE stats_add_validator (
                                      id		 			bigserial	 	primary key,
                                      validator_total 			int	 		not null,
@@ -771,9 +724,8 @@ CREATE TABLE stats_add_validator (
 
                                      foreign key(general_id) references stats_process(id)
 );
-create index idx_stats_beaconnode_validator on stats_add_validator (general_id);
+create index if not exists idx_stats_beaconnode_validator on stats_add_validator (general_id);
 
-drop table if exists stats_system;
 CREATE TABLE stats_system (
                               id		 			bigserial 	 	primary key,
 
@@ -805,14 +757,12 @@ CREATE TABLE stats_system (
 
                               meta_id	 			bigint		 	not null,
 
-
                               foreign key(meta_id) references stats_meta(id)
 );
 
-create index idx_stats_system_meta_id on stats_system (meta_id);
+create index if not exists idx_stats_system_meta_id on stats_system (meta_id);
 
-drop table if exists stake_pools_stats;
-create table stake_pools_stats
+create table if not exists stake_pools_stats
 (
     id serial not null,
     address text not null,
@@ -822,8 +772,7 @@ create table stake_pools_stats
     PRIMARY KEY(id, address, deposit, name)
 );
 
-drop table if exists price;
-create table price
+create table if not exists price
 (
     ts     timestamp without time zone not null,
     eur numeric(20,10)                not null,
@@ -837,8 +786,7 @@ create table price
     primary key (ts)
 );
 
-drop table if exists staking_pools_chart;
-create table staking_pools_chart
+create table if not exists staking_pools_chart
 (
     epoch                      int  not null,
     name                       text not null,
@@ -847,7 +795,6 @@ create table staking_pools_chart
     PRIMARY KEY(epoch, name)
 );
 
-drop table if exists stats_sharing;
 CREATE TABLE stats_sharing (
                                id 				bigserial 			primary key,
                                ts 				timestamp  			not null,
@@ -856,8 +803,7 @@ CREATE TABLE stats_sharing (
                                foreign key(user_id) references users(id)
 );
 
-drop table if exists finality_checkpoints;
-create table finality_checkpoints (
+create table if not exists finality_checkpoints (
                                       head_epoch               int   not null,
                                       head_root                bytea not null,
                                       current_justified_epoch  int   not null,
@@ -869,16 +815,51 @@ create table finality_checkpoints (
                                       primary key (head_epoch, head_root)
 );
 
-drop table if exists rocketpool_export_status;
-create table rocketpool_export_status
+create table if not exists reorgs
+(
+    id                   serial,
+    slot                 int         not null,
+    depth                int         not null,
+    common_ancestor_slot int         not null,
+    old_head_slot        int         not null,
+    old_head_root        bytea       not null,
+    new_head_slot        int         not null,
+    new_head_root        bytea       not null,
+    orphaned_blocks      int         not null,
+    detected_at          timestamp without time zone not null,
+    primary key (id)
+);
+create index if not exists idx_reorgs_slot on reorgs (slot);
+
+create table if not exists export_failures
+(
+    epoch           int  not null,
+    error           text not null,
+    attempts        int  not null,
+    first_failed_at timestamp without time zone not null,
+    last_failed_at  timestamp without time zone not null,
+    next_retry_at   timestamp without time zone not null,
+    primary key (epoch)
+);
+
+create table if not exists backfill_checkpoints
+(
+    name        varchar(100) not null,
+    start_epoch int          not null,
+    end_epoch   int          not null,
+    last_epoch  int          not null,
+    updated_at  timestamp without time zone not null,
+    primary key (name, start_epoch, end_epoch)
+);
+
+create table if not exists rocketpool_export_status
 (
     rocketpool_storage_address bytea not null,
     eth1_block int not null,
     primary key (rocketpool_storage_address)
 );
 
-drop table if exists rocketpool_minipools;
-create table rocketpool_minipools
+create table if not exists rocketpool_minipools
 (
     rocketpool_storage_address bytea not null,
 
@@ -893,8 +874,7 @@ create table rocketpool_minipools
     primary key(rocketpool_storage_address, address)
 );
 
-drop table if exists rocketpool_nodes;
-create table rocketpool_nodes
+create table if not exists rocketpool_nodes
 (
     rocketpool_storage_address bytea not null,
 
@@ -907,8 +887,7 @@ create table rocketpool_nodes
     primary key(rocketpool_storage_address, address)
 );
 
-drop table if exists rocketpool_dao_proposals;
-create table rocketpool_dao_proposals
+create table if not exists rocketpool_dao_proposals
 (
     rocketpool_storage_address bytea not null,
 
@@ -933,8 +912,7 @@ create table rocketpool_dao_proposals
     primary key(rocketpool_storage_address, id)
 );
 
-drop table if exists rocketpool_dao_proposals_member_votes;
-create table rocketpool_dao_proposals_member_votes
+create table if not exists rocketpool_dao_proposals_member_votes
 (
     rocketpool_storage_address bytea not null,
 
@@ -946,8 +924,7 @@ create table rocketpool_dao_proposals_member_votes
     primary key(rocketpool_storage_address, id, member_address)
 );
 
-drop table if exists rocketpool_dao_members;
-create table rocketpool_dao_members
+create table if not exists rocketpool_dao_members
 (
     rocketpool_storage_address bytea not null,
 
@@ -962,8 +939,7 @@ create table rocketpool_dao_members
     primary key(rocketpool_storage_address, address)
 );
 
-drop table if exists rocketpool_network_stats;
-create table rocketpool_network_stats
+create table if not exists rocketpool_network_stats
 (
     id 				    bigserial,
     ts timestamp without time zone not null,
@@ -985,9 +961,7 @@ create table rocketpool_network_stats
     primary key(id)
 );
 
-
-drop table if exists eth_store_stats;
-create table eth_store_stats
+create table if not exists eth_store_stats
 (
     day			int	not null,
     effective_balances_sum	bigint	not null,
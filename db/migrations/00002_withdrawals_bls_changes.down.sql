@@ -0,0 +1,2 @@
+drop table if exists blocks_bls_change;
+drop table if exists blocks_withdrawals;
@@ -0,0 +1,25 @@
+create table if not exists blocks_withdrawals
+(
+    block_slot      int    not null,
+    block_root      bytea  not null,
+    withdrawalindex int    not null,
+    validatorindex  int    not null,
+    address         bytea  not null,
+    amount          bigint not null, -- in GWei
+    primary key (block_slot, block_root, withdrawalindex)
+);
+create index if not exists idx_blocks_withdrawals_recipient on blocks_withdrawals (address);
+create index if not exists idx_blocks_withdrawals_validatorindex on blocks_withdrawals (validatorindex);
+
+create table if not exists blocks_bls_change
+(
+    block_slot     int   not null,
+    block_root     bytea not null,
+    validatorindex int   not null,
+    signature      bytea not null,
+    pubkey         bytea not null,
+    address        bytea not null,
+    primary key (block_slot, block_root, validatorindex)
+);
+create index if not exists idx_blocks_bls_change_pubkey on blocks_bls_change (pubkey);
+create index if not exists idx_blocks_bls_change_address on blocks_bls_change (address);
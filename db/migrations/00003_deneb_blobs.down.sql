@@ -0,0 +1,9 @@
+drop table if exists blocks_blob_sidecars;
+drop table if exists blocks_blobs;
+
+alter table blocks_transactions drop column if exists blob_versioned_hashes;
+alter table blocks_transactions drop column if exists max_fee_per_blob_gas;
+
+alter table blocks drop column if exists blobcount;
+alter table blocks drop column if exists exec_excess_blob_gas;
+alter table blocks drop column if exists exec_blob_gas_used;
@@ -0,0 +1,32 @@
+alter table blocks add column if not exists exec_blob_gas_used   bigint;
+alter table blocks add column if not exists exec_excess_blob_gas bigint;
+alter table blocks add column if not exists blobcount            int not null default 0;
+
+alter table blocks_transactions add column if not exists max_fee_per_blob_gas  bigint;
+alter table blocks_transactions add column if not exists blob_versioned_hashes bytea[];
+
+-- kzg commitments of the blobs of a block as included in the block body
+create table if not exists blocks_blobs
+(
+    block_slot     int   not null,
+    block_root     bytea not null,
+    blob_index     int   not null,
+    kzg_commitment bytea not null,
+    versioned_hash bytea not null,
+    tx_hash        bytea, /* hash of the blob transaction that references the blob */
+    primary key (block_slot, block_root, blob_index)
+);
+create index if not exists idx_blocks_blobs_versioned_hash on blocks_blobs (versioned_hash);
+create index if not exists idx_blocks_blobs_tx_hash on blocks_blobs (tx_hash);
+
+-- blob sidecars as retrieved from the beacon-node, the blob data itself is not stored
+create table if not exists blocks_blob_sidecars
+(
+    block_slot     int   not null,
+    block_root     bytea not null,
+    blob_index     int   not null,
+    kzg_commitment bytea not null,
+    kzg_proof      bytea not null,
+    size           int   not null, /* size of the blob without trailing zero bytes */
+    primary key (block_slot, block_root, blob_index)
+);
@@ -0,0 +1,3 @@
+alter table epochs drop column if exists rewards_exported;
+
+drop table if exists validator_rewards_p;
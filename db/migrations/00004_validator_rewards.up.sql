@@ -0,0 +1,19 @@
+-- consensus layer rewards of validators per epoch as reported by the beacon-node rewards api, penalties are negative
+create table if not exists validator_rewards_p
+(
+    validatorindex              int    not null,
+    epoch                       int    not null,
+    source                      bigint not null default 0,
+    target                      bigint not null default 0,
+    head                        bigint not null default 0,
+    inclusion_delay             bigint not null default 0,
+    inactivity                  bigint not null default 0,
+    sync_committee              bigint not null default 0,
+    proposer_attestations       bigint not null default 0,
+    proposer_sync_aggregate     bigint not null default 0,
+    proposer_slashing_inclusion bigint not null default 0,
+    week                        int    not null,
+    primary key (validatorindex, week, epoch)
+) PARTITION BY LIST (week);
+
+alter table epochs add column if not exists rewards_exported bool not null default false;
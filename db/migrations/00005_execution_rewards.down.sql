@@ -0,0 +1,3 @@
+drop index if exists idx_blocks_exec_priority_fees_missing;
+
+alter table blocks drop column if exists exec_priority_fees;
@@ -0,0 +1,4 @@
+-- sum of the effective priority fees of the transactions of a block in wei, null until computed from the receipts
+alter table blocks add column if not exists exec_priority_fees numeric;
+
+create index if not exists idx_blocks_exec_priority_fees_missing on blocks (slot) where exec_priority_fees is null and exec_block_number is not null;
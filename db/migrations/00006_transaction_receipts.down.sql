@@ -0,0 +1,4 @@
+drop table if exists blocks_transactions_logs;
+drop table if exists blocks_transactions_receipts;
+
+drop index if exists idx_blocks_transactions_txhash;
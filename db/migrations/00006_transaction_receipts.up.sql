@@ -0,0 +1,33 @@
+create index if not exists idx_blocks_transactions_txhash on blocks_transactions (txhash);
+
+-- execution outcome of the transactions as reported by the receipts of the execution client
+create table if not exists blocks_transactions_receipts
+(
+    block_slot          int      not null,
+    block_index         int      not null,
+    block_root          bytea    not null,
+    txhash              bytea    not null,
+    status              smallint not null, /* 1 = success, 0 = reverted */
+    gas_used            bigint   not null,
+    effective_gas_price bigint   not null,
+    contract_address    bytea, /* address of the contract created by the transaction */
+    logs_count          int      not null default 0,
+    primary key (block_slot, block_index)
+);
+create index if not exists idx_blocks_transactions_receipts_txhash on blocks_transactions_receipts (txhash);
+create index if not exists idx_blocks_transactions_receipts_contract_address on blocks_transactions_receipts (contract_address) where contract_address is not null;
+
+create table if not exists blocks_transactions_logs
+(
+    block_slot  int     not null,
+    block_root  bytea   not null,
+    log_index   int     not null, /* index of the log in the block */
+    block_index int     not null, /* index of the transaction in the block */
+    txhash      bytea   not null,
+    address     bytea   not null,
+    topics      bytea[] not null,
+    data        bytea   not null,
+    primary key (block_slot, log_index)
+);
+create index if not exists idx_blocks_transactions_logs_txhash on blocks_transactions_logs (txhash);
+create index if not exists idx_blocks_transactions_logs_address on blocks_transactions_logs (address);
@@ -0,0 +1,4 @@
+drop index if exists idx_validators_withdrawalcredentials;
+drop index if exists idx_blocks_exec_fee_recipient;
+drop index if exists idx_blocks_transactions_recipient;
+drop index if exists idx_blocks_transactions_sender;
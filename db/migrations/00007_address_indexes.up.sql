@@ -0,0 +1,5 @@
+-- lookups of the activity of execution layer addresses for the address page
+create index if not exists idx_blocks_transactions_sender on blocks_transactions (sender);
+create index if not exists idx_blocks_transactions_recipient on blocks_transactions (recipient);
+create index if not exists idx_blocks_exec_fee_recipient on blocks (exec_fee_recipient) where exec_fee_recipient is not null;
+create index if not exists idx_validators_withdrawalcredentials on validators (withdrawalcredentials);
@@ -0,0 +1 @@
+drop table if exists bls_change_pool;
@@ -0,0 +1,12 @@
+-- bls to execution changes that are waiting in the operation pool of the beacon-node and have not been included in a block yet
+create table if not exists bls_change_pool
+(
+    validatorindex  int       not null,
+    signature       bytea     not null,
+    pubkey          bytea     not null,
+    address         bytea     not null,
+    valid_signature bool      not null, /* the signature verifies against the from_bls_pubkey */
+    pubkey_matches  bool      not null, /* the from_bls_pubkey matches the current 0x00 withdrawal credentials of the validator */
+    first_seen      timestamp without time zone not null,
+    primary key (validatorindex)
+);
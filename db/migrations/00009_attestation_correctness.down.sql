@@ -0,0 +1,12 @@
+alter table validator_stats drop column if exists correct_source_attestations;
+alter table validator_stats drop column if exists correct_target_attestations;
+alter table validator_stats drop column if exists correct_head_attestations;
+alter table validator_stats drop column if exists inclusion_delay_sum;
+alter table validator_stats drop column if exists included_attestations;
+
+alter table epochs drop column if exists attestations_evaluated;
+
+alter table attestation_assignments_p drop column if exists source_correct;
+alter table attestation_assignments_p drop column if exists target_correct;
+alter table attestation_assignments_p drop column if exists head_correct;
+alter table attestation_assignments_p drop column if exists inclusion_delay;
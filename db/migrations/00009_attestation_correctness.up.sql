@@ -0,0 +1,13 @@
+-- inclusion delay and correctness of the head, target and source votes of included attestations, null until evaluated
+alter table attestation_assignments_p add column if not exists inclusion_delay int;
+alter table attestation_assignments_p add column if not exists head_correct bool;
+alter table attestation_assignments_p add column if not exists target_correct bool;
+alter table attestation_assignments_p add column if not exists source_correct bool;
+
+alter table epochs add column if not exists attestations_evaluated bool not null default false;
+
+alter table validator_stats add column if not exists included_attestations int;
+alter table validator_stats add column if not exists inclusion_delay_sum bigint;
+alter table validator_stats add column if not exists correct_head_attestations int;
+alter table validator_stats add column if not exists correct_target_attestations int;
+alter table validator_stats add column if not exists correct_source_attestations int;
@@ -0,0 +1 @@
+drop table if exists proposer_duties;
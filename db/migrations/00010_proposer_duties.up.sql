@@ -0,0 +1,10 @@
+-- proposer duties of the current and the next epoch as reported by the beacon-node, replaced when the dependent root changes
+create table if not exists proposer_duties
+(
+    slot           int   not null,
+    epoch          int   not null,
+    validatorindex int   not null,
+    dependent_root bytea not null,
+    primary key (slot)
+);
+create index if not exists idx_proposer_duties_validatorindex on proposer_duties (validatorindex);
@@ -0,0 +1,7 @@
+drop table if exists users_webhook_deliveries;
+
+alter table notification_queue drop column if exists idempotency_key;
+alter table notification_queue drop column if exists next_attempt;
+alter table notification_queue drop column if exists attempts;
+
+alter table users_webhooks drop column if exists secret;
@@ -0,0 +1,25 @@
+-- secret of a webhook that is used to sign its requests with hmac-sha256 so receivers can verify their origin
+alter table users_webhooks add column if not exists secret bytea;
+update users_webhooks set secret = sha256((random()::text || clock_timestamp()::text || id::text)::bytea) where secret is null;
+
+-- delivery state of queued messages, webhook messages are retried with an exponential backoff
+alter table notification_queue add column if not exists attempts int not null default 0;
+alter table notification_queue add column if not exists next_attempt timestamp without time zone;
+alter table notification_queue add column if not exists idempotency_key varchar(64);
+
+-- every delivery attempt of a webhook message, the content is kept so the message can be resent
+create table if not exists users_webhook_deliveries
+(
+    id              serial                      not null,
+    webhook_id      int                         not null,
+    idempotency_key varchar(64)                 not null,
+    channel         notification_channels       not null,
+    content         jsonb                       not null,
+    attempt         int                         not null,
+    ts              timestamp without time zone not null,
+    status_code     int, /* null if no response was received */
+    response        text,
+    error           text,
+    primary key (id)
+);
+create index if not exists idx_users_webhook_deliveries_webhook_id on users_webhook_deliveries (webhook_id, ts);
@@ -0,0 +1,7 @@
+-- values can not be removed from an enum, the chat channels stay part of notification_channels but are no longer used
+delete from notification_queue where channel in ('telegram', 'slack', 'matrix');
+delete from users_notification_channels where channel in ('telegram', 'slack', 'matrix');
+
+alter table users_notification_channels drop column if exists token;
+alter table users_notification_channels drop column if exists server;
+alter table users_notification_channels drop column if exists destination;
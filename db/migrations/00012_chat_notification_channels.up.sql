@@ -0,0 +1,10 @@
+-- chat platforms notifications can be posted to
+alter type notification_channels add value if not exists 'telegram';
+alter type notification_channels add value if not exists 'slack';
+alter type notification_channels add value if not exists 'matrix';
+
+-- destination of chat channels: the telegram chat id, the slack incoming webhook url or the matrix room id
+alter table users_notification_channels add column if not exists destination varchar(500);
+-- homeserver url and access token of matrix channels
+alter table users_notification_channels add column if not exists server varchar(500);
+alter table users_notification_channels add column if not exists token varchar(500);
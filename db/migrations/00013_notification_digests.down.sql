@@ -0,0 +1,2 @@
+drop table if exists users_notification_digest_items;
+drop table if exists users_notification_settings;
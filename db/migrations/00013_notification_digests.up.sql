@@ -0,0 +1,25 @@
+-- delivery preferences of a user: immediate notifications or an hourly or daily digest of the email notifications,
+-- and quiet hours in the timezone of the user during which no email, push or chat notifications are delivered
+create table if not exists users_notification_settings
+(
+    user_id           int                         not null,
+    delivery          varchar(10)                 not null default 'immediate', -- immediate, hourly or daily
+    timezone          varchar(64)                 not null default 'UTC',
+    quiet_hours_start smallint                    not null default 0, /* quiet hours are disabled if start equals end */
+    quiet_hours_end   smallint                    not null default 0,
+    last_digest       timestamp without time zone,
+    primary key (user_id)
+);
+
+-- notifications collected for the next digest of a user
+create table if not exists users_notification_digest_items
+(
+    id           serial                      not null,
+    user_id      int                         not null,
+    event_name   character varying(100)      not null,
+    event_filter text                        not null default '',
+    epoch        int                         not null,
+    created      timestamp without time zone not null,
+    primary key (id)
+);
+create index if not exists idx_users_notification_digest_items_user_id on users_notification_digest_items (user_id, created);
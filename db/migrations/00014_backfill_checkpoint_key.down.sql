@@ -0,0 +1,3 @@
+alter table backfill_checkpoints drop constraint if exists backfill_checkpoints_pkey;
+alter table backfill_checkpoints add column if not exists end_epoch int not null default 0;
+alter table backfill_checkpoints add primary key (name, start_epoch, end_epoch);
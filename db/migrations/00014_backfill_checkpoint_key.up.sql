@@ -0,0 +1,7 @@
+-- backfill checkpoints are keyed on the name and the first epoch of a backfill, the last epoch may grow between runs
+delete from backfill_checkpoints a
+    using backfill_checkpoints b
+    where a.name = b.name and a.start_epoch = b.start_epoch and (a.updated_at, a.end_epoch) < (b.updated_at, b.end_epoch);
+alter table backfill_checkpoints drop constraint if exists backfill_checkpoints_pkey;
+alter table backfill_checkpoints drop column if exists end_epoch;
+alter table backfill_checkpoints add primary key (name, start_epoch);
@@ -0,0 +1,2 @@
+alter table blocks drop column if exists blob_sidecars_retry_at;
+alter table blocks drop column if exists blob_sidecars_attempts;
@@ -0,0 +1,3 @@
+-- failed requests of the blob sidecars of a block, the next request is delayed with an exponential backoff
+alter table blocks add column if not exists blob_sidecars_attempts int not null default 0;
+alter table blocks add column if not exists blob_sidecars_retry_at timestamp without time zone;
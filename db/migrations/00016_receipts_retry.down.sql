@@ -0,0 +1,2 @@
+alter table blocks drop column if exists exec_receipts_retry_at;
+alter table blocks drop column if exists exec_receipts_attempts;
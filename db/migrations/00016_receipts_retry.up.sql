@@ -0,0 +1,3 @@
+-- failed exports of the receipts of a block, the next export is delayed with an exponential backoff
+alter table blocks add column if not exists exec_receipts_attempts int not null default 0;
+alter table blocks add column if not exists exec_receipts_retry_at timestamp without time zone;
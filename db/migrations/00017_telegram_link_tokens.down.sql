@@ -0,0 +1 @@
+drop table if exists users_telegram_link_tokens;
@@ -0,0 +1,12 @@
+-- tokens that link a telegram chat to a user, the user sends /start <token> to the bot from the chat
+create table if not exists users_telegram_link_tokens
+(
+    user_id int                         not null,
+    token   varchar(64)                 not null,
+    created timestamp without time zone not null,
+    primary key (user_id)
+);
+create unique index if not exists idx_users_telegram_link_tokens_token on users_telegram_link_tokens (token);
+
+-- chat ids entered before chats had to be linked through the bot are not proven to belong to the user
+update users_notification_channels set destination = null, active = false where channel = 'telegram' and destination is not null;
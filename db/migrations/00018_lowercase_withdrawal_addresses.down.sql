@@ -0,0 +1 @@
+-- lowercased addresses can not be restored
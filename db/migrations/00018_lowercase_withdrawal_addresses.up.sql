@@ -0,0 +1,13 @@
+-- withdrawal subscriptions of execution addresses were stored as typed, drop the ones that only differ in case before lowercasing the rest
+delete from users_subscriptions a
+    using users_subscriptions b
+where a.event_name = b.event_name
+  and a.user_id = b.user_id
+  and a.event_name like '%validator_withdrawal'
+  and length(a.event_filter) = 40
+  and lower(a.event_filter) = lower(b.event_filter)
+  and a.event_filter <> lower(a.event_filter)
+  and (b.event_filter = lower(b.event_filter) or b.id < a.id);
+
+update users_subscriptions set event_filter = lower(event_filter)
+where event_name like '%validator_withdrawal' and length(event_filter) = 40 and event_filter <> lower(event_filter);
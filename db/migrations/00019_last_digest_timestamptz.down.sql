@@ -0,0 +1 @@
+alter table users_notification_settings alter column last_digest type timestamp without time zone using last_digest at time zone 'UTC';
@@ -0,0 +1,3 @@
+-- last_digest was written with now() into a column without time zone while it is compared against the time of the
+-- notification service, it is stored with time zone so both refer to the same instant
+alter table users_notification_settings alter column last_digest type timestamp with time zone using last_digest at time zone 'UTC';
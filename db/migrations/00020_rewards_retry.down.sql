@@ -0,0 +1,2 @@
+alter table epochs drop column if exists rewards_retry_at;
+alter table epochs drop column if exists rewards_attempts;
@@ -0,0 +1,4 @@
+-- failed exports of the rewards of an epoch, the next export is delayed with an exponential backoff and given up after
+-- a number of attempts as nodes that pruned the state of the epoch can not provide its rewards anymore
+alter table epochs add column if not exists rewards_attempts int not null default 0;
+alter table epochs add column if not exists rewards_retry_at timestamp without time zone;
@@ -0,0 +1,11 @@
+-- only the receipts and logs of one block per slot can be kept
+delete from blocks_transactions_logs a
+    using blocks_transactions_logs b
+    where a.block_slot = b.block_slot and a.log_index = b.log_index and a.block_root < b.block_root;
+delete from blocks_transactions_receipts a
+    using blocks_transactions_receipts b
+    where a.block_slot = b.block_slot and a.block_index = b.block_index and a.block_root < b.block_root;
+alter table blocks_transactions_logs drop constraint if exists blocks_transactions_logs_pkey;
+alter table blocks_transactions_logs add primary key (block_slot, log_index);
+alter table blocks_transactions_receipts drop constraint if exists blocks_transactions_receipts_pkey;
+alter table blocks_transactions_receipts add primary key (block_slot, block_index);
@@ -0,0 +1,5 @@
+-- the receipts and logs of a block replaced in a reorg share the slot with the receipts and logs of the new block
+alter table blocks_transactions_receipts drop constraint if exists blocks_transactions_receipts_pkey;
+alter table blocks_transactions_receipts add primary key (block_slot, block_root, block_index);
+alter table blocks_transactions_logs drop constraint if exists blocks_transactions_logs_pkey;
+alter table blocks_transactions_logs add primary key (block_slot, block_root, log_index);
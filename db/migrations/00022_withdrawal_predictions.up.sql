@@ -0,0 +1,13 @@
+-- predicted next withdrawal of the validators, replaced by the exporter after every simulation of the withdrawal sweep
+create table if not exists withdrawal_predictions
+(
+    validatorindex  int     not null,
+    address         bytea   not null,
+    slot            int     not null,
+    amount          bigint  not null, /* in gwei */
+    is_full         boolean not null,
+    income_per_slot bigint  not null, /* average income in gwei of the validator per slot during the last 7 days */
+    cycle_slots     int     not null, /* number of slots the simulated sweep needed to pass all validators once */
+    primary key (validatorindex)
+);
+create index if not exists idx_withdrawal_predictions_address on withdrawal_predictions (address);
@@ -0,0 +1 @@
+alter table blocks_transactions_receipts alter column effective_gas_price type bigint;
@@ -0,0 +1,2 @@
+-- effective gas prices in wei can exceed the range of a bigint
+alter table blocks_transactions_receipts alter column effective_gas_price type numeric;
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"eth2-exporter/metrics"
 	"eth2-exporter/utils"
 	"time"
@@ -60,6 +61,14 @@ func WriteStatisticsForDay(day uint64) error {
 	}
 	logger.Infof("export completed, took %v", time.Since(start))
 
+	start = time.Now()
+	logger.Infof("exporting attestation inclusion delay and correctness statistics")
+	err = writeAttestationCorrectnessStatistics(tx, firstEpoch, lastEpoch, day)
+	if err != nil {
+		return err
+	}
+	logger.Infof("export completed, took %v", time.Since(start))
+
 	start = time.Now()
 	logger.Infof("exporting sync statistics")
 	_, err = tx.Exec(`
@@ -167,3 +176,19 @@ func WriteStatisticsForDay(day uint64) error {
 	logger.Infof("statistics export of day %v completed, took %v", day, time.Since(exportStart))
 	return nil
 }
+
+// writeAttestationCorrectnessStatistics will aggregate the inclusion delay and the correctness of the votes of the evaluated attestations of a day,
+// attestations that have not been evaluated yet are added once their epoch is evaluated
+func writeAttestationCorrectnessStatistics(tx *sql.Tx, firstEpoch, lastEpoch, day uint64) error {
+	_, err := tx.Exec(`
+		insert into validator_stats (validatorindex, day, included_attestations, inclusion_delay_sum, correct_head_attestations, correct_target_attestations, correct_source_attestations)
+		(
+			select validatorindex, $3, count(inclusion_delay), coalesce(sum(inclusion_delay), 0), sum(case when head_correct then 1 else 0 end), sum(case when target_correct then 1 else 0 end), sum(case when source_correct then 1 else 0 end)
+			from attestation_assignments_p
+			where week >= $1 / 1575 AND week <= $2 / 1575 and epoch >= $1 and epoch <= $2 and inclusion_delay is not null
+			group by validatorindex
+		)
+		on conflict (validatorindex, day) do update set included_attestations = excluded.included_attestations, inclusion_delay_sum = excluded.inclusion_delay_sum, correct_head_attestations = excluded.correct_head_attestations, correct_target_attestations = excluded.correct_target_attestations, correct_source_attestations = excluded.correct_source_attestations;`,
+		firstEpoch, lastEpoch, day)
+	return err
+}
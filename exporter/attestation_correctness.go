@@ -0,0 +1,106 @@
+package exporter
+
+import (
+	"bytes"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// attestationEvaluationBatchSize is the number of epochs whose attestations are evaluated per run
+const attestationEvaluationBatchSize = 10
+
+func attestationCorrectnessExporter() {
+	for {
+		t0 := time.Now()
+		err := exportAttestationCorrectness()
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error evaluating attestations")
+		}
+		time.Sleep(time.Second * 12)
+	}
+}
+
+// exportAttestationCorrectness will evaluate the attestations of the finalized epochs that have not been evaluated yet
+func exportAttestationCorrectness() error {
+	epochs, err := db.GetEpochsMissingAttestationEvaluation(attestationEvaluationBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, epoch := range epochs {
+		t0 := time.Now()
+		evaluations, err := evaluateAttestations(epoch)
+		if err != nil {
+			return err
+		}
+		err = db.SaveAttestationEvaluations(epoch, evaluations)
+		if err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{"epoch": epoch, "attestations": len(evaluations), "duration": time.Since(t0)}).Infof("evaluated attestations")
+	}
+	return nil
+}
+
+// evaluateAttestations will compute the inclusion delay of the first included attestation of every validator in the epoch
+// and check its head, target and source votes against the canonical chain
+func evaluateAttestations(epoch uint64) ([]*types.AttestationEvaluation, error) {
+	attestations, err := db.GetIncludedAttestations(epoch)
+	if err != nil {
+		return nil, err
+	}
+	if len(attestations) == 0 {
+		return []*types.AttestationEvaluation{}, nil
+	}
+
+	minEpoch := epoch
+	for _, a := range attestations {
+		if a.SourceEpoch < minEpoch {
+			minEpoch = a.SourceEpoch
+		}
+	}
+	roots, err := db.GetCanonicalBlockRoots(minEpoch*utils.Config.Chain.Config.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.Config.SlotsPerEpoch-1)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluations := make([]*types.AttestationEvaluation, 0, len(attestations))
+	evaluated := make(map[uint64]bool)
+	for _, a := range attestations {
+		headCorrect := bytes.Equal(a.BeaconBlockRoot, canonicalBlockRootAt(roots, a.Slot))
+		targetCorrect := bytes.Equal(a.TargetRoot, canonicalBlockRootAt(roots, a.TargetEpoch*utils.Config.Chain.Config.SlotsPerEpoch))
+		// the checkpoint of the genesis epoch has an empty root until the first justification
+		sourceCorrect := bytes.Equal(a.SourceRoot, canonicalBlockRootAt(roots, a.SourceEpoch*utils.Config.Chain.Config.SlotsPerEpoch)) ||
+			(a.SourceEpoch == 0 && bytes.Equal(a.SourceRoot, make([]byte, 32)))
+
+		// attestations are ordered by inclusion, only the first inclusion of a validator counts
+		for _, validator := range a.Validators {
+			if evaluated[uint64(validator)] {
+				continue
+			}
+			evaluated[uint64(validator)] = true
+			evaluations = append(evaluations, &types.AttestationEvaluation{
+				Validatorindex: uint64(validator),
+				InclusionDelay: a.BlockSlot - a.Slot,
+				HeadCorrect:    headCorrect,
+				TargetCorrect:  targetCorrect,
+				SourceCorrect:  sourceCorrect,
+			})
+		}
+	}
+	return evaluations, nil
+}
+
+// canonicalBlockRootAt returns the root of the latest canonical block at or before the slot, roots must be ordered by slot
+func canonicalBlockRootAt(roots []*types.CanonicalBlockRoot, slot uint64) []byte {
+	i := sort.Search(len(roots), func(i int) bool { return roots[i].Slot > slot })
+	if i == 0 {
+		return nil
+	}
+	return roots[i-1].BlockRoot
+}
@@ -0,0 +1,205 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// backfillFetchRetries is the number of times the data of an epoch is requested before it is recorded as failed
+const backfillFetchRetries = 3
+
+type backfillResult struct {
+	epoch uint64
+	data  *types.EpochData
+	err   error
+}
+
+// Backfill will export the given epochs. The epoch data is fetched by a pool of workers ahead of the epoch that is
+// currently saved, while the epochs are saved in ascending order. Epochs that fail to export are recorded in the
+// export failures and retried by the regular export, the backfill continues with the next epoch. The progress is
+// checkpointed under the name and the first epoch of the backfill, so a backfill that was interrupted resumes after
+// the last saved epoch even if more epochs have been added to its end. The checkpoint is not advanced past a failed
+// epoch and is removed once every epoch has been saved, so running the same backfill again exports all epochs again.
+func Backfill(name string, epochs []uint64, client rpc.Client) error {
+	if len(epochs) == 0 {
+		return nil
+	}
+
+	epochs = sortBackfillEpochs(epochs)
+	from := epochs[0]
+	to := epochs[len(epochs)-1]
+
+	lastEpoch, found, err := db.GetBackfillCheckpoint(name, from)
+	if err != nil {
+		return fmt.Errorf("error retrieving backfill checkpoint of %v: %v", name, err)
+	}
+	if found {
+		logger.Infof("resuming backfill %v of epochs %v-%v after epoch %v", name, from, to, lastEpoch)
+		epochs = backfillEpochsAfter(epochs, lastEpoch)
+	}
+
+	total := len(epochs)
+	metrics.BackfillEpochsTotal.WithLabelValues(name).Set(float64(total))
+	metrics.BackfillEpochsDone.WithLabelValues(name).Set(0)
+	if total == 0 {
+		logger.Infof("backfill %v of epochs %v-%v is already complete", name, from, to)
+		err := db.DeleteBackfillCheckpoint(name, from)
+		if err != nil {
+			return fmt.Errorf("error deleting backfill checkpoint of %v: %v", name, err)
+		}
+		return nil
+	}
+
+	workers := utils.Config.Indexer.Backfill.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	prefetch := utils.Config.Indexer.Backfill.Prefetch
+	if prefetch < workers {
+		prefetch = workers
+	}
+	logger.Infof("starting backfill %v of %v epochs (%v-%v) with %v workers", name, total, from, to, workers)
+
+	// the window limits the number of epochs that are fetched but not yet saved
+	window := make(chan struct{}, prefetch)
+	jobs := make(chan uint64)
+	results := make(chan *backfillResult, prefetch)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(jobs)
+		for _, epoch := range epochs {
+			select {
+			case window <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- epoch:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for epoch := range jobs {
+				createEpochPartitions(epoch)
+
+				var data *types.EpochData
+				var err error
+				for try := 1; try <= backfillFetchRetries; try++ {
+					data, err = getEpochData(epoch, client)
+					if err == nil {
+						break
+					}
+					logger.Warnf("error fetching epoch %v for backfill %v (try %v/%v): %v", epoch, name, try, backfillFetchRetries, err)
+					time.Sleep(time.Second * time.Duration(try))
+				}
+
+				select {
+				case results <- &backfillResult{epoch: epoch, data: data, err: err}:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// results arrive out of order, they are buffered until every epoch before them has been saved
+	pending := make(map[uint64]*backfillResult, prefetch)
+	next := 0
+	failed := 0
+	start := time.Now()
+	for result := range results {
+		pending[result.epoch] = result
+
+		for next < total {
+			res, ok := pending[epochs[next]]
+			if !ok {
+				break
+			}
+			delete(pending, epochs[next])
+
+			exportErr := res.err
+			if exportErr == nil {
+				exportErr = db.SaveEpoch(res.data)
+			}
+			if exportErr != nil {
+				failed++
+				logger.Errorf("error exporting epoch %v of backfill %v: %v", res.epoch, name, exportErr)
+				recordExportFailure(res.epoch, exportErr)
+			}
+			// a resumed backfill has to start at the first failed epoch
+			if failed == 0 {
+				err := db.SaveBackfillCheckpoint(name, from, res.epoch)
+				if err != nil {
+					return fmt.Errorf("error saving backfill checkpoint of %v: %v", name, err)
+				}
+			}
+			<-window
+			next++
+
+			elapsed := time.Since(start)
+			eta := time.Duration(float64(elapsed) / float64(next) * float64(total-next))
+			metrics.BackfillEpochsDone.WithLabelValues(name).Set(float64(next))
+			metrics.BackfillETA.WithLabelValues(name).Set(eta.Seconds())
+			logger.WithFields(logrus.Fields{"backfill": name, "epoch": res.epoch, "done": next, "total": total, "eta": eta.Round(time.Second)}).Info("backfilled epoch")
+		}
+	}
+
+	if next < total {
+		return fmt.Errorf("error backfill %v stopped before epoch %v", name, epochs[next])
+	}
+	if failed > 0 {
+		return fmt.Errorf("error backfill %v of epochs %v-%v completed with %v failed epochs, they are retried by the regular export", name, from, to, failed)
+	}
+	err = db.DeleteBackfillCheckpoint(name, from)
+	if err != nil {
+		return fmt.Errorf("error deleting backfill checkpoint of %v: %v", name, err)
+	}
+	logger.Infof("completed backfill %v of epochs %v-%v, took %v", name, from, to, time.Since(start))
+	return nil
+}
+
+// sortBackfillEpochs will return the epochs in ascending order without duplicates, the given slice is not modified
+func sortBackfillEpochs(epochs []uint64) []uint64 {
+	if len(epochs) == 0 {
+		return nil
+	}
+	sorted := make([]uint64, len(epochs))
+	copy(sorted, epochs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	unique := sorted[:1]
+	for _, epoch := range sorted[1:] {
+		if epoch != unique[len(unique)-1] {
+			unique = append(unique, epoch)
+		}
+	}
+	return unique
+}
+
+// backfillEpochsAfter will return the sorted epochs that have not been saved by a backfill whose checkpoint is at
+// the given epoch
+func backfillEpochsAfter(epochs []uint64, lastEpoch uint64) []uint64 {
+	idx := sort.Search(len(epochs), func(i int) bool { return epochs[i] > lastEpoch })
+	return epochs[idx:]
+}
@@ -0,0 +1,71 @@
+package exporter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackfillResume(t *testing.T) {
+	tests := []struct {
+		name       string
+		epochs     []uint64
+		checkpoint *uint64
+		want       []uint64
+	}{
+		{
+			name:   "sorted without duplicates",
+			epochs: []uint64{7, 3, 5, 3, 4, 7},
+			want:   []uint64{3, 4, 5, 7},
+		},
+		{
+			name:       "resumed after the checkpoint",
+			epochs:     []uint64{10, 11, 12, 13, 14},
+			checkpoint: uint64Ptr(12),
+			want:       []uint64{13, 14},
+		},
+		{
+			name:       "checkpoint between the epochs",
+			epochs:     []uint64{10, 20, 30, 40},
+			checkpoint: uint64Ptr(25),
+			want:       []uint64{30, 40},
+		},
+		{
+			name:       "epochs added to the end after the interruption",
+			epochs:     []uint64{100, 101, 102, 103, 104, 105, 106},
+			checkpoint: uint64Ptr(103),
+			want:       []uint64{104, 105, 106},
+		},
+		{
+			name:       "checkpoint before the first epoch",
+			epochs:     []uint64{10, 11},
+			checkpoint: uint64Ptr(5),
+			want:       []uint64{10, 11},
+		},
+		{
+			name:       "complete",
+			epochs:     []uint64{10, 11, 12},
+			checkpoint: uint64Ptr(12),
+			want:       []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]uint64{}, tt.epochs...)
+			got := sortBackfillEpochs(input)
+			if tt.checkpoint != nil {
+				got = backfillEpochsAfter(got, *tt.checkpoint)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected epochs %v, got %v", tt.want, got)
+			}
+			if !reflect.DeepEqual(input, tt.epochs) {
+				t.Errorf("the epochs of the caller were modified to %v", input)
+			}
+		})
+	}
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
@@ -0,0 +1,86 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// blobSidecarsExportBatchSize is the number of blocks whose blob sidecars are exported per run
+const blobSidecarsExportBatchSize = 100
+
+// blobSidecarsRetryBaseBackoff is the delay before blob sidecars that could not be retrieved are requested again, it
+// doubles with every failed attempt up to blobSidecarsRetryMaxBackoff
+const blobSidecarsRetryBaseBackoff = time.Minute
+const blobSidecarsRetryMaxBackoff = time.Hour * 24
+
+// defaultMinEpochsForBlobSidecarsRequests is the number of epochs beacon-nodes keep blob sidecars for on mainnet
+const defaultMinEpochsForBlobSidecarsRequests = 4096
+
+func blobSidecarsExporter(client rpc.Client) {
+	provider, ok := client.(rpc.BlobSidecarProvider)
+	if !ok {
+		logger.Infof("beacon-node does not provide blob sidecars, blob sidecars will not be exported")
+		return
+	}
+
+	for {
+		t0 := time.Now()
+		err := exportBlobSidecars(provider)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting blob sidecars")
+		}
+		time.Sleep(time.Second * 12)
+	}
+}
+
+// exportBlobSidecars will export the blob sidecars of all blocks with blobs that the beacon-node has not pruned yet
+func exportBlobSidecars(provider rpc.BlobSidecarProvider) error {
+	currentEpoch := uint64(utils.TimeToEpoch(time.Now()))
+	if !utils.ForkFeatureActive(utils.ForkFeatureBlobs, currentEpoch) {
+		return nil
+	}
+
+	retention := utils.Config.Chain.Config.MinEpochsForBlobSidecarsRequests
+	if retention == 0 {
+		retention = defaultMinEpochsForBlobSidecarsRequests
+	}
+	minEpoch := utils.ForkFeatureEpoch(utils.ForkFeatureBlobs)
+	if currentEpoch > retention && currentEpoch-retention > minEpoch {
+		minEpoch = currentEpoch - retention
+	}
+
+	blocks, err := db.GetBlocksMissingBlobSidecars(minEpoch*utils.Config.Chain.Config.SlotsPerEpoch, blobSidecarsExportBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, block := range blocks {
+		sidecars, err := provider.GetBlobSidecars(&types.Block{Slot: block.Slot, BlockRoot: block.BlockRoot})
+		if err == nil && len(sidecars) == 0 {
+			err = fmt.Errorf("no blob sidecars found")
+		}
+		if err != nil {
+			// the sidecars of the other blocks can still be exported, the block is retried with a backoff so blocks
+			// whose sidecars have been pruned do not hold up newer blocks
+			logger.WithFields(logrus.Fields{"slot": block.Slot, "blockRoot": fmt.Sprintf("%x", block.BlockRoot)}).Warnf("error retrieving blob sidecars: %v", err)
+			err = db.SetBlobSidecarsRetry(block.Slot, block.BlockRoot, blobSidecarsRetryBaseBackoff, blobSidecarsRetryMaxBackoff)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = db.SaveBlobSidecars(sidecars)
+		if err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{"slot": block.Slot, "blobs": len(sidecars)}).Infof("exported blob sidecars")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package exporter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v4/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v4/config/params"
+	"github.com/prysmaticlabs/prysm/v4/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v4/proto/prysm/v1alpha1"
+	"github.com/sirupsen/logrus"
+)
+
+func blsChangePoolExporter(client rpc.Client) {
+	provider, ok := client.(rpc.BLSChangePoolProvider)
+	if !ok {
+		logger.Infof("beacon-node does not provide the bls to execution change pool, pending bls to execution changes will not be exported")
+		return
+	}
+
+	var domain []byte
+	for {
+		t0 := time.Now()
+		var err error
+		if domain == nil {
+			domain, err = blsChangeDomain(provider)
+		}
+		if err == nil {
+			err = exportBLSChangePool(provider, domain)
+		}
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting bls to execution change pool")
+		}
+		time.Sleep(time.Second * 12)
+	}
+}
+
+// blsChangeDomain will compute the signature domain of bls to execution changes, which is always based on the genesis fork version
+func blsChangeDomain(provider rpc.BLSChangePoolProvider) ([]byte, error) {
+	genesisValidatorsRoot, err := provider.GetGenesisValidatorsRoot()
+	if err != nil {
+		return nil, err
+	}
+	genForkVersion, err := hex.DecodeString(strings.Replace(utils.Config.Chain.Config.GenesisForkVersion, "0x", "", -1))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding genesis fork version: %v", err)
+	}
+	return signing.ComputeDomain(params.BeaconConfig().DomainBLSToExecutionChange, genForkVersion, genesisValidatorsRoot)
+}
+
+// exportBLSChangePool will save the bls to execution changes in the operation pool of the node and validate them
+// against the current withdrawal credentials of the validators
+func exportBLSChangePool(provider rpc.BLSChangePoolProvider, domain []byte) error {
+	if !utils.ForkFeatureActive(utils.ForkFeatureBLSToExecutionChanges, uint64(utils.TimeToEpoch(time.Now()))) {
+		return nil
+	}
+
+	changes, err := provider.GetBLSChangePool()
+	if err != nil {
+		return err
+	}
+
+	indices := make([]uint64, 0, len(changes))
+	for _, change := range changes {
+		indices = append(indices, change.Message.Validatorindex)
+	}
+	credentials, err := db.GetValidatorsWithdrawalCredentials(indices)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	pending := make([]*types.PendingBLSChange, 0, len(changes))
+	for _, change := range changes {
+		p := &types.PendingBLSChange{
+			Validatorindex: change.Message.Validatorindex,
+			BlsPubkey:      change.Message.BlsPubkey,
+			Address:        change.Message.Address,
+			Signature:      change.Signature,
+			ValidSignature: verifyBLSChangeSignature(change, domain) == nil,
+			PubkeyMatches:  blsPubkeyMatchesCredentials(change.Message.BlsPubkey, credentials[change.Message.Validatorindex]),
+			FirstSeen:      now,
+		}
+		if !p.Valid() {
+			logger.WithFields(logrus.Fields{"validator": p.Validatorindex, "validSignature": p.ValidSignature, "pubkeyMatches": p.PubkeyMatches}).Warnf("invalid bls to execution change in pool")
+		}
+		pending = append(pending, p)
+	}
+
+	return db.SaveBLSChangePool(pending)
+}
+
+func verifyBLSChangeSignature(change *types.SignedBLSToExecutionChange, domain []byte) error {
+	msg := &ethpb.BLSToExecutionChange{
+		ValidatorIndex:     primitives.ValidatorIndex(change.Message.Validatorindex),
+		FromBlsPubkey:      change.Message.BlsPubkey,
+		ToExecutionAddress: change.Message.Address,
+	}
+	return signing.VerifySigningRoot(msg, change.Message.BlsPubkey, change.Signature, domain)
+}
+
+// blsPubkeyMatchesCredentials checks if the credentials are 0x00 credentials derived from the bls pubkey
+func blsPubkeyMatchesCredentials(pubkey, credentials []byte) bool {
+	if len(credentials) != 32 || credentials[0] != 0x00 {
+		return false
+	}
+	pubkeyHash := sha256.Sum256(pubkey)
+	return bytes.Equal(pubkeyHash[1:], credentials[1:])
+}
@@ -0,0 +1,183 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// executionReceiptsExportBatchSize is the number of blocks whose receipts are exported per run
+const executionReceiptsExportBatchSize = 100
+
+// executionReceiptsRetryBaseBackoff is the delay before the receipts of a block that could not be exported are requested
+// again, it doubles with every failed attempt up to executionReceiptsRetryMaxBackoff
+const executionReceiptsRetryBaseBackoff = time.Minute
+const executionReceiptsRetryMaxBackoff = time.Hour * 24
+
+// eth1MaxBatchSize is the maximum number of requests sent in a single batch, many providers limit the size of batch requests
+const eth1MaxBatchSize = 100
+
+// executionReceipt holds the fields of a transaction receipt that are exported,
+// it is decoded manually as the go-ethereum version in use does not know all transaction types
+type executionReceipt struct {
+	Status            hexutil.Uint64  `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*executionLog `json:"logs"`
+}
+
+type executionLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	LogIndex hexutil.Uint64 `json:"logIndex"`
+}
+
+// executionReceiptsExporter will export the receipts and logs of the transactions of proposed blocks and compute
+// the priority fees the fee recipients of the blocks received
+func executionReceiptsExporter() {
+	if utils.Config.Indexer.Eth1Endpoint == "" {
+		logger.Infof("no eth1 endpoint configured, transaction receipts will not be exported")
+		return
+	}
+
+	client, err := gethRPC.Dial(utils.Config.Indexer.Eth1Endpoint)
+	if err != nil {
+		logger.Errorf("error dialing eth1 endpoint, transaction receipts will not be exported: %v", err)
+		return
+	}
+
+	for {
+		t0 := time.Now()
+		err := exportExecutionReceipts(client)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting transaction receipts")
+		}
+		time.Sleep(time.Second * 12)
+	}
+}
+
+func exportExecutionReceipts(client *gethRPC.Client) error {
+	blocks, err := db.GetBlocksMissingReceipts(executionReceiptsExportBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, block := range blocks {
+		var receipts []*executionReceipt
+		if uint64(len(block.TxHashes)) != block.TransactionsCount {
+			err = fmt.Errorf("block has %v transactions but %v are saved", block.TransactionsCount, len(block.TxHashes))
+		} else {
+			receipts, err = eth1BatchRequestReceipts(client, block.TxHashes)
+		}
+		if err != nil {
+			// the receipts of the other blocks can still be exported, the block is retried with a backoff so failing
+			// blocks do not fill up the batch
+			logger.WithFields(logrus.Fields{"slot": block.Slot, "blockRoot": fmt.Sprintf("%x", block.BlockRoot)}).Warnf("error exporting receipts: %v", err)
+			err = db.SetBlockReceiptsRetry(block.Slot, block.BlockRoot, executionReceiptsRetryBaseBackoff, executionReceiptsRetryMaxBackoff)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		txReceipts, priorityFees := convertReceipts(block, receipts)
+		err = db.SaveBlockReceipts(block.Slot, block.BlockRoot, txReceipts, priorityFees)
+		if err != nil {
+			return err
+		}
+	}
+	if len(blocks) > 0 {
+		logger.WithFields(logrus.Fields{"blocks": len(blocks)}).Infof("exported transaction receipts")
+	}
+	return nil
+}
+
+// eth1BatchRequestReceipts requests the receipts of the transactions in batches instead of one call per transaction
+func eth1BatchRequestReceipts(client *gethRPC.Client, txHashes [][]byte) ([]*executionReceipt, error) {
+	receipts := make([]*executionReceipt, len(txHashes))
+	elems := make([]gethRPC.BatchElem, len(txHashes))
+	for i, txHash := range txHashes {
+		receipts[i] = &executionReceipt{}
+		elems[i] = gethRPC.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{common.BytesToHash(txHash)},
+			Result: receipts[i],
+		}
+	}
+
+	for start := 0; start < len(elems); start += eth1MaxBatchSize {
+		end := start + eth1MaxBatchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		ioErr := client.BatchCall(elems[start:end])
+		if ioErr != nil {
+			return nil, ioErr
+		}
+	}
+
+	for i, e := range elems {
+		if e.Error != nil {
+			return nil, fmt.Errorf("error retrieving receipt of transaction %#x: %v", txHashes[i], e.Error)
+		}
+		// a missing receipt is returned as null and leaves the result untouched
+		if receipts[i].EffectiveGasPrice == nil {
+			return nil, fmt.Errorf("receipt of transaction %#x not found", txHashes[i])
+		}
+	}
+	return receipts, nil
+}
+
+// convertReceipts will convert the receipts of the transactions of a block and sum up their effective priority fees
+func convertReceipts(block *types.ExecutionRewardsBlock, receipts []*executionReceipt) ([]*types.TransactionReceipt, *big.Int) {
+	baseFee := new(big.Int).SetUint64(block.BaseFeePerGas)
+	priorityFees := new(big.Int)
+	txReceipts := make([]*types.TransactionReceipt, 0, len(receipts))
+	for i, receipt := range receipts {
+		tip := new(big.Int).Sub(receipt.EffectiveGasPrice.ToInt(), baseFee)
+		priorityFees.Add(priorityFees, tip.Mul(tip, new(big.Int).SetUint64(uint64(receipt.GasUsed))))
+
+		txReceipt := &types.TransactionReceipt{
+			BlockSlot:         block.Slot,
+			BlockIndex:        uint64(i),
+			BlockRoot:         block.BlockRoot,
+			TxHash:            block.TxHashes[i],
+			Status:            uint64(receipt.Status),
+			GasUsed:           uint64(receipt.GasUsed),
+			EffectiveGasPrice: receipt.EffectiveGasPrice.ToInt().String(),
+			LogsCount:         uint64(len(receipt.Logs)),
+			Logs:              make([]*types.TransactionLog, 0, len(receipt.Logs)),
+		}
+		if receipt.ContractAddress != nil {
+			txReceipt.ContractAddress = receipt.ContractAddress.Bytes()
+		}
+		for _, log := range receipt.Logs {
+			topics := make([][]byte, len(log.Topics))
+			for j, topic := range log.Topics {
+				topics[j] = topic.Bytes()
+			}
+			txReceipt.Logs = append(txReceipt.Logs, &types.TransactionLog{
+				BlockSlot:  block.Slot,
+				BlockRoot:  block.BlockRoot,
+				LogIndex:   uint64(log.LogIndex),
+				BlockIndex: uint64(i),
+				TxHash:     block.TxHashes[i],
+				Address:    log.Address.Bytes(),
+				Topics:     topics,
+				Data:       log.Data,
+			})
+		}
+		txReceipts = append(txReceipts, txReceipt)
+	}
+	return txReceipts, priorityFees
+}
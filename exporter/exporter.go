@@ -17,10 +17,11 @@ import (
 
 var logger = logrus.New().WithField("module", "exporter")
 
-// If exporting an epoch fails for 10 consecutive times exporting this epoch will be disabled
-// This is a workaround for a bug in the prysm archive node that causes epochs without blocks
+// If exporting an epoch fails, it is recorded in the export_failures table and retried with an exponential backoff
+// This was introduced as a workaround for a bug in the prysm archive node that causes epochs without blocks
 // to not be archived properly (see https://github.com/prysmaticlabs/prysm/issues/4165)
-var epochBlacklist = make(map[uint64]uint64)
+const exportFailureBaseBackoff = time.Minute
+const exportFailureMaxBackoff = time.Hour * 24
 
 // Start will start the export of data from rpc into the database
 func Start(client rpc.Client) error {
@@ -31,6 +32,12 @@ func Start(client rpc.Client) error {
 	go checkSubscriptions()
 	go cleanupOldMachineStats()
 	go syncCommitteesExporter(client)
+	go blobSidecarsExporter(client)
+	go executionReceiptsExporter()
+	go blsChangePoolExporter(client)
+	go attestationCorrectnessExporter()
+	go proposerDutiesExporter(client)
+	go withdrawalSweepExporter()
 	if utils.Config.SSVExporter.Enabled {
 		go ssvExporter()
 	}
@@ -45,6 +52,10 @@ func Start(client rpc.Client) error {
 		go UpdatePubkeyTag()
 	}
 
+	if utils.Config.Indexer.RewardsExporter.Enabled {
+		go rewardsExporter(client)
+	}
+
 	// wait until the beacon-node is available
 	for {
 		_, err := client.GetChainHead()
@@ -62,12 +73,13 @@ func Start(client rpc.Client) error {
 			logger.Fatal(err)
 		}
 
+		epochs := make([]uint64, 0, head.HeadEpoch)
 		for epoch := uint64(1); epoch <= head.HeadEpoch; epoch++ {
-			err := ExportEpoch(epoch, client)
-
-			if err != nil {
-				logger.Error(err)
-			}
+			epochs = append(epochs, epoch)
+		}
+		err = Backfill("fullindex", epochs, client)
+		if err != nil {
+			logger.Error(err)
 		}
 	}
 
@@ -78,8 +90,8 @@ func Start(client rpc.Client) error {
 			logger.Fatal(err)
 		}
 
-		for epoch := head.HeadEpoch - 1; epoch >= 0; epoch-- {
-			blocks, err := client.GetBlockStatusByEpoch(epoch)
+		for epoch := int64(head.HeadEpoch) - 1; epoch >= 0; epoch-- {
+			blocks, err := client.GetBlockStatusByEpoch(uint64(epoch))
 			if err != nil {
 				logger.Errorf("error retrieving block status: %v", err)
 				continue
@@ -99,12 +111,9 @@ func Start(client rpc.Client) error {
 			logger.Fatal(err)
 		}
 
+		missingEpochs := []uint64{}
 		if len(epochs) > 0 && epochs[0] != 0 {
-			err := ExportEpoch(0, client)
-			if err != nil {
-				logger.Error(err)
-			}
-			logger.Printf("finished export for epoch %v", 0)
+			missingEpochs = append(missingEpochs, 0)
 			epochs = append([]uint64{0}, epochs...)
 		}
 
@@ -113,14 +122,15 @@ func Start(client rpc.Client) error {
 				logger.Println("Epochs between", epochs[i], "and", epochs[i+1], "are missing!")
 
 				for epoch := epochs[i]; epoch <= epochs[i+1]; epoch++ {
-					err := ExportEpoch(epoch, client)
-					if err != nil {
-						logger.Error(err)
-					}
-					logger.Printf("finished export for epoch %v", epoch)
+					missingEpochs = append(missingEpochs, epoch)
 				}
 			}
 		}
+
+		err = Backfill("missingepochs", missingEpochs, client)
+		if err != nil {
+			logger.Error(err)
+		}
 	}
 
 	if utils.Config.Indexer.CheckAllBlocksOnStartup {
@@ -140,42 +150,11 @@ func Start(client rpc.Client) error {
 			logger.Fatal(err)
 		}
 
-		blocksMap := make(map[string]*types.BlockComparisonContainer)
-
-		for _, block := range dbBlocks {
-			key := fmt.Sprintf("%v-%x", block.Slot, block.BlockRoot)
-			_, found := blocksMap[key]
-
-			if !found {
-				blocksMap[key] = &types.BlockComparisonContainer{Epoch: block.Epoch}
-			}
-
-			blocksMap[key].Db = block
-		}
-		for _, block := range nodeBlocks {
-			key := fmt.Sprintf("%v-%x", block.Slot, block.BlockRoot)
-			_, found := blocksMap[key]
-
-			if !found {
-				blocksMap[key] = &types.BlockComparisonContainer{Epoch: block.Epoch}
-			}
-
-			blocksMap[key].Node = block
-		}
-
 		epochsToExport := make(map[uint64]bool)
 
-		for key, block := range blocksMap {
-			if block.Db == nil {
-				logger.Printf("queuing epoch %v for export as block %v is present on the node but missing in the db", block.Epoch, key)
-				epochsToExport[block.Epoch] = true
-			} else if block.Node == nil {
-				logger.Printf("queuing epoch %v for export as block %v is present on the db but missing in the node", block.Epoch, key)
-				epochsToExport[block.Epoch] = true
-			} else if bytes.Compare(block.Db.BlockRoot, block.Node.BlockRoot) != 0 {
-				logger.Printf("queuing epoch %v for export as block %v has a different hash in the db as on the node", block.Epoch, key)
-				epochsToExport[block.Epoch] = true
-			}
+		for _, mismatch := range CompareBlocks(dbBlocks, nodeBlocks) {
+			logger.Printf("queuing epoch %v for export as block %v %v", mismatch.Epoch, mismatch.Key, mismatch.Reason)
+			epochsToExport[mismatch.Epoch] = true
 		}
 
 		logger.Printf("exporting %v epochs.", len(epochsToExport))
@@ -189,13 +168,9 @@ func Start(client rpc.Client) error {
 		})
 
 		for _, epoch := range keys {
-			err = ExportEpoch(epoch, client)
-
+			err = exportEpochAndRecordFailure(epoch, client)
 			if err != nil {
 				logger.Errorf("error exporting epoch: %v", err)
-				if utils.EpochToTime(epoch).Before(time.Now().Add(time.Hour * -24)) {
-					epochBlacklist[epoch]++
-				}
 			}
 		}
 	}
@@ -215,6 +190,12 @@ func Start(client rpc.Client) error {
 
 	newBlockChan := client.GetNewBlockChan()
 
+	// a nil channel is never selected, so clients without an event stream only deliver blocks
+	var chainEventChan chan *types.ChainEvent
+	if subscriber, ok := client.(rpc.ChainEventSubscriber); ok {
+		chainEventChan = subscriber.GetChainEventChan()
+	}
+
 	lastExportedSlot := uint64(0)
 
 	doFullCheck(client)
@@ -222,6 +203,11 @@ func Start(client rpc.Client) error {
 	for {
 		select {
 		case block := <-newBlockChan:
+			err := checkReorg(block, client)
+			if err != nil {
+				logger.Errorf("error checking block %x at slot %v for a reorg: %v", block.BlockRoot, block.Slot, err)
+			}
+
 			// Do a full check on any epoch transition or after during the first run
 			if utils.EpochOfSlot(lastExportedSlot) != utils.EpochOfSlot(block.Slot) || utils.EpochOfSlot(block.Slot) == 0 {
 				doFullCheck(client)
@@ -240,6 +226,18 @@ func Start(client rpc.Client) error {
 				}
 			}
 			lastExportedSlot = block.Slot
+		case event := <-chainEventChan:
+			if event.Reorg != nil {
+				logger.Warnf("chain reorg of depth %v at slot %v detected, checking exported blocks", event.Reorg.Depth, event.Reorg.Slot)
+				doFullCheck(client)
+			}
+			if event.Finalized != nil {
+				logger.Infof("epoch %v has been finalized", event.Finalized.Epoch)
+				err := db.MarkEpochsFinalized(event.Finalized.Epoch)
+				if err != nil {
+					logger.Errorf("error marking epochs before %v as finalized: %v", event.Finalized.Epoch, err)
+				}
+			}
 		}
 	}
 
@@ -288,42 +286,11 @@ func doFullCheck(client rpc.Client) {
 	// If a block is missing on the node that has been exported in the db
 	// or if a block is missing in the db that is present in the node
 	// export this epoch to the db again
-	blocksMap := make(map[string]*types.BlockComparisonContainer)
-
-	for _, block := range dbBlocks {
-		key := fmt.Sprintf("%v-%x", block.Slot, block.BlockRoot)
-		_, found := blocksMap[key]
-
-		if !found {
-			blocksMap[key] = &types.BlockComparisonContainer{Epoch: block.Epoch}
-		}
-
-		blocksMap[key].Db = block
-	}
-	for _, block := range nodeBlocks {
-		key := fmt.Sprintf("%v-%x", block.Slot, block.BlockRoot)
-		_, found := blocksMap[key]
-
-		if !found {
-			blocksMap[key] = &types.BlockComparisonContainer{Epoch: block.Epoch}
-		}
-
-		blocksMap[key].Node = block
-	}
-
 	epochsToExport := make(map[uint64]bool)
 
-	for key, block := range blocksMap {
-		if block.Db == nil {
-			logger.Printf("queuing epoch %v for export as block %v is present on the node but missing in the db", block.Epoch, key)
-			epochsToExport[block.Epoch] = true
-		} else if block.Node == nil {
-			logger.Printf("queuing epoch %v for export as block %v is present on the db but missing in the node", block.Epoch, key)
-			epochsToExport[block.Epoch] = true
-		} else if bytes.Compare(block.Db.BlockRoot, block.Node.BlockRoot) != 0 {
-			logger.Printf("queuing epoch %v for export as block %v has a different hash in the db as on the node", block.Epoch, key)
-			epochsToExport[block.Epoch] = true
-		}
+	for _, mismatch := range CompareBlocks(dbBlocks, nodeBlocks) {
+		logger.Printf("queuing epoch %v for export as block %v %v", mismatch.Epoch, mismatch.Key, mismatch.Reason)
+		epochsToExport[mismatch.Epoch] = true
 	}
 
 	// Add any missing epoch to the export set (might happen if the indexer was stopped for a long period of time)
@@ -370,21 +337,24 @@ func doFullCheck(client rpc.Client) {
 		return keys[i] < keys[j]
 	})
 
+	failures, err := db.GetExportFailuresAwaitingRetry()
+	if err != nil {
+		logger.Errorf("error retrieving export failures: %v", err)
+		failures = map[uint64]*types.ExportFailure{}
+	}
+
 	for _, epoch := range keys {
-		if epochBlacklist[epoch] > 3 {
-			logger.Printf("skipping export of epoch %v as it has errored %d times", epoch, epochBlacklist[epoch])
+		if failure, found := failures[epoch]; found {
+			logger.Printf("skipping export of epoch %v until %v as it has errored %d times", epoch, failure.NextRetryAt, failure.Attempts)
 			continue
 		}
 
 		logger.Printf("exporting epoch %v", epoch)
 
-		err = ExportEpoch(epoch, client)
+		err = exportEpochAndRecordFailure(epoch, client)
 
 		if err != nil {
 			logger.Errorf("error exporting epoch: %v", err)
-			if utils.EpochToTime(epoch).Before(time.Now().Add(time.Hour * -24)) {
-				epochBlacklist[epoch]++
-			}
 		}
 		logger.Printf("finished export for epoch %v", epoch)
 	}
@@ -453,6 +423,58 @@ func GetLastBlocks(startEpoch, endEpoch uint64, client rpc.Client) ([]*types.Min
 	return wrappedBlocks, nil
 }
 
+// BlockMismatch describes a block that differs between the db and the node
+type BlockMismatch struct {
+	Key    string
+	Epoch  uint64
+	Reason string
+}
+
+// CompareBlocks will compare the blocks in the db with the blocks on the node and return all blocks that differ
+func CompareBlocks(dbBlocks, nodeBlocks []*types.MinimalBlock) []*BlockMismatch {
+	blocksMap := make(map[string]*types.BlockComparisonContainer)
+
+	for _, block := range dbBlocks {
+		key := fmt.Sprintf("%v-%x", block.Slot, block.BlockRoot)
+		_, found := blocksMap[key]
+
+		if !found {
+			blocksMap[key] = &types.BlockComparisonContainer{Epoch: block.Epoch}
+		}
+
+		blocksMap[key].Db = block
+	}
+	for _, block := range nodeBlocks {
+		key := fmt.Sprintf("%v-%x", block.Slot, block.BlockRoot)
+		_, found := blocksMap[key]
+
+		if !found {
+			blocksMap[key] = &types.BlockComparisonContainer{Epoch: block.Epoch}
+		}
+
+		blocksMap[key].Node = block
+	}
+
+	mismatches := make([]*BlockMismatch, 0)
+	for key, block := range blocksMap {
+		if block.Db == nil {
+			mismatches = append(mismatches, &BlockMismatch{Key: key, Epoch: block.Epoch, Reason: "is present on the node but missing in the db"})
+		} else if block.Node == nil {
+			mismatches = append(mismatches, &BlockMismatch{Key: key, Epoch: block.Epoch, Reason: "is present on the db but missing in the node"})
+		} else if bytes.Compare(block.Db.BlockRoot, block.Node.BlockRoot) != 0 {
+			mismatches = append(mismatches, &BlockMismatch{Key: key, Epoch: block.Epoch, Reason: "has a different hash in the db as on the node"})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Epoch != mismatches[j].Epoch {
+			return mismatches[i].Epoch < mismatches[j].Epoch
+		}
+		return mismatches[i].Key < mismatches[j].Key
+	})
+	return mismatches
+}
+
 // ExportEpoch will export an epoch from rpc into the database
 func ExportEpoch(epoch uint64, client rpc.Client) error {
 	start := time.Now()
@@ -461,6 +483,18 @@ func ExportEpoch(epoch uint64, client rpc.Client) error {
 		logger.WithFields(logrus.Fields{"duration": time.Since(start), "epoch": epoch}).Info("completed exporting epoch")
 	}()
 
+	createEpochPartitions(epoch)
+
+	data, err := getEpochData(epoch, client)
+	if err != nil {
+		return err
+	}
+
+	return db.SaveEpoch(data)
+}
+
+// createEpochPartitions will create the partitions of the validator_balances, attestation_assignments and sync_assignments tables for the epoch if they do not exist yet
+func createEpochPartitions(epoch uint64) {
 	// Check if the partition for the validator_balances and attestation_assignments and sync_assignments table for this epoch exists
 	var one int
 	logger.Printf("checking partition status for epoch %v", epoch)
@@ -489,22 +523,61 @@ func ExportEpoch(epoch uint64, client rpc.Client) error {
 			logger.Fatalf("unable to create partition sync_assignments_%v: %v", week, err)
 		}
 	}
+}
+
+// exportEpochAndRecordFailure will export an epoch and keep the export_failures ledger up to date
+func exportEpochAndRecordFailure(epoch uint64, client rpc.Client) error {
+	exportErr := ExportEpoch(epoch, client)
+
+	if exportErr == nil {
+		err := db.DeleteExportFailure(epoch)
+		if err != nil {
+			logger.Errorf("error removing epoch %v from the export failures: %v", epoch, err)
+		}
+		updateExportFailureCount()
+	} else {
+		recordExportFailure(epoch, exportErr)
+	}
 
-	startGetEpochData := time.Now()
+	return exportErr
+}
+
+// recordExportFailure will add a failed epoch to the export_failures ledger, the epoch is retried with a backoff
+func recordExportFailure(epoch uint64, exportErr error) {
+	failure, err := db.SaveExportFailure(epoch, exportErr, exportFailureBaseBackoff, exportFailureMaxBackoff)
+	if err != nil {
+		logger.Errorf("error recording export failure of epoch %v: %v", epoch, err)
+	} else {
+		logger.Warnf("export of epoch %v failed %v times, next retry at %v", epoch, failure.Attempts, failure.NextRetryAt)
+	}
+	updateExportFailureCount()
+}
+
+func updateExportFailureCount() {
+	count, err := db.GetExportFailureCount()
+	if err != nil {
+		logger.Errorf("error retrieving export failure count: %v", err)
+		return
+	}
+	metrics.ExportFailures.Set(float64(count))
+}
+
+// getEpochData will retrieve the data of an epoch from the node
+func getEpochData(epoch uint64, client rpc.Client) (*types.EpochData, error) {
+	start := time.Now()
 	logger.Printf("retrieving data for epoch %v", epoch)
 	data, err := client.GetEpochData(epoch)
 	if err != nil {
-		return fmt.Errorf("error retrieving epoch data: %v", err)
+		return nil, fmt.Errorf("error retrieving epoch data: %v", err)
 	}
-	metrics.TaskDuration.WithLabelValues("rpc_get_epoch_data").Observe(time.Since(startGetEpochData).Seconds())
-	logger.WithFields(logrus.Fields{"duration": time.Since(startGetEpochData), "epoch": epoch}).Info("completed getting epoch-data")
-	logger.Printf("data for epoch %v retrieved, took %v", epoch, time.Since(start))
+	metrics.TaskDuration.WithLabelValues("rpc_get_epoch_data").Observe(time.Since(start).Seconds())
+	logger.WithFields(logrus.Fields{"duration": time.Since(start), "epoch": epoch}).Info("completed getting epoch-data")
 
 	if len(data.Validators) == 0 {
-		return fmt.Errorf("error retrieving epoch data: no validators received for epoch")
+		return nil, fmt.Errorf("error retrieving epoch data: no validators received for epoch")
 	}
 
-	return db.SaveEpoch(data)
+	return data, nil
 }
 
 func exportValidatorQueue(client rpc.Client) error {
@@ -517,7 +590,16 @@ func exportValidatorQueue(client rpc.Client) error {
 }
 
 func updateEpochStatus(client rpc.Client, startEpoch, endEpoch uint64) error {
+	// older epochs whose participation could not be retrieved when they were exported are retried as well
+	epochs, err := db.GetEpochsMissingParticipation(startEpoch, 100)
+	if err != nil {
+		return err
+	}
 	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		epochs = append(epochs, epoch)
+	}
+
+	for _, epoch := range epochs {
 		epochParticipationStats, err := client.GetValidatorParticipation(epoch)
 		if err != nil {
 			logger.Printf("error retrieving epoch participation statistics: %v", err)
@@ -0,0 +1,54 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/utils"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func proposerDutiesExporter(client rpc.Client) {
+	provider, ok := client.(rpc.ProposerDutiesProvider)
+	if !ok {
+		logger.Infof("beacon-node does not provide proposer duties, upcoming proposals will not be exported")
+		return
+	}
+
+	for {
+		t0 := time.Now()
+		err := exportProposerDuties(provider)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting proposer duties")
+		}
+		time.Sleep(time.Second * 12)
+	}
+}
+
+// exportProposerDuties will save the proposer duties of the current and the next epoch and delete the duties of past epochs
+func exportProposerDuties(provider rpc.ProposerDutiesProvider) error {
+	epoch := uint64(utils.TimeToEpoch(time.Now()))
+
+	duties, err := provider.GetProposerDuties(epoch)
+	if err != nil {
+		return err
+	}
+	err = db.SaveProposerDuties(epoch, duties)
+	if err != nil {
+		return err
+	}
+
+	// the duties of the next epoch are only a lookahead, the current epoch is still useful without them
+	nextDuties, err := provider.GetProposerDuties(epoch + 1)
+	if err != nil {
+		logger.WithFields(logrus.Fields{"error": err, "epoch": epoch + 1}).Warnf("error retrieving proposer duties of next epoch")
+	} else {
+		err = db.SaveProposerDuties(epoch+1, nextDuties)
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.DeleteProposerDutiesBefore(epoch)
+}
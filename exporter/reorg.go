@@ -0,0 +1,136 @@
+package exporter
+
+import (
+	"bytes"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"fmt"
+	"time"
+)
+
+// maxReorgDepth is the maximum number of slots walked back from the stored head to find the common ancestor of a reorg
+const maxReorgDepth = 64
+
+// checkReorg will compare a new block with the head stored in the db. If the node considers the block canonical and it
+// does not build on the stored head, the orphaned blocks back to the common ancestor are rolled back, the reorg is
+// recorded and the slots between the common ancestor and the new block are exported again. Side-fork and late blocks
+// that the node did not make canonical are ignored.
+func checkReorg(block *types.Block, client rpc.Client) error {
+	head, err := db.GetHeadBlock()
+	if err != nil {
+		return err
+	}
+	if head == nil || bytes.Equal(block.ParentRoot, head.BlockRoot) || bytes.Equal(block.BlockRoot, head.BlockRoot) {
+		return nil
+	}
+
+	if !block.Canonical {
+		nodeHead, err := client.GetChainHead()
+		if err != nil {
+			return fmt.Errorf("error retrieving chain head: %v", err)
+		}
+		if !bytes.Equal(nodeHead.HeadBlockRoot, block.BlockRoot) {
+			return nil
+		}
+	}
+
+	if block.Slot <= head.Slot {
+		storedRoot, err := db.GetProposedBlockRoot(block.Slot)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(storedRoot, block.BlockRoot) {
+			return nil
+		}
+	}
+
+	// walk back from the stored head until the node and the db agree on the block of a slot
+	slot := head.Slot
+	if block.Slot > head.Slot {
+		slot = block.Slot - 1
+	}
+
+	orphaned := make([][]byte, 0)
+	ancestorSlot := uint64(0)
+	found := false
+	for ; slot+maxReorgDepth > head.Slot; slot-- {
+		storedRoot, err := db.GetProposedBlockRoot(slot)
+		if err != nil {
+			return err
+		}
+
+		var nodeRoot []byte
+		if slot == block.Slot {
+			nodeRoot = block.BlockRoot
+		} else if storedRoot != nil {
+			nodeBlocks, err := client.GetBlocksBySlot(slot)
+			if err != nil {
+				return fmt.Errorf("error retrieving blocks at slot %v: %v", slot, err)
+			}
+			for _, nodeBlock := range nodeBlocks {
+				if nodeBlock.Canonical {
+					nodeRoot = nodeBlock.BlockRoot
+				}
+			}
+		}
+
+		// a slot without a canonical block on the node proves nothing, the stored block is left to the canonical status check
+		if storedRoot != nil && nodeRoot != nil {
+			if bytes.Equal(storedRoot, nodeRoot) {
+				ancestorSlot = slot
+				found = true
+				break
+			}
+			orphaned = append(orphaned, storedRoot)
+		}
+		if slot == 0 {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("error no common ancestor of block %x at slot %v and head %x at slot %v found within %v slots", block.BlockRoot, block.Slot, head.BlockRoot, head.Slot, maxReorgDepth)
+	}
+	if len(orphaned) == 0 {
+		// the parent of the block has not been exported yet, the gap is filled by the regular export
+		return nil
+	}
+
+	reorg := &types.Reorg{
+		Slot:               block.Slot,
+		Depth:              head.Slot - ancestorSlot,
+		CommonAncestorSlot: ancestorSlot,
+		OldHeadSlot:        head.Slot,
+		OldHeadRoot:        head.BlockRoot,
+		NewHeadSlot:        block.Slot,
+		NewHeadRoot:        block.BlockRoot,
+		OrphanedBlocks:     uint64(len(orphaned)),
+		DetectedAt:         time.Now(),
+	}
+	logger.Warnf("chain reorg of depth %v detected at slot %v: head %x at slot %v replaced by %x at slot %v, %v blocks orphaned", reorg.Depth, reorg.Slot, reorg.OldHeadRoot, reorg.OldHeadSlot, reorg.NewHeadRoot, reorg.NewHeadSlot, reorg.OrphanedBlocks)
+
+	err = db.SaveReorg(reorg, orphaned)
+	if err != nil {
+		return err
+	}
+
+	for slot := ancestorSlot + 1; slot < block.Slot; slot++ {
+		blocks, err := client.GetBlocksBySlot(slot)
+		if err != nil {
+			return fmt.Errorf("error retrieving blocks at slot %v: %v", slot, err)
+		}
+		for _, nodeBlock := range blocks {
+			data, err := client.GetSlotData(nodeBlock)
+			if err != nil {
+				return fmt.Errorf("error retrieving slot data of slot %v: %v", slot, err)
+			}
+			err = db.SaveSlot(data)
+			if err != nil {
+				return fmt.Errorf("error saving slot %v: %v", slot, err)
+			}
+		}
+	}
+
+	return nil
+}
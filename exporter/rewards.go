@@ -0,0 +1,98 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// rewardsExportBatchSize is the number of epochs whose rewards are exported per run
+const rewardsExportBatchSize = 10
+
+// rewardsRetryBaseBackoff is the delay before the rewards of an epoch that could not be retrieved are requested again,
+// it doubles with every failed attempt up to rewardsRetryMaxBackoff. After rewardsExportMaxAttempts failed attempts the
+// rewards of the epoch are considered unavailable, e.g. because every node pruned the state of the epoch.
+const rewardsRetryBaseBackoff = time.Minute
+const rewardsRetryMaxBackoff = time.Hour * 24
+const rewardsExportMaxAttempts = 10
+
+func rewardsExporter(client rpc.Client) {
+	provider, ok := client.(rpc.RewardsProvider)
+	if !ok {
+		logger.Infof("beacon-node does not provide rewards, validator rewards will not be exported")
+		return
+	}
+
+	for {
+		t0 := time.Now()
+		err := exportRewards(client, provider)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting validator rewards")
+		}
+		time.Sleep(time.Second * 12)
+	}
+}
+
+// exportRewards will export the validator rewards of the latest finalized epochs that have not been exported yet
+func exportRewards(client rpc.Client, provider rpc.RewardsProvider) error {
+	head, err := client.GetChainHead()
+	if err != nil {
+		return fmt.Errorf("error retrieving chain head: %v", err)
+	}
+
+	minEpoch := uint64(0)
+	history := utils.Config.Indexer.RewardsExporter.HistoryEpochs
+	if history > 0 && head.FinalizedEpoch > history {
+		minEpoch = head.FinalizedEpoch - history
+	}
+
+	epochs, err := db.GetEpochsMissingRewards(minEpoch, rewardsExportMaxAttempts, rewardsExportBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, epoch := range epochs {
+		t0 := time.Now()
+		rewards, err := provider.GetEpochRewards(epoch)
+		if err != nil {
+			attempts, retryErr := db.SetRewardsRetry(epoch, rewardsRetryBaseBackoff, rewardsRetryMaxBackoff)
+			if retryErr != nil {
+				logger.WithError(retryErr).Errorf("error delaying rewards export of epoch %v", epoch)
+			} else if attempts >= rewardsExportMaxAttempts {
+				logger.Warnf("giving up on the rewards of epoch %v after %v failed attempts", epoch, attempts)
+			}
+			// older epochs will fail as well if the node already pruned the state of this epoch
+			return fmt.Errorf("error retrieving rewards of epoch %v: %w", epoch, err)
+		}
+
+		err = createRewardsPartition(epoch / 1575)
+		if err != nil {
+			return err
+		}
+
+		err = db.SaveValidatorRewards(epoch, rewards)
+		if err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{"epoch": epoch, "validators": len(rewards), "duration": time.Since(t0)}).Infof("exported validator rewards")
+	}
+	return nil
+}
+
+func createRewardsPartition(week uint64) error {
+	var one int
+	err := db.WriterDb.Get(&one, fmt.Sprintf("SELECT 1 FROM information_schema.tables WHERE table_name = 'validator_rewards_%v'", week))
+	if err == nil {
+		return nil
+	}
+	logger.Infof("creating partition validator_rewards_%v", week)
+	_, err = db.WriterDb.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS validator_rewards_%v PARTITION OF validator_rewards_p FOR VALUES IN (%v);", week, week))
+	if err != nil {
+		return fmt.Errorf("error creating partition validator_rewards_%v: %v", week, err)
+	}
+	return nil
+}
@@ -35,7 +35,7 @@ func exportSyncCommittees(rpcClient rpc.Client) error {
 	}
 	currEpoch := utils.TimeToEpoch(time.Now())
 	lastPeriod := utils.SyncPeriodOfEpoch(uint64(currEpoch)) + 1 // we can look into the future
-	firstPeriod := utils.SyncPeriodOfEpoch(utils.Config.Chain.Config.AltairForkEpoch)
+	firstPeriod := utils.SyncPeriodOfEpoch(utils.ForkFeatureEpoch(utils.ForkFeatureSyncCommittees))
 	for p := firstPeriod; p <= lastPeriod && p > 0; p++ {
 		_, exists := dbPeriodsMap[p]
 		if !exists {
@@ -60,9 +60,9 @@ func exportSyncCommitteeAtPeriod(rpcClient rpc.Client, p uint64) error {
 		stateID = utils.FirstEpochOfSyncPeriod(p-1) * utils.Config.Chain.Config.SlotsPerEpoch
 	}
 	epoch := utils.FirstEpochOfSyncPeriod(p)
-	if stateID/utils.Config.Chain.Config.SlotsPerEpoch <= utils.Config.Chain.Config.AltairForkEpoch {
-		stateID = utils.Config.Chain.Config.AltairForkEpoch * utils.Config.Chain.Config.SlotsPerEpoch
-		epoch = utils.Config.Chain.Config.AltairForkEpoch
+	if syncCommitteesEpoch := utils.ForkFeatureEpoch(utils.ForkFeatureSyncCommittees); stateID/utils.Config.Chain.Config.SlotsPerEpoch <= syncCommitteesEpoch {
+		stateID = syncCommitteesEpoch * utils.Config.Chain.Config.SlotsPerEpoch
+		epoch = syncCommitteesEpoch
 	}
 
 	firstEpoch := utils.FirstEpochOfSyncPeriod(p)
@@ -0,0 +1,225 @@
+package exporter
+
+import (
+	"bytes"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// withdrawalSweepResult holds the result of a simulation of the withdrawal sweep
+type withdrawalSweepResult struct {
+	predictions []*types.WithdrawalPrediction
+	byIndex     map[uint64]*types.WithdrawalPrediction
+	// incomePerSlot is the average income in gwei of the validators with execution withdrawal credentials per slot
+	incomePerSlot map[uint64]uint64
+	// cycleSlots is the number of slots the sweep needs to pass all validators once
+	cycleSlots uint64
+}
+
+// withdrawalSweepExporter will simulate the withdrawal sweep once per epoch and save the predicted withdrawals, which
+// the frontends read from the database
+func withdrawalSweepExporter() {
+	sleepDuration := time.Duration(utils.Config.Chain.Config.SecondsPerSlot*utils.Config.Chain.Config.SlotsPerEpoch) * time.Second
+
+	for {
+		t0 := time.Now()
+		err := exportWithdrawalPredictions()
+		if err != nil {
+			logger.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error predicting withdrawal sweep")
+		}
+		time.Sleep(sleepDuration)
+	}
+}
+
+func exportWithdrawalPredictions() error {
+	latestEpoch, err := db.GetLatestEpoch()
+	if err != nil {
+		return err
+	}
+	if !utils.ForkFeatureActive(utils.ForkFeatureWithdrawals, latestEpoch) {
+		return nil
+	}
+
+	t0 := time.Now()
+	result, err := predictWithdrawalSweep()
+	if err != nil || result == nil {
+		return err
+	}
+
+	err = db.SaveWithdrawalPredictions(result.predictions)
+	if err != nil {
+		return err
+	}
+	logger.WithFields(logrus.Fields{"validators": len(result.predictions), "cycleSlots": result.cycleSlots, "duration": time.Since(t0)}).Info("withdrawal sweep prediction completed")
+	return nil
+}
+
+// predictWithdrawalSweep will load the current state of the validators and simulate the withdrawal sweep starting
+// with the slot after the latest slot, returns nil if no withdrawal has been processed yet
+func predictWithdrawalSweep() (*withdrawalSweepResult, error) {
+	position, err := db.GetWithdrawalSweepPosition()
+	if err != nil || position == nil {
+		return nil, err
+	}
+
+	validators, err := db.GetWithdrawalSweepValidators()
+	if err != nil {
+		return nil, err
+	}
+	if len(validators) == 0 {
+		return nil, nil
+	}
+	for i, v := range validators {
+		if v.Validatorindex != uint64(i) {
+			return nil, fmt.Errorf("validator %v is missing in the database", i)
+		}
+	}
+
+	n := uint64(len(validators))
+	bound := utils.Config.Chain.Config.MaxValidatorsPerWithdrawalSweep
+	if n < bound {
+		bound = n
+	}
+
+	// a full payload moves the cursor behind the last withdrawn validator, otherwise the sweep advanced by the full bound.
+	// The start of the sweep is unknown for non-full payloads, the first withdrawn validator is the closest known position
+	var cursor uint64
+	if position.Count == utils.Config.Chain.Config.MaxWithdrawalsPerPayload {
+		cursor = (position.LastValidatorindex + 1) % n
+	} else {
+		cursor = (position.FirstValidatorindex + bound) % n
+	}
+	// blocks without withdrawals swept the full bound without finding a withdrawable validator
+	cursor = (cursor + position.BlocksSince*bound) % n
+
+	startSlot := position.LatestSlot + 1
+	if startSlot <= position.Slot {
+		startSlot = position.Slot + 1
+	}
+	return simulateWithdrawalSweep(validators, cursor, startSlot), nil
+}
+
+// simulateWithdrawalSweep will reproduce the withdrawal sweep of the spec (get_expected_withdrawals) slot by slot until
+// every validator has been visited once, assuming that a block is proposed in every slot. Validators that are not
+// withdrawable when the sweep visits them are predicted for the cycle in which they become withdrawable.
+func simulateWithdrawalSweep(validators []*types.WithdrawalSweepValidator, cursor, startSlot uint64) *withdrawalSweepResult {
+	cfg := utils.Config.Chain.Config
+	n := uint64(len(validators))
+	bound := cfg.MaxValidatorsPerWithdrawalSweep
+	if n < bound {
+		bound = n
+	}
+
+	balances := make([]uint64, n)
+	for i, v := range validators {
+		balances[i] = v.Balance
+	}
+	visited := make([]bool, n)
+	visitedAt := make([]uint64, n)
+	visitedCount := uint64(0)
+
+	result := &withdrawalSweepResult{
+		byIndex:       make(map[uint64]*types.WithdrawalPrediction),
+		incomePerSlot: make(map[uint64]uint64),
+	}
+
+	slot := startSlot
+	for ; visitedCount < n; slot++ {
+		epoch := utils.EpochOfSlot(slot)
+		withdrawals := uint64(0)
+		index := cursor
+		for i := uint64(0); i < bound; i++ {
+			v := validators[index]
+			if !visited[index] {
+				visited[index] = true
+				visitedAt[index] = slot
+				visitedCount++
+			}
+
+			if hasExecutionWithdrawalCredentials(v) {
+				if balances[index] > 0 && v.WithdrawableEpoch <= epoch {
+					result.addPrediction(v, slot, balances[index], true)
+					balances[index] = 0
+					withdrawals++
+				} else if v.EffectiveBalance == cfg.MaxEffectiveBalance && balances[index] > cfg.MaxEffectiveBalance {
+					result.addPrediction(v, slot, balances[index]-cfg.MaxEffectiveBalance, false)
+					balances[index] = cfg.MaxEffectiveBalance
+					withdrawals++
+				}
+			}
+
+			if withdrawals == cfg.MaxWithdrawalsPerPayload {
+				break
+			}
+			index = (index + 1) % n
+		}
+
+		if withdrawals == cfg.MaxWithdrawalsPerPayload {
+			cursor = (index + 1) % n
+		} else {
+			cursor = (cursor + bound) % n
+		}
+	}
+	// balances are only updated at epoch boundaries, so a partial withdrawal takes at least an epoch to accumulate
+	cycleSlots := slot - startSlot
+	if cycleSlots < cfg.SlotsPerEpoch {
+		cycleSlots = cfg.SlotsPerEpoch
+	}
+	result.cycleSlots = cycleSlots
+
+	slotsPerDay := uint64(24*60*60) / cfg.SecondsPerSlot
+	for i, v := range validators {
+		if !hasExecutionWithdrawalCredentials(v) {
+			continue
+		}
+		if v.Performance7d > 0 {
+			result.incomePerSlot[v.Validatorindex] = uint64(v.Performance7d) / (7 * slotsPerDay)
+		}
+		if result.byIndex[v.Validatorindex] != nil {
+			continue
+		}
+
+		if balances[i] > 0 && v.WithdrawableEpoch != 9223372036854775807 {
+			// the validator exited but was not withdrawable yet when the sweep passed it
+			withdrawableSlot := v.WithdrawableEpoch * cfg.SlotsPerEpoch
+			cycles := uint64(1)
+			if withdrawableSlot > visitedAt[i] {
+				cycles = (withdrawableSlot - visitedAt[i] + cycleSlots - 1) / cycleSlots
+			}
+			result.addPrediction(v, visitedAt[i]+cycles*cycleSlots, balances[i], true)
+		} else if v.EffectiveBalance == cfg.MaxEffectiveBalance && result.incomePerSlot[v.Validatorindex] > 0 {
+			// the validator has no excess balance yet, it will be withdrawn in the next cycle if it keeps its income
+			result.addPrediction(v, visitedAt[i]+cycleSlots, result.incomePerSlot[v.Validatorindex]*cycleSlots, false)
+		}
+	}
+
+	for _, prediction := range result.predictions {
+		prediction.IncomePerSlot = result.incomePerSlot[prediction.Validatorindex]
+		prediction.CycleSlots = cycleSlots
+	}
+	return result
+}
+
+func (result *withdrawalSweepResult) addPrediction(v *types.WithdrawalSweepValidator, slot, amount uint64, isFull bool) {
+	if result.byIndex[v.Validatorindex] != nil {
+		return
+	}
+	prediction := &types.WithdrawalPrediction{
+		Validatorindex: v.Validatorindex,
+		Address:        v.WithdrawalCredentials[12:],
+		Slot:           slot,
+		Amount:         amount,
+		IsFull:         isFull,
+	}
+	result.byIndex[v.Validatorindex] = prediction
+	result.predictions = append(result.predictions, prediction)
+}
+
+func hasExecutionWithdrawalCredentials(v *types.WithdrawalSweepValidator) bool {
+	return len(v.WithdrawalCredentials) == 32 && bytes.Equal(v.WithdrawalCredentials[:1], []byte{0x01})
+}
@@ -0,0 +1,119 @@
+package exporter
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateWithdrawalSweep(t *testing.T) {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.Config.SlotsPerEpoch = 4
+	utils.Config.Chain.Config.SecondsPerSlot = 12
+	utils.Config.Chain.Config.MaxEffectiveBalance = 32e9
+	utils.Config.Chain.Config.MaxWithdrawalsPerPayload = 2
+	utils.Config.Chain.Config.MaxValidatorsPerWithdrawalSweep = 3
+
+	const farFuture = 9223372036854775807
+	// the income of 1000 gwei per slot during the last 7 days of 12 second slots
+	const performance7d = 1000 * 7 * 7200
+
+	credentials := func(prefix, address byte) []byte {
+		c := make([]byte, 32)
+		c[0] = prefix
+		c[31] = address
+		return c
+	}
+	address := func(address byte) []byte {
+		return credentials(0x01, address)[12:]
+	}
+
+	tests := []struct {
+		name           string
+		validators     []*types.WithdrawalSweepValidator
+		cursor         uint64
+		startSlot      uint64
+		want           []*types.WithdrawalPrediction
+		wantCycleSlots uint64
+	}{
+		{
+			name: "partial withdrawals fill the payloads",
+			validators: []*types.WithdrawalSweepValidator{
+				{Validatorindex: 0, WithdrawalCredentials: credentials(0x01, 0xa), WithdrawableEpoch: farFuture, EffectiveBalance: 32e9, Balance: 32.1e9},
+				{Validatorindex: 1, WithdrawalCredentials: credentials(0x01, 0xb), WithdrawableEpoch: farFuture, EffectiveBalance: 32e9, Balance: 32.2e9},
+				{Validatorindex: 2, WithdrawalCredentials: credentials(0x01, 0xc), WithdrawableEpoch: farFuture, EffectiveBalance: 32e9, Balance: 32.3e9},
+			},
+			cursor:    2,
+			startSlot: 50,
+			want: []*types.WithdrawalPrediction{
+				{Validatorindex: 2, Address: address(0xc), Slot: 50, Amount: 0.3e9, CycleSlots: 4},
+				{Validatorindex: 0, Address: address(0xa), Slot: 50, Amount: 0.1e9, CycleSlots: 4},
+				{Validatorindex: 1, Address: address(0xb), Slot: 51, Amount: 0.2e9, CycleSlots: 4},
+			},
+			wantCycleSlots: 4,
+		},
+		{
+			name: "full withdrawal of a withdrawable validator",
+			validators: []*types.WithdrawalSweepValidator{
+				{Validatorindex: 0, WithdrawalCredentials: credentials(0x01, 0xa), WithdrawableEpoch: farFuture, EffectiveBalance: 32e9, Balance: 32e9},
+				{Validatorindex: 1, WithdrawalCredentials: credentials(0x01, 0xb), WithdrawableEpoch: 10, EffectiveBalance: 31e9, Balance: 31e9},
+				{Validatorindex: 2, WithdrawalCredentials: credentials(0x00, 0xc), WithdrawableEpoch: 10, EffectiveBalance: 32e9, Balance: 32.5e9},
+			},
+			cursor:    0,
+			startSlot: 100,
+			want: []*types.WithdrawalPrediction{
+				{Validatorindex: 1, Address: address(0xb), Slot: 100, Amount: 31e9, IsFull: true, CycleSlots: 4},
+			},
+			wantCycleSlots: 4,
+		},
+		{
+			name: "validators are predicted for the cycle after the sweep passed them",
+			validators: []*types.WithdrawalSweepValidator{
+				{Validatorindex: 0, WithdrawalCredentials: credentials(0x01, 0xa), WithdrawableEpoch: 30, EffectiveBalance: 32e9, Balance: 32e9},
+				{Validatorindex: 1, WithdrawalCredentials: credentials(0x01, 0xb), WithdrawableEpoch: farFuture, EffectiveBalance: 32e9, Balance: 32e9, Performance7d: performance7d},
+			},
+			cursor:    0,
+			startSlot: 100,
+			want: []*types.WithdrawalPrediction{
+				{Validatorindex: 0, Address: address(0xa), Slot: 120, Amount: 32e9, IsFull: true, CycleSlots: 4},
+				{Validatorindex: 1, Address: address(0xb), Slot: 104, Amount: 4000, IncomePerSlot: 1000, CycleSlots: 4},
+			},
+			wantCycleSlots: 4,
+		},
+		{
+			name: "a cycle takes as many slots as the sweep needs to pass all validators",
+			validators: func() []*types.WithdrawalSweepValidator {
+				validators := make([]*types.WithdrawalSweepValidator, 14)
+				for i := range validators {
+					validators[i] = &types.WithdrawalSweepValidator{Validatorindex: uint64(i), WithdrawalCredentials: credentials(0x01, byte(i)), WithdrawableEpoch: farFuture, EffectiveBalance: 32e9, Balance: 32e9}
+				}
+				validators[12].Balance = 32.1e9
+				validators[13].Performance7d = performance7d
+				return validators
+			}(),
+			cursor:    0,
+			startSlot: 200,
+			want: []*types.WithdrawalPrediction{
+				{Validatorindex: 12, Address: address(12), Slot: 204, Amount: 0.1e9, CycleSlots: 5},
+				{Validatorindex: 13, Address: address(13), Slot: 209, Amount: 5000, IncomePerSlot: 1000, CycleSlots: 5},
+			},
+			wantCycleSlots: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := simulateWithdrawalSweep(tt.validators, tt.cursor, tt.startSlot)
+			if result.cycleSlots != tt.wantCycleSlots {
+				t.Errorf("expected a cycle of %v slots, got %v", tt.wantCycleSlots, result.cycleSlots)
+			}
+			if !reflect.DeepEqual(result.predictions, tt.want) {
+				t.Errorf("unexpected predictions")
+				for _, p := range result.predictions {
+					t.Logf("got %+v", *p)
+				}
+			}
+		})
+	}
+}
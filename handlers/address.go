@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/gorilla/mux"
+	"golang.org/x/sync/errgroup"
+)
+
+// addressPageLimit is the number of entries shown per category on the address page
+const addressPageLimit = 25
+
+var addressTemplate = template.Must(template.New("address").Funcs(utils.GetTemplateFuncs()).ParseFiles("templates/layout.html", "templates/address.html"))
+
+var eth1BalanceClient *ethclient.Client
+var eth1BalanceClientMux = &sync.Mutex{}
+
+// Address will return the activity of an execution layer address
+func Address(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	vars := mux.Vars(r)
+	data := InitPageData(w, r, "blocks", "/address", "Address")
+
+	address, err := hex.DecodeString(strings.Replace(vars["address"], "0x", "", -1))
+	if err != nil || len(address) != 20 {
+		err := searchNotFoundTemplate.ExecuteTemplate(w, "layout", data)
+		if err != nil {
+			logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	addressPageData, err := getAddressPageData(address)
+	if err != nil {
+		logger.Errorf("error retrieving address data for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data.Meta.Title = fmt.Sprintf("%v - Address %v - www.agorascan.io - %v", utils.Config.Frontend.SiteName, common.BytesToAddress(address).Hex(), time.Now().Year())
+	data.Data = addressPageData
+
+	err = addressTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getAddressPageData will return the transactions, withdrawals, deposits, fee recipient blocks and validators of an address
+func getAddressPageData(address []byte) (*types.AddressPageData, error) {
+	data := &types.AddressPageData{Address: address}
+
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		var err error
+		data.Transactions, err = db.GetAddressTransactions(address, addressPageLimit)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		data.Withdrawals, _, err = db.GetAddressWithdrawals(address, addressPageLimit, "")
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		data.Deposits, err = db.GetAddressDeposits(address, addressPageLimit)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		data.Blocks, err = db.GetAddressFeeRecipientBlocks(address, addressPageLimit)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		data.Validators, err = db.GetAddressValidators(address, addressPageLimit)
+		return err
+	})
+	g.Go(func() error {
+		balance, err := getAddressBalance(address)
+		if err != nil {
+			// the page is still useful without the balance
+			logger.Warnf("error retrieving balance of address %#x: %v", address, err)
+			return nil
+		}
+		data.Balance = balance.String()
+		data.BalancePretty = ToEth(balance)
+		return nil
+	})
+	err := g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	payout, ok, err := services.GetExpectedMonthlyPayout(address)
+	if err != nil {
+		return nil, err
+	}
+	if ok && len(data.Validators) > 0 {
+		data.ExpectedMonthlyPayout = &payout
+	}
+
+	for _, tx := range data.Transactions {
+		tx.AmountPretty = ToEth(new(big.Int).SetBytes(tx.Amount))
+	}
+	for _, block := range data.Blocks {
+		if block.ExecPriorityFees == nil {
+			continue
+		}
+		if fees, ok := new(big.Int).SetString(*block.ExecPriorityFees, 10); ok {
+			block.PriorityFeesPretty = ToEth(fees)
+		}
+	}
+	return data, nil
+}
+
+// getAddressBalance will retrieve the latest balance of an address in wei from the configured execution endpoint
+func getAddressBalance(address []byte) (*big.Int, error) {
+	if utils.Config.Indexer.Eth1Endpoint == "" {
+		return nil, fmt.Errorf("no eth1 endpoint configured")
+	}
+
+	eth1BalanceClientMux.Lock()
+	if eth1BalanceClient == nil {
+		client, err := ethclient.Dial(utils.Config.Indexer.Eth1Endpoint)
+		if err != nil {
+			eth1BalanceClientMux.Unlock()
+			return nil, fmt.Errorf("error dialing eth1 endpoint: %v", err)
+		}
+		eth1BalanceClient = client
+	}
+	client := eth1BalanceClient
+	eth1BalanceClientMux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return client.BalanceAt(ctx, common.BytesToAddress(address), nil)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ApiAdminExportFailures godoc
+// @Summary Get all epochs that failed to export together with their retry state
+// @Tags Admin
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=[]types.ExportFailure}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/admin/export_failures [get]
+func ApiAdminExportFailures(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	failures, err := db.GetExportFailures()
+	if err != nil {
+		logger.Errorf("error retrieving export failures: %v", err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{failures})
+}
+
+// ApiAdminExportFailureRetry godoc
+// @Summary Schedule the retry of a failed epoch export for the next export run
+// @Tags Admin
+// @Produce  json
+// @Param  epoch path int true "Epoch"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/admin/export_failures/{epoch}/retry [post]
+func ApiAdminExportFailureRetry(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "invalid epoch provided")
+		return
+	}
+
+	found, err := db.RetryExportFailureNow(epoch)
+	if err != nil {
+		logger.Errorf("error scheduling retry of epoch %v: %v", epoch, err)
+		sendErrorResponse(j, r.URL.String(), "could not schedule retry")
+		return
+	}
+	if !found {
+		sendErrorResponse(j, r.URL.String(), "epoch has no export failure")
+		return
+	}
+
+	sendOKResponse(j, r.URL.String(), nil)
+}
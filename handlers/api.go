@@ -15,6 +15,7 @@ import (
 	"eth2-exporter/utils"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
@@ -221,7 +222,7 @@ func ApiEpochBlocks(w http.ResponseWriter, r *http.Request) {
 		epoch = int64(services.LatestEpoch())
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT * FROM blocks WHERE epoch = $1 ORDER BY slot", epoch)
+	rows, err := db.ReaderDb.Query("SELECT "+apiBlockColumns+" FROM blocks WHERE epoch = $1 ORDER BY slot", epoch)
 	if err != nil {
 		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
 		return
@@ -231,6 +232,19 @@ func ApiEpochBlocks(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, j, r)
 }
 
+// apiBlockColumns are the columns of a block returned by the api, the bookkeeping columns of the exporter are left out
+const apiBlockColumns = `
+	blocks.epoch, blocks.slot, blocks.blockroot, blocks.parentroot, blocks.stateroot, blocks.signature,
+	blocks.randaoreveal, blocks.graffiti, blocks.graffiti_text, blocks.eth1data_depositroot,
+	blocks.eth1data_depositcount, blocks.eth1data_blockhash, blocks.syncaggregate_bits,
+	blocks.syncaggregate_signature, blocks.syncaggregate_participation, blocks.proposerslashingscount,
+	blocks.attesterslashingscount, blocks.attestationscount, blocks.depositscount, blocks.voluntaryexitscount,
+	blocks.proposer, blocks.status, blocks.exec_parent_hash, blocks.exec_fee_recipient, blocks.exec_state_root,
+	blocks.exec_receipts_root, blocks.exec_logs_bloom, blocks.exec_random, blocks.exec_block_number,
+	blocks.exec_gas_limit, blocks.exec_gas_used, blocks.exec_timestamp, blocks.exec_extra_data,
+	blocks.exec_base_fee_per_gas, blocks.exec_block_hash, blocks.exec_transactions_count,
+	blocks.exec_blob_gas_used, blocks.exec_excess_blob_gas, blocks.blobcount, blocks.exec_priority_fees`
+
 // ApiBlock godoc
 // @Summary Get block
 // @Tags Block
@@ -257,14 +271,33 @@ func ApiBlock(w http.ResponseWriter, r *http.Request) {
 		blockSlot = int64(services.LatestSlot())
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT * FROM blocks WHERE slot = $1 OR blockroot = $2", blockSlot, blockRootHash)
+	rows, err := db.ReaderDb.Query(`
+		SELECT `+apiBlockColumns+`,
+			(SELECT COALESCE(SUM(size), 0) FROM blocks_blob_sidecars WHERE block_slot = blocks.slot AND block_root = blocks.blockroot) AS blobs_size
+		FROM blocks
+		WHERE slot = $1 OR blockroot = $2`, blockSlot, blockRootHash)
 	if err != nil {
 		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
 		return
 	}
 	defer rows.Close()
 
-	returnQueryResults(rows, j, r)
+	data, err := utils.SqlRowsToJSON(rows)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not parse db results")
+		return
+	}
+
+	// the blob base fee is derived from the excess blob gas of the block
+	for _, row := range data {
+		block := row.(map[string]interface{})
+		block["blob_base_fee"] = nil
+		if excessBlobGas, ok := block["exec_excess_blob_gas"].(int64); ok {
+			block["blob_base_fee"] = utils.BlobBaseFee(uint64(excessBlobGas))
+		}
+	}
+
+	sendOKResponse(j, r.URL.String(), data)
 }
 
 // ApiBlockAttestations godoc
@@ -480,6 +513,77 @@ func ApiSyncCommittee(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, j, r)
 }
 
+// ApiSyncCommitteePerformance godoc
+// @Summary Get the performance of the sync-committee of a sync-period
+// @Tags SyncCommittee
+// @Description Returns the participation rate, missed slots and sync committee rewards of every member of the sync-committee of a sync-period. Missed slots are split into slots the validator missed itself (missed_own) and slots without a canonical block (missed_empty_slot), the participation rate only considers slots with a canonical block. Rewards and penalties are in Gwei, penalties are negative
+// @Produce json
+// @Param period path string true "Period ('latest' for latest period)"
+// @Success 200 {object} types.ApiResponse{data=types.ApiSyncCommitteePerformanceResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/sync_committee/{period}/performance [get]
+func ApiSyncCommitteePerformance(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	period, err := strconv.ParseUint(vars["period"], 10, 64)
+	if err != nil && vars["period"] != "latest" {
+		sendErrorResponse(j, r.URL.String(), "invalid period provided")
+		return
+	}
+
+	if vars["period"] == "latest" {
+		period = utils.SyncPeriodOfEpoch(services.LatestEpoch())
+	}
+
+	performance, err := db.GetSyncCommitteePerformance(period, nil)
+	if err != nil {
+		logger.WithError(err).WithField("url", r.URL.String()).Errorf("error retrieving sync committee performance")
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	dataFormatted := &types.ApiSyncCommitteePerformanceResponse{
+		Period:     period,
+		StartEpoch: utils.FirstEpochOfSyncPeriod(period),
+		EndEpoch:   utils.FirstEpochOfSyncPeriod(period+1) - 1,
+		Validators: make([]*types.ApiSyncCommitteeValidatorPerformanceResponse, 0, len(performance)),
+	}
+	for _, p := range performance {
+		dataFormatted.Participated += p.Participated
+		dataFormatted.MissedOwn += p.MissedOwn
+		dataFormatted.MissedEmptySlot += p.MissedEmptySlot
+		dataFormatted.Rewards += p.Rewards
+		dataFormatted.Penalties += p.Penalties
+		dataFormatted.Validators = append(dataFormatted.Validators, &types.ApiSyncCommitteeValidatorPerformanceResponse{
+			ValidatorIndex:    p.Validatorindex,
+			Participated:      p.Participated,
+			MissedOwn:         p.MissedOwn,
+			MissedEmptySlot:   p.MissedEmptySlot,
+			Scheduled:         p.Scheduled,
+			ParticipationRate: p.ParticipationRate(),
+			Rewards:           p.Rewards,
+			Penalties:         p.Penalties,
+		})
+	}
+	if dataFormatted.Participated+dataFormatted.MissedOwn > 0 {
+		dataFormatted.ParticipationRate = float64(dataFormatted.Participated) / float64(dataFormatted.Participated+dataFormatted.MissedOwn)
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
 // ApiEth1Deposit godoc
 // @Summary Get an eth1 deposit by its eth1 transaction hash
 // @Tags Eth1
@@ -1005,6 +1109,133 @@ func ApiValidatorAttestationEffectiveness(w http.ResponseWriter, r *http.Request
 	returnQueryResults(rows, j, r)
 }
 
+// ApiValidatorAttestationCorrectness godoc
+// @Summary Get the inclusion delay and the correctness of the head, target and source votes of the attestations of up to 100 validators. The rates are the shares of the included attestations whose votes match the canonical chain, only attestations of finalized epochs are evaluated
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorAttestationCorrectnessResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/attestationcorrectness [get]
+func ApiValidatorAttestationCorrectness(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(j, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	startEpoch := uint64(0)
+	if services.LatestEpoch() > 100 {
+		startEpoch = services.LatestEpoch() - 100
+	}
+	recent, err := db.GetAttestationCorrectness(queryIndices, startEpoch)
+	if err != nil {
+		logger.Errorf("error retrieving attestation correctness for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	total, err := db.GetAttestationCorrectnessStatistics(queryIndices)
+	if err != nil {
+		logger.Errorf("error retrieving attestation correctness statistics for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	recentByIndex := make(map[uint64]*types.AttestationCorrectness, len(recent))
+	for _, c := range recent {
+		recentByIndex[c.Validatorindex] = c
+	}
+	totalByIndex := make(map[uint64]*types.AttestationCorrectness, len(total))
+	for _, c := range total {
+		totalByIndex[c.Validatorindex] = c
+	}
+
+	dataFormatted := make([]*types.ApiValidatorAttestationCorrectnessResponse, 0, len(queryIndices))
+	for _, index := range queryIndices {
+		correctness := &types.ApiValidatorAttestationCorrectnessResponse{
+			ValidatorIndex: index,
+			Last100Epochs:  formatAttestationCorrectness(&types.AttestationCorrectness{}),
+			Total:          formatAttestationCorrectness(&types.AttestationCorrectness{}),
+		}
+		if c, ok := recentByIndex[index]; ok {
+			correctness.Last100Epochs = formatAttestationCorrectness(c)
+		}
+		if c, ok := totalByIndex[index]; ok {
+			correctness.Total = formatAttestationCorrectness(c)
+		}
+		dataFormatted = append(dataFormatted, correctness)
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiDuties godoc
+// @Summary Get the upcoming block proposals and sync committee memberships of up to 100 validators. Proposals are only known for the current and the next epoch, sync committees for the current and the next period
+// @Tags Validator
+// @Produce  json
+// @Param  validators query string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=types.ApiDutiesResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/duties [get]
+func ApiDuties(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(q.Get("validators"), maxValidators)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(j, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	duties, err := getUpcomingDuties(queryIndices)
+	if err != nil {
+		logger.Errorf("error retrieving upcoming duties for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = duties
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
 // ApiValidatorAttestationEfficiency godoc
 // @Summary Get the current performance of up to 100 validators
 // @Tags Validator
@@ -1190,6 +1421,410 @@ func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ApiValidatorWithdrawalCredentials godoc
+// @Summary Get the withdrawal credentials of up to 100 validators. For validators with 0x00 credentials the state of their bls to execution change is returned: none, pending in the operation pool, invalid (the signature does not verify or the bls pubkey does not match the credentials) or included
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param  type query string false "only return validators with the given credentials type: bls or execution"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiWithdrawalCredentialsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/withdrawalcredentials [get]
+func ApiValidatorWithdrawalCredentials(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(j, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	credentialsType := r.URL.Query().Get("type")
+	if credentialsType != "" && credentialsType != "bls" && credentialsType != "execution" {
+		sendErrorResponse(j, r.URL.String(), "invalid credentials type provided")
+		return
+	}
+
+	data, err := getWithdrawalCredentialsStatus(queryIndices)
+	if err != nil {
+		logger.Errorf("error retrieving withdrawal credentials for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	dataFormatted := make([]*types.ApiWithdrawalCredentialsResponse, 0, len(data))
+	for _, credentials := range data {
+		if credentialsType != "" && credentials.Type != credentialsType {
+			continue
+		}
+		dataFormatted = append(dataFormatted, credentials)
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiValidatorNextWithdrawal godoc
+// @Summary Get the predicted next withdrawal of up to 100 validators. The prediction simulates the withdrawal sweep with the current balances of all validators and assumes that a block is proposed in every slot. Validators without execution withdrawal credentials or without an expected withdrawal are omitted. Amounts are in Gwei
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorNextWithdrawalResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/next_withdrawal [get]
+func ApiValidatorNextWithdrawal(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(j, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	predictions, err := services.GetNextWithdrawals(queryIndices)
+	if err != nil {
+		logger.Errorf("error retrieving withdrawal predictions: %v", err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	dataFormatted := make([]*types.ApiValidatorNextWithdrawalResponse, 0, len(predictions))
+	for _, prediction := range predictions {
+		withdrawalType := "partial"
+		if prediction.IsFull {
+			withdrawalType = "full"
+		}
+		dataFormatted = append(dataFormatted, &types.ApiValidatorNextWithdrawalResponse{
+			ValidatorIndex: prediction.Validatorindex,
+			Address:        fmt.Sprintf("0x%x", prediction.Address),
+			Epoch:          utils.EpochOfSlot(prediction.Slot),
+			Slot:           prediction.Slot,
+			Timestamp:      utils.SlotToTime(prediction.Slot).Unix(),
+			Amount:         prediction.Amount,
+			Type:           withdrawalType,
+		})
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiValidatorRewards godoc
+// @Summary Get the consensus layer reward breakdown of up to 100 validators for the last 100 epochs. To receive older rewards modify the epoch param. Penalties are returned as negative amounts in Gwei
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param  epoch query int false "the start epoch for the reward history (default: latest epoch)"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorRewardsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/rewards [get]
+func ApiValidatorRewards(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(j, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	q := r.URL.Query()
+
+	epoch, err := strconv.ParseUint(q.Get("epoch"), 10, 64)
+	if err != nil {
+		epoch = services.LatestEpoch()
+	}
+
+	// startEpoch and epoch are both inclusive, so substracting 99 here will result in a limit of 100 epochs
+	startEpoch := uint64(0)
+	if epoch > 99 {
+		startEpoch = epoch - 99
+	}
+
+	data, err := db.GetValidatorsRewards(queryIndices, startEpoch, epoch)
+	if err != nil {
+		logger.Errorf("error retrieving rewards for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	dataFormatted := make([]*types.ApiValidatorRewardsResponse, 0, len(data))
+	for _, rewards := range data {
+		dataFormatted = append(dataFormatted, &types.ApiValidatorRewardsResponse{
+			Epoch:                     rewards.Epoch,
+			ValidatorIndex:            rewards.ValidatorIndex,
+			Source:                    rewards.Source,
+			Target:                    rewards.Target,
+			Head:                      rewards.Head,
+			InclusionDelay:            rewards.InclusionDelay,
+			Inactivity:                rewards.Inactivity,
+			SyncCommittee:             rewards.SyncCommittee,
+			ProposerAttestations:      rewards.ProposerAttestations,
+			ProposerSyncAggregate:     rewards.ProposerSyncAggregate,
+			ProposerSlashingInclusion: rewards.ProposerSlashingInclusion,
+			Total:                     rewards.Total(),
+		})
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiExecutionTransaction godoc
+// @Summary Get an execution transaction by its hash, the receipt fields and logs are null until the receipt of the transaction has been exported
+// @Tags Execution
+// @Produce  json
+// @Param  hash path string true "Transaction hash"
+// @Success 200 {object} types.ApiResponse{data=types.ApiExecutionTransactionResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/tx/{hash} [get]
+func ApiExecutionTransaction(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	txHash, err := hex.DecodeString(strings.Replace(vars["hash"], "0x", "", -1))
+	if err != nil || len(txHash) != 32 {
+		sendErrorResponse(j, r.URL.String(), "invalid transaction hash provided")
+		return
+	}
+
+	tx, err := getTxPageData(txHash)
+	if err != nil {
+		logger.Errorf("error retrieving transaction for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if tx == nil {
+		sendErrorResponse(j, r.URL.String(), "transaction not found")
+		return
+	}
+
+	nullInt64 := func(n sql.NullInt64) *int64 {
+		if !n.Valid {
+			return nil
+		}
+		return &n.Int64
+	}
+
+	logs := make([]*types.ApiExecutionTransactionLogResponse, 0, len(tx.Logs))
+	for _, log := range tx.Logs {
+		topics := make([]string, 0, len(log.Topics))
+		for _, topic := range log.Topics {
+			topics = append(topics, fmt.Sprintf("0x%x", topic))
+		}
+		logs = append(logs, &types.ApiExecutionTransactionLogResponse{
+			LogIndex: log.LogIndex,
+			Address:  fmt.Sprintf("0x%x", log.Address),
+			Topics:   topics,
+			Data:     fmt.Sprintf("0x%x", log.Data),
+		})
+	}
+
+	dataFormatted := &types.ApiExecutionTransactionResponse{
+		TxHash:               fmt.Sprintf("0x%x", tx.TxHash),
+		BlockSlot:            tx.BlockSlot,
+		BlockIndex:           tx.BlockIndex,
+		BlockRoot:            fmt.Sprintf("0x%x", tx.BlockRoot),
+		BlockNumber:          tx.BlockNumber,
+		Orphaned:             tx.BlockStatus == 3,
+		From:                 fmt.Sprintf("0x%x", tx.Sender),
+		To:                   fmt.Sprintf("0x%x", tx.Recipient),
+		Nonce:                tx.AccountNonce,
+		Value:                new(big.Int).SetBytes(tx.Amount).String(),
+		GasLimit:             tx.GasLimit,
+		GasPrice:             new(big.Int).SetBytes(tx.Price).String(),
+		MaxFeePerGas:         nullInt64(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: nullInt64(tx.MaxPriorityFeePerGas),
+		Input:                fmt.Sprintf("0x%x", tx.Payload),
+		Status:               nullInt64(tx.ReceiptStatus),
+		GasUsed:              nullInt64(tx.GasUsed),
+		Logs:                 logs,
+	}
+	if tx.EffectiveGasPrice.Valid {
+		dataFormatted.EffectiveGasPrice = &tx.EffectiveGasPrice.String
+	}
+	if len(tx.ContractAddress) > 0 {
+		dataFormatted.ContractAddress = fmt.Sprintf("0x%x", tx.ContractAddress)
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiAddress godoc
+// @Summary Get the latest activity of an execution layer address: transactions sent and received, withdrawals credited, deposits made, blocks it was the fee recipient of and the validators withdrawing to it. Returns up to 25 entries per category, the balance in wei is omitted if it could not be retrieved
+// @Tags Execution
+// @Produce  json
+// @Param  address path string true "Execution layer address"
+// @Success 200 {object} types.ApiResponse{data=types.ApiAddressResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/address/{address} [get]
+func ApiAddress(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	address, err := hex.DecodeString(strings.Replace(vars["address"], "0x", "", -1))
+	if err != nil || len(address) != 20 {
+		sendErrorResponse(j, r.URL.String(), "invalid address provided")
+		return
+	}
+
+	data, err := getAddressPageData(address)
+	if err != nil {
+		logger.Errorf("error retrieving address data for %v route: %v", r.URL.String(), err)
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	dataFormatted := &types.ApiAddressResponse{
+		Address:      fmt.Sprintf("0x%x", data.Address),
+		Balance:      data.Balance,
+		Transactions: make([]*types.ApiAddressTransactionResponse, 0, len(data.Transactions)),
+		Withdrawals:  make([]*types.ApiValidatorWithdrawalResponse, 0, len(data.Withdrawals)),
+		Deposits:     make([]*types.ApiAddressDepositResponse, 0, len(data.Deposits)),
+		Blocks:       make([]*types.ApiAddressBlockResponse, 0, len(data.Blocks)),
+		Validators:   make([]*types.ApiAddressValidatorResponse, 0, len(data.Validators)),
+
+		ExpectedMonthlyPayout: data.ExpectedMonthlyPayout,
+	}
+	for _, tx := range data.Transactions {
+		txFormatted := &types.ApiAddressTransactionResponse{
+			TxHash:     fmt.Sprintf("0x%x", tx.TxHash),
+			BlockSlot:  tx.BlockSlot,
+			BlockIndex: tx.BlockIndex,
+			From:       fmt.Sprintf("0x%x", tx.Sender),
+			To:         fmt.Sprintf("0x%x", tx.Recipient),
+			Value:      new(big.Int).SetBytes(tx.Amount).String(),
+		}
+		if tx.ReceiptStatus.Valid {
+			txFormatted.Status = &tx.ReceiptStatus.Int64
+		}
+		if len(tx.ContractAddress) > 0 {
+			txFormatted.ContractAddress = fmt.Sprintf("0x%x", tx.ContractAddress)
+		}
+		dataFormatted.Transactions = append(dataFormatted.Transactions, txFormatted)
+	}
+	for _, withdrawal := range data.Withdrawals {
+		dataFormatted.Withdrawals = append(dataFormatted.Withdrawals, &types.ApiValidatorWithdrawalResponse{
+			Epoch:          withdrawal.Slot / utils.Config.Chain.Config.SlotsPerEpoch,
+			Slot:           withdrawal.Slot,
+			Index:          withdrawal.Index,
+			ValidatorIndex: withdrawal.ValidatorIndex,
+			Address:        fmt.Sprintf("0x%x", withdrawal.Address),
+			Amount:         withdrawal.Amount,
+		})
+	}
+	for _, deposit := range data.Deposits {
+		dataFormatted.Deposits = append(dataFormatted.Deposits, &types.ApiAddressDepositResponse{
+			TxHash:                fmt.Sprintf("0x%x", deposit.TxHash),
+			BlockNumber:           deposit.BlockNumber,
+			BlockTs:               deposit.BlockTs,
+			PublicKey:             fmt.Sprintf("0x%x", deposit.PublicKey),
+			WithdrawalCredentials: fmt.Sprintf("0x%x", deposit.WithdrawalCredentials),
+			Amount:                deposit.Amount,
+			ValidSignature:        deposit.ValidSignature,
+		})
+	}
+	for _, block := range data.Blocks {
+		blockFormatted := &types.ApiAddressBlockResponse{
+			Slot:            block.Slot,
+			BlockRoot:       fmt.Sprintf("0x%x", block.BlockRoot),
+			Proposer:        block.Proposer,
+			ExecBlockNumber: block.ExecBlockNumber,
+		}
+		if block.ExecPriorityFees != nil {
+			blockFormatted.ExecPriorityFees = *block.ExecPriorityFees
+		}
+		dataFormatted.Blocks = append(dataFormatted.Blocks, blockFormatted)
+	}
+	for _, validator := range data.Validators {
+		dataFormatted.Validators = append(dataFormatted.Validators, &types.ApiAddressValidatorResponse{
+			ValidatorIndex: validator.Index,
+			PublicKey:      fmt.Sprintf("0x%x", validator.PublicKey),
+			Balance:        validator.Balance,
+			Status:         validator.Status,
+		})
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
 // ApiValidatorTotalWithdrawals godoc
 // @Summary Get the withdrawal history of up to 100 validators for the last 100 epochs. To receive older withdrawals modify the epoch paraum
 // @Tags Validator
@@ -1304,7 +1939,7 @@ func ApiValidatorProposals(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT blocks.* FROM blocks LEFT JOIN validators on validators.validatorindex = blocks.proposer WHERE (proposer = ANY($1) OR validators.pubkey = ANY($2)) AND epoch > $3 ORDER BY proposer, epoch desc, slot desc LIMIT 100", pq.Array(queryIndices), queryPubkeys, services.LatestEpoch()-100)
+	rows, err := db.ReaderDb.Query("SELECT "+apiBlockColumns+" FROM blocks LEFT JOIN validators on validators.validatorindex = blocks.proposer WHERE (proposer = ANY($1) OR validators.pubkey = ANY($2)) AND epoch > $3 ORDER BY proposer, epoch desc, slot desc LIMIT 100", pq.Array(queryIndices), queryPubkeys, services.LatestEpoch()-100)
 	if err != nil {
 		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
 		return
@@ -1336,6 +1971,59 @@ func ApiGraffitiwall(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, j, r)
 }
 
+// ApiReorgs godoc
+// @Summary Get the most recent chain reorgs detected by the explorer
+// @Tags Reorgs
+// @Produce  json
+// @Param  limit query int false "Limit the number of results (default: 100, max: 1000)"
+// @Param  offset query int false "Offset of the results"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/reorgs [get]
+func ApiReorgs(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	limit := uint64(100)
+	if q.Get("limit") != "" {
+		var err error
+		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil {
+			sendErrorResponse(j, r.URL.String(), "invalid limit provided")
+			return
+		}
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	offset := uint64(0)
+	if q.Get("offset") != "" {
+		var err error
+		offset, err = strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			sendErrorResponse(j, r.URL.String(), "invalid offset provided")
+			return
+		}
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT slot, depth, common_ancestor_slot, old_head_slot, old_head_root, new_head_slot, new_head_root, orphaned_blocks, detected_at
+		FROM reorgs
+		ORDER BY slot DESC, id DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		sendErrorResponse(j, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResults(rows, j, r)
+}
+
 // ApiChart godoc
 // @Summary Returns charts from the page https://www.agorascan.io/charts as PNG
 // @Tags Charts
@@ -28,6 +28,7 @@ var blockTemplate = template.Must(template.New("block").Funcs(utils.GetTemplateF
 	"templates/layout.html",
 	"templates/block/block.html",
 	"templates/block/transactions.html",
+	"templates/block/blobs.html",
 	"templates/block/attestations.html",
 	"templates/block/deposits.html",
 	"templates/block/votes.html",
@@ -135,6 +136,9 @@ func Block(w http.ResponseWriter, r *http.Request) {
 		    exec_base_fee_per_gas,
 		    exec_block_hash,
 			exec_transactions_count,
+			exec_blob_gas_used,
+			exec_excess_blob_gas,
+			blobcount,
 			COALESCE(validator_names.name, '') AS name
 		FROM blocks
 		LEFT JOIN validators ON blocks.proposer = validators.validatorindex
@@ -255,6 +259,33 @@ func Block(w http.ResponseWriter, r *http.Request) {
 	}
 	blockPageData.Transactions = transactions
 
+	if blockPageData.BlobCount > 0 {
+		err = db.ReaderDb.Select(&blockPageData.Blobs, `
+			SELECT
+				blocks_blobs.blob_index,
+				blocks_blobs.kzg_commitment,
+				blocks_blobs.versioned_hash,
+				blocks_blobs.tx_hash,
+				blocks_blob_sidecars.kzg_proof,
+				blocks_blob_sidecars.size
+			FROM blocks_blobs
+			LEFT JOIN blocks_blob_sidecars ON blocks_blob_sidecars.block_slot = blocks_blobs.block_slot AND blocks_blob_sidecars.block_root = blocks_blobs.block_root AND blocks_blob_sidecars.blob_index = blocks_blobs.blob_index
+			WHERE blocks_blobs.block_slot = $1 AND blocks_blobs.block_root = $2
+			ORDER BY blocks_blobs.blob_index`,
+			blockPageData.Slot, blockPageData.BlockRoot)
+		if err != nil {
+			logger.Errorf("error retrieving blobs of block %v: %v", blockPageData.Slot, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		for _, blob := range blockPageData.Blobs {
+			blockPageData.BlobsSize += uint64(blob.Size.Int64)
+		}
+	}
+	if blockPageData.ExecExcessBlobGas.Valid {
+		blockPageData.BlobBaseFee = utils.BlobBaseFee(uint64(blockPageData.ExecExcessBlobGas.Int64)).String()
+	}
+
 	var attestations []*types.BlockPageAttestation
 	rows, err = db.ReaderDb.Query(`
 		SELECT
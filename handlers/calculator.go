@@ -58,7 +58,7 @@ func estimatedValidatorIncomeChartData() ([][]float64, error) {
 	}{}
 	err := db.ReaderDb.Select(&rows, `
 		SELECT 
-			epoch, eligibleether, votedether, validatorscount, globalparticipationrate,
+			epoch, coalesce(eligibleether,0) as eligibleether, coalesce(votedether,0) as votedether, validatorscount, coalesce(globalparticipationrate,0) as globalparticipationrate,
 			coalesce(nl.headepoch-nl.finalizedepoch,2) as finalitydelay
 		FROM epochs
 			LEFT JOIN network_liveness nl ON epochs.epoch = nl.headepoch
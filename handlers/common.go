@@ -139,23 +139,50 @@ func GetValidatorEarnings(validators []uint64, currency string) (*types.Validato
 		earningsLastMonth += int64(balance.Balance) - int64(balance.Balance31d)
 	}
 
+	// priority fees received by the fee recipients of the blocks the validators proposed, converted from wei to gwei
+	execEarnings := struct {
+		Total     int64 `db:"total"`
+		LastDay   int64 `db:"last_day"`
+		LastWeek  int64 `db:"last_week"`
+		LastMonth int64 `db:"last_month"`
+	}{}
+	err = db.ReaderDb.Get(&execEarnings, `
+		SELECT
+			TRUNC(COALESCE(SUM(exec_priority_fees), 0) / 1000000000)::bigint AS total,
+			TRUNC(COALESCE(SUM(exec_priority_fees) FILTER (WHERE epoch > $2), 0) / 1000000000)::bigint AS last_day,
+			TRUNC(COALESCE(SUM(exec_priority_fees) FILTER (WHERE epoch > $3), 0) / 1000000000)::bigint AS last_week,
+			TRUNC(COALESCE(SUM(exec_priority_fees) FILTER (WHERE epoch > $4), 0) / 1000000000)::bigint AS last_month
+		FROM blocks
+		WHERE proposer = ANY($1) AND status = '1' AND exec_priority_fees IS NOT NULL`, validatorsPQArray, lastDayEpoch, lastWeekEpoch, lastMonthEpoch)
+	if err != nil {
+		return nil, err
+	}
+
 	apr = (((float64(earningsLastWeek) / 1e9) / (float64(totalDeposits) / 1e9)) * 365) / 7
 	if apr < float64(-1) {
 		apr = float64(-1)
 	}
 
 	return &types.ValidatorEarnings{
-		Total:                earningsTotal,
-		LastDay:              earningsLastDay,
-		LastWeek:             earningsLastWeek,
-		LastMonth:            earningsLastMonth,
-		APR:                  apr,
-		TotalDeposits:        totalDeposits,
-		LastDayFormatted:     utils.FormatIncome(earningsLastDay, currency),
-		LastWeekFormatted:    utils.FormatIncome(earningsLastWeek, currency),
-		LastMonthFormatted:   utils.FormatIncome(earningsLastMonth, currency),
-		TotalFormatted:       utils.FormatIncome(earningsTotal, currency),
-		TotalChangeFormatted: utils.FormatIncome(earningsTotal+totalDeposits, currency),
+		Total:                  earningsTotal,
+		LastDay:                earningsLastDay,
+		LastWeek:               earningsLastWeek,
+		LastMonth:              earningsLastMonth,
+		APR:                    apr,
+		TotalDeposits:          totalDeposits,
+		LastDayFormatted:       utils.FormatIncome(earningsLastDay, currency),
+		LastWeekFormatted:      utils.FormatIncome(earningsLastWeek, currency),
+		LastMonthFormatted:     utils.FormatIncome(earningsLastMonth, currency),
+		TotalFormatted:         utils.FormatIncome(earningsTotal, currency),
+		TotalChangeFormatted:   utils.FormatIncome(earningsTotal+totalDeposits, currency),
+		ExecTotal:              execEarnings.Total,
+		ExecLastDay:            execEarnings.LastDay,
+		ExecLastWeek:           execEarnings.LastWeek,
+		ExecLastMonth:          execEarnings.LastMonth,
+		ExecLastDayFormatted:   utils.FormatIncome(execEarnings.LastDay, currency),
+		ExecLastWeekFormatted:  utils.FormatIncome(execEarnings.LastWeek, currency),
+		ExecLastMonthFormatted: utils.FormatIncome(execEarnings.LastMonth, currency),
+		ExecTotalFormatted:     utils.FormatIncome(execEarnings.Total, currency),
 	}, nil
 }
 
@@ -366,35 +393,3 @@ func GetDataTableState(user *types.User, session *sessions.Session, tableKey str
 	}
 	return &state, nil
 }
-
-func GetWithdrawableCountFromCursor(epoch uint64, validatorindex uint64, cursor uint64) (uint64, error) {
-	// the validators' balance will not be checked here as this is only a rough estimation
-	// checking the balance for hundreds of thousands of validators is too expensive
-
-	var maxValidatorIndex uint64
-	err := db.WriterDb.Get(&maxValidatorIndex, "SELECT COALESCE(MAX(validatorindex), 0) FROM validators")
-	if err != nil {
-		return 0, fmt.Errorf("error getting withdrawable validator count from cursor: %w", err)
-	}
-
-	if maxValidatorIndex == 0 {
-		return 0, nil
-	}
-
-	activeValidators := services.LatestIndexPageData().ActiveValidators
-	if activeValidators == 0 {
-		activeValidators = maxValidatorIndex
-	}
-
-	if validatorindex > cursor {
-		// if the validatorindex is after the cursor, simply return the number of validators between the cursor and the validatorindex
-		// the returned data is then scaled using the number of currently active validators in order to account for exited / entering validators
-		return (validatorindex - cursor) * activeValidators / maxValidatorIndex, nil
-	} else if validatorindex < cursor {
-		// if the validatorindex is before the cursor (wraparound case) return the number of validators between the cursor and the most recent validator plus the amount of validators from the validator 0 to the validatorindex
-		// the returned data is then scaled using the number of currently active validators in order to account for exited / entering validators
-		return (maxValidatorIndex - cursor + validatorindex) * activeValidators / maxValidatorIndex, nil
-	} else {
-		return 0, nil
-	}
-}
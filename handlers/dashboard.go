@@ -457,7 +457,39 @@ func DashboardDataEffectiveness(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(avgIncDistance)
+	startEpoch := uint64(0)
+	if services.LatestEpoch() > 100 {
+		startEpoch = services.LatestEpoch() - 100
+	}
+	activeIndices := make([]uint64, 0, len(activeValidators))
+	for _, index := range activeValidators {
+		activeIndices = append(activeIndices, uint64(index))
+	}
+	correctness, err := db.GetAttestationCorrectness(activeIndices, startEpoch)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Errorf("error retrieving attestation correctness")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	correctnessByIndex := make(map[uint64]*types.AttestationCorrectness, len(correctness))
+	for _, c := range correctness {
+		correctnessByIndex[c.Validatorindex] = c
+	}
+
+	// the effectiveness of a validator is the reciprocal of its average inclusion distance, weighted with the share of
+	// correct head, target and source votes of its attestations that have been evaluated already
+	effectiveness := make([]float64, len(avgIncDistance))
+	for i, incDistance := range avgIncDistance {
+		if incDistance == 0 {
+			continue
+		}
+		effectiveness[i] = 1.0 / incDistance * 100.0
+		if c, found := correctnessByIndex[activeIndices[i]]; found && c.Included > 0 {
+			effectiveness[i] *= c.VoteRate()
+		}
+	}
+
+	err = json.NewEncoder(w).Encode(effectiveness)
 	if err != nil {
 		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
 		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
@@ -524,3 +556,186 @@ func DashboardDataProposalsHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// DashboardDataWithdrawalCredentials returns the withdrawal credentials of the validators and the state of their bls to execution changes
+func DashboardDataWithdrawalCredentials(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	validatorLimit := getUserPremium(r).MaxValidators
+	filterArr, err := parseValidatorsFromQueryString(q.Get("validators"), validatorLimit)
+	if err != nil {
+		http.Error(w, "Invalid query", 400)
+		return
+	}
+
+	credentials, err := getWithdrawalCredentialsStatus(filterArr)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Errorf("error retrieving withdrawal credentials")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(credentials)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// getWithdrawalCredentialsStatus will return the withdrawal credentials of the validators and whether a bls to execution
+// change has been included or is pending for validators that still have 0x00 credentials
+func getWithdrawalCredentialsStatus(validators []uint64) ([]*types.ApiWithdrawalCredentialsResponse, error) {
+	status, err := db.GetWithdrawalCredentialsStatus(validators)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.ApiWithdrawalCredentialsResponse, 0, len(status))
+	for _, s := range status {
+		credentials := &types.ApiWithdrawalCredentialsResponse{
+			ValidatorIndex:        s.Validatorindex,
+			WithdrawalCredentials: fmt.Sprintf("%#x", s.WithdrawalCredentials),
+			Type:                  "bls",
+			BLSChangeStatus:       "none",
+			BLSChangeSlot:         s.IncludedSlot,
+		}
+		if address, err := utils.WithdrawalCredentialsToAddress(s.WithdrawalCredentials); err == nil {
+			credentials.Type = "execution"
+			credentials.Address = fmt.Sprintf("%#x", address)
+		}
+
+		if s.IncludedSlot > 0 {
+			credentials.BLSChangeStatus = "included"
+		} else if s.PendingChange != nil {
+			credentials.BLSChangeStatus = "pending"
+			if !s.PendingChange.Valid() {
+				credentials.BLSChangeStatus = "invalid"
+			}
+			credentials.PendingAddress = fmt.Sprintf("%#x", s.PendingChange.Address)
+			credentials.PendingValidSignature = &s.PendingChange.ValidSignature
+			credentials.PendingPubkeyMatches = &s.PendingChange.PubkeyMatches
+		}
+		result = append(result, credentials)
+	}
+	return result, nil
+}
+
+// DashboardDataAttestationCorrectness returns the inclusion delay and the correctness of the votes of the attestations of the validators in the last 100 epochs
+func DashboardDataAttestationCorrectness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	validatorLimit := getUserPremium(r).MaxValidators
+	filterArr, err := parseValidatorsFromQueryString(q.Get("validators"), validatorLimit)
+	if err != nil {
+		http.Error(w, "Invalid query", 400)
+		return
+	}
+
+	startEpoch := uint64(0)
+	if services.LatestEpoch() > 100 {
+		startEpoch = services.LatestEpoch() - 100
+	}
+	correctness, err := db.GetAttestationCorrectness(filterArr, startEpoch)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Errorf("error retrieving attestation correctness")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	total := &types.AttestationCorrectness{}
+	for _, c := range correctness {
+		total.Included += c.Included
+		total.InclusionDelaySum += c.InclusionDelaySum
+		total.CorrectHead += c.CorrectHead
+		total.CorrectTarget += c.CorrectTarget
+		total.CorrectSource += c.CorrectSource
+	}
+
+	err = json.NewEncoder(w).Encode(formatAttestationCorrectness(total))
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// DashboardDataDuties will return the upcoming block proposals and sync committee memberships of the validators
+func DashboardDataDuties(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	validatorLimit := getUserPremium(r).MaxValidators
+	filterArr, err := parseValidatorsFromQueryString(q.Get("validators"), validatorLimit)
+	if err != nil {
+		http.Error(w, "Invalid query", 400)
+		return
+	}
+
+	duties, err := getUpcomingDuties(filterArr)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Errorf("error retrieving upcoming duties")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(duties)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// getUpcomingDuties will return the proposals of the validators that are scheduled after the latest slot and their
+// memberships in the current and the next sync committee
+func getUpcomingDuties(validators []uint64) (*types.ApiDutiesResponse, error) {
+	proposals, err := db.GetUpcomingProposerDuties(validators, services.LatestSlot()+1)
+	if err != nil {
+		return nil, err
+	}
+	syncCommittees := []*types.SyncCommitteeDuty{}
+	if utils.ForkFeatureActive(utils.ForkFeatureSyncCommittees, services.LatestEpoch()) {
+		syncCommittees, err = db.GetUpcomingSyncCommitteeDuties(validators, utils.SyncPeriodOfEpoch(services.LatestEpoch()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	duties := &types.ApiDutiesResponse{
+		Proposals:      make([]*types.ApiProposerDutyResponse, 0, len(proposals)),
+		SyncCommittees: make([]*types.ApiSyncCommitteeDutyResponse, 0, len(syncCommittees)),
+	}
+	for _, p := range proposals {
+		duties.Proposals = append(duties.Proposals, &types.ApiProposerDutyResponse{
+			ValidatorIndex: p.Validatorindex,
+			Slot:           p.Slot,
+			Epoch:          utils.EpochOfSlot(p.Slot),
+			Timestamp:      utils.SlotToTime(p.Slot).Unix(),
+		})
+	}
+	for _, s := range syncCommittees {
+		startEpoch := utils.FirstEpochOfSyncPeriod(s.Period)
+		duties.SyncCommittees = append(duties.SyncCommittees, &types.ApiSyncCommitteeDutyResponse{
+			ValidatorIndex: s.Validatorindex,
+			Period:         s.Period,
+			StartEpoch:     startEpoch,
+			EndEpoch:       utils.FirstEpochOfSyncPeriod(s.Period+1) - 1,
+			StartTimestamp: utils.EpochToTime(startEpoch).Unix(),
+		})
+	}
+	return duties, nil
+}
+
+// formatAttestationCorrectness will convert the aggregated attestation evaluations into rates
+func formatAttestationCorrectness(c *types.AttestationCorrectness) *types.ApiAttestationCorrectnessResponse {
+	return &types.ApiAttestationCorrectnessResponse{
+		Included:          c.Included,
+		AvgInclusionDelay: c.AvgInclusionDelay(),
+		HeadCorrect:       c.HeadRate(),
+		TargetCorrect:     c.TargetRate(),
+		SourceCorrect:     c.SourceRate(),
+	}
+}
@@ -64,9 +64,9 @@ func Epoch(w http.ResponseWriter, r *http.Request) {
 			validatorscount,
 			averagevalidatorbalance,
 			finalized,
-			eligibleether,
-			globalparticipationrate,
-			votedether,
+			COALESCE(eligibleether, 0) AS eligibleether,
+			COALESCE(globalparticipationrate, 0) AS globalparticipationrate,
+			COALESCE(votedether, 0) AS votedether,
 			totalvalidatorbalance
 		FROM epochs
 		WHERE epoch = $1`, epoch)
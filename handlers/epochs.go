@@ -82,9 +82,9 @@ func Epochs(w http.ResponseWriter, r *http.Request) {
 		validatorscount, 
 		averagevalidatorbalance, 
 		finalized,
-		eligibleether,
-		globalparticipationrate,
-		votedether
+		COALESCE(eligibleether, 0) AS eligibleether,
+		COALESCE(globalparticipationrate, 0) AS globalparticipationrate,
+		COALESCE(votedether, 0) AS votedether
 	FROM epochs 
 	WHERE epoch >= $1 AND epoch <= $2
 	ORDER BY epoch DESC`, endEpoch, startEpoch)
@@ -190,9 +190,9 @@ func EpochsData(w http.ResponseWriter, r *http.Request) {
 				validatorscount, 
 				averagevalidatorbalance, 
 				finalized,
-				eligibleether,
-				globalparticipationrate,
-				votedether
+				COALESCE(eligibleether, 0) AS eligibleether,
+				COALESCE(globalparticipationrate, 0) AS globalparticipationrate,
+				COALESCE(votedether, 0) AS votedether
 			FROM epochs 
 			WHERE epoch >= $1 AND epoch <= $2
 			ORDER BY epoch DESC`, endEpoch, startEpoch)
@@ -208,9 +208,9 @@ func EpochsData(w http.ResponseWriter, r *http.Request) {
 				validatorscount, 
 				averagevalidatorbalance, 
 				finalized,
-				eligibleether,
-				globalparticipationrate,
-				votedether
+				COALESCE(eligibleether, 0) AS eligibleether,
+				COALESCE(globalparticipationrate, 0) AS globalparticipationrate,
+				COALESCE(votedether, 0) AS votedether
 			FROM epochs 
 			WHERE epoch = $1
 			ORDER BY epoch DESC`, search)
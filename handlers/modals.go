@@ -174,6 +174,7 @@ func UserModalManageNotificationModal(w http.ResponseWriter, r *http.Request) {
 	events[types.ValidatorExecutedProposalEventName] = "on" == r.FormValue(string(types.ValidatorExecutedProposalEventName))
 	events[types.ValidatorGotSlashedEventName] = "on" == r.FormValue(string(types.ValidatorGotSlashedEventName))
 	events[types.SyncCommitteeSoon] = "on" == r.FormValue(string(types.SyncCommitteeSoon))
+	events[types.ValidatorWithdrawalEventName] = "on" == r.FormValue(string(types.ValidatorWithdrawalEventName))
 
 	all := "on" == r.FormValue("all")
 
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+)
+
+var reorgsTemplate = template.Must(template.New("reorgs").Funcs(utils.GetTemplateFuncs()).ParseFiles("templates/layout.html", "templates/reorgs.html"))
+
+// Reorgs returns the chain reorgs detected by the exporter using a go template
+func Reorgs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "more", "/reorgs", "Chain Reorgs")
+	data.HeaderAd = true
+
+	err := reorgsTemplate.ExecuteTemplate(w, "layout", data)
+
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+}
+
+// ReorgsData returns the chain reorgs detected by the exporter in json
+func ReorgsData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+
+	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables data parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables start parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables length parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+	if length > 100 {
+		length = 100
+	}
+
+	var reorgs []*types.Reorg
+	err = db.ReaderDb.Select(&reorgs, `
+		SELECT id, slot, depth, common_ancestor_slot, old_head_slot, old_head_root, new_head_slot, new_head_root, orphaned_blocks, detected_at
+		FROM reorgs
+		ORDER BY slot DESC, id DESC
+		LIMIT $1 OFFSET $2`, length, start)
+	if err != nil {
+		logger.Errorf("error retrieving reorgs: %v", err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+
+	var records uint64
+	err = db.ReaderDb.Get(&records, "SELECT COUNT(*) FROM reorgs")
+	if err != nil {
+		logger.Errorf("error retrieving reorg count: %v", err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+
+	tableData := make([][]interface{}, 0, len(reorgs))
+	for _, reorg := range reorgs {
+		tableData = append(tableData, []interface{}{
+			utils.FormatBlockSlot(reorg.Slot),
+			utils.FormatTimestamp(reorg.DetectedAt.Unix()),
+			reorg.Depth,
+			reorg.OrphanedBlocks,
+			template.HTML(fmt.Sprintf("%v %v", utils.FormatBlockRoot(reorg.OldHeadRoot), utils.FormatBlockSlot(reorg.OldHeadSlot))),
+			template.HTML(fmt.Sprintf("%v %v", utils.FormatBlockRoot(reorg.NewHeadRoot), utils.FormatBlockSlot(reorg.NewHeadSlot))),
+			utils.FormatBlockSlot(reorg.CommonAncestorSlot),
+		})
+	}
+
+	data := &types.DataTableResponse{
+		Draw:            draw,
+		RecordsTotal:    records,
+		RecordsFiltered: records,
+		Data:            tableData,
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+}
@@ -38,11 +38,20 @@ func Search(w http.ResponseWriter, r *http.Request) {
 	search = strings.Replace(search, "0x", "", -1)
 
 	if len(search) == 64 {
+		var isTx bool
+		err = db.ReaderDb.Get(&isTx, "SELECT EXISTS (SELECT 1 FROM blocks_transactions WHERE txhash = DECODE($1, 'hex'))", strings.ToLower(search))
+		if err != nil {
+			logger.Errorf("error checking if %v is a transaction hash: %v", search, err)
+		}
+		if isTx {
+			http.Redirect(w, r, "/tx/0x"+search, 301)
+			return
+		}
 		http.Redirect(w, r, "/block/"+search, 301)
 	} else if len(search) == 96 {
 		http.Redirect(w, r, "/validator/"+search, 301)
 	} else if utils.IsValidEth1Address(search) {
-		http.Redirect(w, r, "/validators/eth1deposits?q="+search, 301)
+		http.Redirect(w, r, "/address/0x"+search, 301)
 	} else {
 		w.Header().Set("Content-Type", "text/html")
 		data := InitPageData(w, r, "search", "/search", "")
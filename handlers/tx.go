@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var txTemplate = template.Must(template.New("tx").Funcs(utils.GetTemplateFuncs()).ParseFiles("templates/layout.html", "templates/tx.html"))
+var txNotFoundTemplate = template.Must(template.New("txnotfound").Funcs(utils.GetTemplateFuncs()).ParseFiles("templates/layout.html", "templates/txnotfound.html"))
+
+// Tx will return the data of an execution transaction and its receipt
+func Tx(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	vars := mux.Vars(r)
+	data := InitPageData(w, r, "blocks", "/tx", "Transaction")
+
+	txHash, err := hex.DecodeString(strings.Replace(vars["hash"], "0x", "", -1))
+	if err != nil || len(txHash) != 32 {
+		txNotFound(w, r, data)
+		return
+	}
+
+	txPageData, err := getTxPageData(txHash)
+	if err != nil {
+		logger.Errorf("error retrieving transaction data for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if txPageData == nil {
+		txNotFound(w, r, data)
+		return
+	}
+
+	data.Meta.Title = fmt.Sprintf("%v - Transaction 0x%x - www.agorascan.io - %v", utils.Config.Frontend.SiteName, txHash, time.Now().Year())
+	data.Data = txPageData
+
+	err = txTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+func txNotFound(w http.ResponseWriter, r *http.Request, data *types.PageData) {
+	err := txNotFoundTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getTxPageData will return the transaction with the given hash and its logs, returns nil if the transaction is not known
+func getTxPageData(txHash []byte) (*types.TxPageData, error) {
+	tx, err := db.GetExecutionTransaction(txHash)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+
+	tx.Epoch = utils.EpochOfSlot(tx.BlockSlot)
+	tx.Ts = utils.SlotToTime(tx.BlockSlot)
+
+	amount := new(big.Int).SetBytes(tx.Amount)
+	tx.AmountPretty = ToEth(amount)
+	price := new(big.Int).SetBytes(tx.Price)
+	tx.PricePretty = ToEth(price.Mul(price, big.NewInt(1e9)))
+
+	if tx.GasUsed.Valid && tx.EffectiveGasPrice.Valid {
+		if price, ok := new(big.Int).SetString(tx.EffectiveGasPrice.String, 10); ok {
+			tx.FeePretty = ToEth(price.Mul(price, big.NewInt(tx.GasUsed.Int64)))
+		}
+	}
+
+	if tx.LogsCount.Int64 > 0 {
+		tx.Logs, err = db.GetTransactionLogs(tx.BlockSlot, tx.BlockRoot, tx.BlockIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
@@ -7,12 +7,14 @@ import (
 	"errors"
 	"eth2-exporter/db"
 	"eth2-exporter/mail"
+	"eth2-exporter/notify"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
 	"html/template"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -859,7 +861,10 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 	err = db.FrontendReaderDB.Select(&notificationChannels, `
 		SELECT
 			channel,
-			active
+			active,
+			destination,
+			server,
+			token IS NOT NULL AND token != '' AS has_token
 		FROM
 			users_notification_channels
 		WHERE
@@ -903,6 +908,39 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 			Active:  true,
 		})
 	}
+	// chat channels are inactive until the user entered a destination
+	for _, chatChannel := range types.ChatNotificationChannels {
+		if chatChannel == types.TelegramNotificationChannel && utils.Config.Notifications.TelegramBotToken == "" {
+			continue
+		}
+		configured := false
+		for _, ch := range notificationChannels {
+			if ch.Channel == chatChannel {
+				configured = true
+				break
+			}
+		}
+		if !configured {
+			notificationChannels = append(notificationChannels, types.UserNotificationChannels{
+				Channel: chatChannel,
+				Active:  false,
+			})
+		}
+	}
+	for i, ch := range notificationChannels {
+		if ch.Channel != types.TelegramNotificationChannel || utils.Config.Notifications.TelegramBotToken == "" {
+			continue
+		}
+		notificationChannels[i].LinkToken, err = db.GetTelegramLinkToken(user.UserID)
+		if err != nil {
+			logger.WithError(err).Errorf("error retrieving telegram link token")
+			http.Error(w, "Internal server error", 503)
+			return
+		}
+		if utils.Config.Notifications.TelegramBotName != "" {
+			notificationChannels[i].LinkURL = fmt.Sprintf("https://t.me/%v?start=%v", url.PathEscape(utils.Config.Notifications.TelegramBotName), notificationChannels[i].LinkToken)
+		}
+	}
 
 	events := make([]types.EventNameCheckbox, 0)
 	for _, ev := range types.AddWatchlistEvents {
@@ -940,9 +978,31 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 		Events:    events,
 	}
 
+	settingsByUserID, err := db.GetUserNotificationSettings([]uint64{user.UserID})
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving notification settings of user %v", user.UserID)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+	notificationSettings, exists := settingsByUserID[user.UserID]
+	if !exists {
+		notificationSettings = &types.UserNotificationSettings{
+			UserID:   user.UserID,
+			Delivery: types.ImmediateNotificationDelivery,
+			Timezone: "UTC",
+		}
+	}
+	hours := make([]uint64, 24)
+	for i := range hours {
+		hours[i] = uint64(i)
+	}
+
 	userNotificationsCenterData.NotificationChannelsModal = types.NotificationChannelsModal{
 		CsrfField:            csrf.TemplateField(r),
 		NotificationChannels: notificationChannels,
+		Settings:             notificationSettings,
+		Deliveries:           []types.NotificationDelivery{types.ImmediateNotificationDelivery, types.HourlyNotificationDelivery, types.DailyNotificationDelivery},
+		Hours:                hours,
 	}
 	userNotificationsCenterData.NetworkEventModal = types.NetworkEventModal{
 		CsrfField: csrf.TemplateField(r),
@@ -1878,12 +1938,20 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 	isPkey := !pkeyRegex.MatchString(filter)
 	filterLen := len(filter)
 
-	if filterLen != 96 && filterLen != 0 && isPkey {
+	// withdrawals can also be watched for an execution address
+	isAddress := eventName == types.ValidatorWithdrawalEventName && filterLen == 40
+
+	if filterLen != 96 && filterLen != 0 && isPkey && !isAddress {
 		logger.Errorf("error invalid pubkey characters or length: %v", err)
 		ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
 		return false
 	}
 
+	// addresses are matched against the hex encoded address of a withdrawal, so a checksummed address has to be lowercased
+	if isAddress {
+		filter = strings.ToLower(filter)
+	}
+
 	userPremium := getUserPremium(r)
 
 	filterWatchlist := db.WatchlistFilter{
@@ -2045,13 +2113,18 @@ func internUserNotificationsUnsubscribe(event, filter string, w http.ResponseWri
 
 	isPkey := !pkeyRegex.MatchString(filter)
 	filterLen := len(filter)
+	isAddress := eventName == types.ValidatorWithdrawalEventName && filterLen == 40
 
-	if len(filter) != 96 && filterLen != 0 && isPkey {
+	if len(filter) != 96 && filterLen != 0 && isPkey && !isAddress {
 		logger.Errorf("error invalid pubkey characters or length: %v", err)
 		ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
 		return false
 	}
 
+	if isAddress {
+		filter = strings.ToLower(filter)
+	}
+
 	filterWatchlist := db.WatchlistFilter{
 		UserId:         user.UserID,
 		Validators:     nil,
@@ -2157,13 +2230,18 @@ func UserNotificationsUnsubscribe(w http.ResponseWriter, r *http.Request) {
 
 	isPkey := !pkeyRegex.MatchString(filter)
 	filterLen := len(filter)
+	isAddress := eventName == types.ValidatorWithdrawalEventName && filterLen == 40
 
-	if len(filter) != 96 && filterLen != 0 && isPkey {
+	if len(filter) != 96 && filterLen != 0 && isPkey && !isAddress {
 		logger.Errorf("error invalid pubkey characters or length: %v", err)
 		ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	if isAddress {
+		filter = strings.ToLower(filter)
+	}
+
 	if filterLen == 0 && !types.IsUserIndexed(eventName) { // no filter = add all my watched validators
 
 		filter := db.WatchlistFilter{
@@ -2481,7 +2559,8 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			event_names,
 			destination,
 			request,
-			response
+			response,
+			secret
 		FROM users_webhooks
 		WHERE user_id = $1;
 	`, user.UserID)
@@ -2532,6 +2611,11 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			EventName:  types.SyncCommitteeSoon,
 			Active:     utils.ElementExists(wh.EventNames, string(types.SyncCommitteeSoon)),
 		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Withdrawal",
+			EventName:  types.ValidatorWithdrawalEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorWithdrawalEventName)),
+		})
 		events = append(events, types.EventNameCheckbox{
 			EventLabel: "Machine Offline",
 			EventName:  types.MonitoringMachineOfflineEventName,
@@ -2590,6 +2674,7 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			Discord:      isDiscord,
 			CsrfField:    csrf.TemplateField(r),
 			WebhookError: whErr,
+			Secret:       hex.EncodeToString(wh.Secret),
 		})
 
 	}
@@ -2597,6 +2682,30 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 	pageData.Webhooks = webhooks
 	pageData.WebhookRows = webhookRows
 
+	err = db.FrontendReaderDB.SelectContext(ctx, &pageData.Deliveries, `
+		SELECT
+			d.id,
+			d.webhook_id,
+			uw.url,
+			d.idempotency_key,
+			d.channel,
+			d.content,
+			d.attempt,
+			d.ts,
+			d.status_code,
+			d.response,
+			d.error
+		FROM users_webhook_deliveries d
+		INNER JOIN users_webhooks uw ON uw.id = d.webhook_id
+		WHERE uw.user_id = $1
+		ORDER BY d.ts DESC
+		LIMIT 50`, user.UserID)
+	if err != nil {
+		logger.Errorf("error querying for webhook deliveries for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+
 	// logger.Infof("events: %+v", webhooks)
 
 	events := make([]types.EventNameCheckbox, 0, 7)
@@ -2621,6 +2730,10 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 		EventLabel: "Sync Commitee Soon",
 		EventName:  types.SyncCommitteeSoon,
 	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Withdrawal",
+		EventName:  types.ValidatorWithdrawalEventName,
+	})
 	events = append(events, types.EventNameCheckbox{
 		EventLabel: "Machine Offline",
 		EventName:  types.MonitoringMachineOfflineEventName,
@@ -2672,6 +2785,7 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	validatorProposalSubmitted := "on" == r.FormValue(string(types.ValidatorExecutedProposalEventName))
 	validatorGotSlashed := "on" == r.FormValue(string(types.ValidatorGotSlashedEventName))
 	validatorSyncCommiteeSoon := "on" == r.FormValue(string(types.SyncCommitteeSoon))
+	validatorWithdrawal := "on" == r.FormValue(string(types.ValidatorWithdrawalEventName))
 	monitoringMachineOffline := "on" == r.FormValue(string(types.MonitoringMachineOfflineEventName))
 	monitoringHddAlmostfull := "on" == r.FormValue(string(types.MonitoringMachineDiskAlmostFullEventName))
 	monitoringCpuLoad := "on" == r.FormValue(string(types.MonitoringMachineCpuLoadEventName))
@@ -2690,6 +2804,7 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	events[string(types.ValidatorExecutedProposalEventName)] = validatorProposalSubmitted
 	events[string(types.ValidatorGotSlashedEventName)] = validatorGotSlashed
 	events[string(types.SyncCommitteeSoon)] = validatorSyncCommiteeSoon
+	events[string(types.ValidatorWithdrawalEventName)] = validatorWithdrawal
 	events[string(types.MonitoringMachineOfflineEventName)] = monitoringMachineOffline
 	events[string(types.MonitoringMachineDiskAlmostFullEventName)] = monitoringHddAlmostfull
 	events[string(types.MonitoringMachineCpuLoadEventName)] = monitoringCpuLoad
@@ -2772,7 +2887,15 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 		urlValid = urlForm
 	}
 
-	_, err = tx.Exec(`INSERT INTO users_webhooks (user_id, url, event_names, destination) VALUES ($1, $2, $3, $4)`, user.UserID, urlValid, pq.StringArray(eventNames), destination)
+	secret, err := utils.GenerateRandomBytesSecure(32)
+	if err != nil {
+		logger.WithError(err).Errorf("error generating webhook secret")
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong adding your webhook, please try again in a bit.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	_, err = tx.Exec(`INSERT INTO users_webhooks (user_id, url, event_names, destination, secret) VALUES ($1, $2, $3, $4, $5)`, user.UserID, urlValid, pq.StringArray(eventNames), destination, secret)
 	if err != nil {
 		logger.WithError(err).Errorf("error inserting a new webhook for user")
 		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong adding your webhook, please try again in a bit.")
@@ -2817,6 +2940,7 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	validatorProposalSubmitted := "on" == r.FormValue(string(types.ValidatorExecutedProposalEventName))
 	validatorGotSlashed := "on" == r.FormValue(string(types.ValidatorGotSlashedEventName))
 	validatorSyncCommiteeSoon := "on" == r.FormValue(string(types.SyncCommitteeSoon))
+	validatorWithdrawal := "on" == r.FormValue(string(types.ValidatorWithdrawalEventName))
 	monitoringMachineOffline := "on" == r.FormValue(string(types.MonitoringMachineOfflineEventName))
 	monitoringHddAlmostfull := "on" == r.FormValue(string(types.MonitoringMachineDiskAlmostFullEventName))
 	monitoringCpuLoad := "on" == r.FormValue(string(types.MonitoringMachineCpuLoadEventName))
@@ -2835,6 +2959,7 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	events[string(types.ValidatorExecutedProposalEventName)] = validatorProposalSubmitted
 	events[string(types.ValidatorGotSlashedEventName)] = validatorGotSlashed
 	events[string(types.SyncCommitteeSoon)] = validatorSyncCommiteeSoon
+	events[string(types.ValidatorWithdrawalEventName)] = validatorWithdrawal
 	events[string(types.MonitoringMachineOfflineEventName)] = monitoringMachineOffline
 	events[string(types.MonitoringMachineDiskAlmostFullEventName)] = monitoringHddAlmostfull
 	events[string(types.MonitoringMachineCpuLoadEventName)] = monitoringCpuLoad
@@ -2909,6 +3034,13 @@ func UsersDeleteWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 	defer tx.Rollback()
 
+	_, err = tx.Exec(`DELETE FROM users_webhook_deliveries where webhook_id = (SELECT id FROM users_webhooks where user_id = $1 and id = $2)`, user.UserID, webhookID)
+	if err != nil {
+		logger.WithError(err).Errorf("error deleting webhook deliveries for user")
+		http.Error(w, "Internal server error", 503)
+		return
+	}
+
 	_, err = tx.Exec(`DELETE FROM users_webhooks where user_id = $1 and id = $2`, user.UserID, webhookID)
 	if err != nil {
 		logger.WithError(err).Errorf("error update webhook for user")
@@ -2925,6 +3057,113 @@ func UsersDeleteWebhook(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 }
 
+// isPublicHost returns true if the host and every address it resolves to are reachable from the internet
+func isPublicHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return notify.IsPublicIP(ip)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !notify.IsPublicIP(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseChatChannelForm will read and validate the settings of a chat channel from the notification channels form,
+// the destination is null if the user cleared it. Telegram chats are not part of the form, they are linked by the bot.
+func parseChatChannelForm(r *http.Request, channel types.NotificationChannel) (destination, server, token sql.NullString, err error) {
+	destinationForm := strings.TrimSpace(r.FormValue(string(channel) + "_destination"))
+	serverForm := strings.TrimSpace(r.FormValue(string(channel) + "_server"))
+	tokenForm := strings.TrimSpace(r.FormValue(string(channel) + "_token"))
+	if destinationForm == "" {
+		return destination, server, token, nil
+	}
+
+	switch channel {
+	case types.SlackNotificationChannel:
+		if !notify.IsSlackWebhookUrl(destinationForm) {
+			return destination, server, token, fmt.Errorf("The Slack webhook URL is invalid, it starts with %v.", notify.SlackWebhookUrlPrefix)
+		}
+	case types.MatrixNotificationChannel:
+		if !strings.HasPrefix(destinationForm, "!") || !strings.Contains(destinationForm, ":") {
+			return destination, server, token, errors.New("The Matrix room id is invalid, it has the format !room:example.org.")
+		}
+		u, err := url.Parse(serverForm)
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+			return destination, server, token, errors.New("The Matrix homeserver URL is invalid, it has to start with https://.")
+		}
+		if !isPublicHost(u.Hostname()) {
+			return destination, server, token, errors.New("The Matrix homeserver has to be reachable from the internet.")
+		}
+		server = sql.NullString{String: serverForm, Valid: true}
+	default:
+		return destination, server, token, fmt.Errorf("The %v channel can not be set up with the form.", channel)
+	}
+	if len(destinationForm) > 500 || len(serverForm) > 500 || len(tokenForm) > 500 {
+		return destination, server, token, fmt.Errorf("The settings of the %v channel are too long.", channel)
+	}
+
+	destination = sql.NullString{String: destinationForm, Valid: true}
+	if tokenForm != "" {
+		token = sql.NullString{String: tokenForm, Valid: true}
+	}
+	return destination, server, token, nil
+}
+
+// UsersResendWebhookDelivery will queue the message of a webhook delivery again. A resend is requested explicitly by
+// the user, so it gets a new idempotency key and is not discarded by receivers that already processed the original message
+func UsersResendWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	user := getUser(r)
+
+	vars := mux.Vars(r)
+
+	deliveryID, err := strconv.ParseUint(vars["deliveryID"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid delivery.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	key, err := utils.GenerateRandomBytesSecure(32)
+	if err != nil {
+		logger.WithError(err).Errorf("error generating idempotency key for webhook delivery %v", deliveryID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong resending the message, please try again in a bit.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	res, err := db.FrontendWriterDB.Exec(`
+		INSERT INTO notification_queue (created, channel, content, idempotency_key)
+		SELECT now(), d.channel, d.content, $3
+		FROM users_webhook_deliveries d
+		INNER JOIN users_webhooks uw ON uw.id = d.webhook_id
+		WHERE d.id = $1 AND uw.user_id = $2`, deliveryID, user.UserID, hex.EncodeToString(key))
+	if err != nil {
+		logger.WithError(err).Errorf("error queueing webhook delivery %v for user", deliveryID)
+		utils.SetFlash(w, r, authSessionName, "Error: Something went wrong resending the message, please try again in a bit.")
+		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+		return
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		utils.SetFlash(w, r, authSessionName, "Error: The delivery could not be found.")
+	} else {
+		utils.SetFlash(w, r, authSessionName, "The message will be sent again shortly.")
+	}
+	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
+}
+
 // UsersNotificationChannel
 // Accepts form encoded values channel and active to set the global notification settings for a user
 func UsersNotificationChannels(w http.ResponseWriter, r *http.Request) {
@@ -2970,6 +3209,51 @@ func UsersNotificationChannels(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, chatChannel := range types.ChatNotificationChannels {
+		if _, exists := r.Form[string(chatChannel)+"_destination"]; !exists || chatChannel == types.TelegramNotificationChannel {
+			// the channel was not part of the form
+			continue
+		}
+		destination, server, token, err := parseChatChannelForm(r, chatChannel)
+		if err != nil {
+			utils.SetFlash(w, r, authSessionName, fmt.Sprintf("Error: %v", err))
+			http.Redirect(w, r, "/user/notifications", http.StatusSeeOther)
+			return
+		}
+		active := destination.Valid && r.FormValue(string(chatChannel)) == "on"
+
+		// an empty token field keeps the stored token, it is never sent to the browser
+		_, err = tx.Exec(`
+			INSERT INTO users_notification_channels (user_id, channel, active, destination, server, token) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, channel) DO UPDATE SET
+				active = excluded.active,
+				destination = excluded.destination,
+				server = excluded.server,
+				token = COALESCE(excluded.token, users_notification_channels.token)`,
+			user.UserID, chatChannel, active, destination, server, token)
+		if err != nil {
+			logger.WithError(err).Error("error updating users_notification_channels")
+			http.Error(w, "Internal server error", 503)
+			return
+		}
+	}
+
+	// the telegram chat is linked by the bot, the form can only toggle or unlink it
+	if utils.Config.Notifications.TelegramBotToken != "" {
+		unlink := r.FormValue("telegram_unlink") == "on"
+		_, err = tx.Exec(`
+			UPDATE users_notification_channels SET
+				active = $3 AND destination IS NOT NULL AND NOT $4,
+				destination = CASE WHEN $4 THEN NULL ELSE destination END
+			WHERE user_id = $1 AND channel = $2`,
+			user.UserID, types.TelegramNotificationChannel, r.FormValue(string(types.TelegramNotificationChannel)) == "on", unlink)
+		if err != nil {
+			logger.WithError(err).Error("error updating users_notification_channels")
+			http.Error(w, "Internal server error", 503)
+			return
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		logger.WithError(err).Error("error committing transaction")
@@ -2977,5 +3261,50 @@ func UsersNotificationChannels(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, exists := r.Form["delivery"]; exists {
+		settings, err := parseNotificationSettingsForm(r, user.UserID)
+		if err != nil {
+			utils.SetFlash(w, r, authSessionName, fmt.Sprintf("Error: %v", err))
+			http.Redirect(w, r, "/user/notifications", http.StatusSeeOther)
+			return
+		}
+		err = db.SaveUserNotificationSettings(settings)
+		if err != nil {
+			logger.WithError(err).Error("error saving notification settings")
+			http.Error(w, "Internal server error", 503)
+			return
+		}
+	}
+
 	http.Redirect(w, r, "/user/notifications", http.StatusSeeOther)
 }
+
+// parseNotificationSettingsForm will read and validate the delivery preferences of a user from the notification channels form
+func parseNotificationSettingsForm(r *http.Request, userID uint64) (*types.UserNotificationSettings, error) {
+	settings := &types.UserNotificationSettings{
+		UserID:   userID,
+		Delivery: types.NotificationDelivery(r.FormValue("delivery")),
+		Timezone: strings.TrimSpace(r.FormValue("timezone")),
+	}
+	if settings.Delivery.Label() == "" {
+		return nil, errors.New("The delivery mode is invalid.")
+	}
+	if settings.Timezone == "" {
+		settings.Timezone = "UTC"
+	}
+	// time.LoadLocation accepts the empty string and "Local" as the timezone of the server
+	if _, err := time.LoadLocation(settings.Timezone); err != nil || settings.Timezone == "Local" || len(settings.Timezone) > 64 {
+		return nil, errors.New("The timezone is invalid, use a name like Europe/Berlin.")
+	}
+
+	var err error
+	settings.QuietHoursStart, err = strconv.ParseUint(r.FormValue("quiet_hours_start"), 10, 64)
+	if err != nil || settings.QuietHoursStart > 23 {
+		return nil, errors.New("The start of the quiet hours is invalid.")
+	}
+	settings.QuietHoursEnd, err = strconv.ParseUint(r.FormValue("quiet_hours_end"), 10, 64)
+	if err != nil || settings.QuietHoursEnd > 23 {
+		return nil, errors.New("The end of the quiet hours is invalid.")
+	}
+	return settings, nil
+}
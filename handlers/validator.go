@@ -58,7 +58,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 
 	validatorPageData := types.ValidatorPageData{}
 
-	validatorPageData.CappellaHasHappened = latestEpoch >= (utils.Config.Chain.Config.CappellaForkEpoch)
+	validatorPageData.CappellaHasHappened = utils.ForkFeatureActive(utils.ForkFeatureWithdrawals, latestEpoch)
 
 	stats := services.GetLatestStats()
 	churnRate := stats.ValidatorChurnLimit
@@ -510,6 +510,28 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	validatorPageData.Income31d = earnings.LastMonth
 	validatorPageData.Apr = earnings.APR
 
+	// reward breakdown as exported from the rewards api, only available if the rewards exporter is enabled
+	epochsPerDay := (24 * 60 * 60) / utils.Config.Chain.Config.SlotsPerEpoch / utils.Config.Chain.Config.SecondsPerSlot
+	rewardsStartEpoch1d, rewardsStartEpoch7d := uint64(0), uint64(0)
+	if latestEpoch > epochsPerDay {
+		rewardsStartEpoch1d = latestEpoch - epochsPerDay
+	}
+	if latestEpoch > 7*epochsPerDay {
+		rewardsStartEpoch7d = latestEpoch - 7*epochsPerDay
+	}
+	validatorPageData.Rewards1d, err = db.GetValidatorRewardsSum(index, rewardsStartEpoch1d)
+	if err != nil {
+		logger.Errorf("error retrieving validator rewards of the last day: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	validatorPageData.Rewards7d, err = db.GetValidatorRewardsSum(index, rewardsStartEpoch7d)
+	if err != nil {
+		logger.Errorf("error retrieving validator rewards of the last week: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	if bytes.Equal(validatorPageData.WithdrawCredentials[:1], []byte{0x01}) {
 		// validators can have 0x01 credentials even before the cappella fork
 		validatorPageData.IsWithdrawableAddress = true
@@ -518,7 +540,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	if validatorPageData.CappellaHasHappened {
 
 		// get validator withdrawals
-		withdrawalsCount, lastWithdrawalsEpoch, err := db.GetValidatorWithdrawalsCount(validatorPageData.Index)
+		withdrawalsCount, _, err := db.GetValidatorWithdrawalsCount(validatorPageData.Index)
 		if err != nil {
 			logger.Errorf("error getting validator withdrawals count from db: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -539,53 +561,41 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			validatorPageData.IsWithdrawableAddress = true
 		}
 
-		// only calculate the expected next withdrawal if the validator is eligible
-		isFullWithdrawal := validatorPageData.CurrentBalance > 0 && validatorPageData.WithdrawableEpoch <= validatorPageData.Epoch
-		isPartialWithdrawal := validatorPageData.EffectiveBalance == utils.Config.Chain.Config.MaxEffectiveBalance && validatorPageData.CurrentBalance > utils.Config.Chain.Config.MaxEffectiveBalance
-		if stats != nil && stats.LatestValidatorWithdrawalIndex != nil && stats.TotalValidatorCount != nil && validatorPageData.IsWithdrawableAddress && (isFullWithdrawal || isPartialWithdrawal) {
-			distance, err := GetWithdrawableCountFromCursor(validatorPageData.Epoch, validatorPageData.Index, *stats.LatestValidatorWithdrawalIndex)
+		if bytes.Equal(validatorPageData.WithdrawCredentials[:1], []byte{0x00}) && blsChange == nil {
+			pendingBLSChanges, err := db.GetPendingBLSChanges([]uint64{validatorPageData.Index})
 			if err != nil {
-				logger.Errorf("error getting withdrawable validator count from cursor: %v", err)
+				logger.Errorf("error getting validator pending bls change from db: %v", err)
 				http.Error(w, "Internal server error", http.StatusInternalServerError)
 				return
 			}
+			if len(pendingBLSChanges) > 0 {
+				validatorPageData.PendingBLSChange = pendingBLSChanges[0]
+			}
+		}
 
-			timeToWithdrawal := utils.GetTimeToNextWithdrawal(distance)
+		// the next withdrawal is predicted by simulating the withdrawal sweep, no prediction exists for validators that are not eligible
+		nextWithdrawals, err := services.GetNextWithdrawals([]uint64{validatorPageData.Index})
+		if err != nil {
+			logger.Errorf("error getting validator withdrawal prediction from db: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if len(nextWithdrawals) > 0 && validatorPageData.IsWithdrawableAddress {
+			nextWithdrawal := nextWithdrawals[0]
+			withdrawalTime := utils.SlotToTime(nextWithdrawal.Slot)
 
 			// it normally takes two epochs to finalize
-			if timeToWithdrawal.After(utils.EpochToTime(latestEpoch + (latestEpoch - lastFinalizedEpoch))) {
-				address, err := utils.WithdrawalCredentialsToAddress(validatorPageData.WithdrawCredentials)
-				if err != nil {
-					// warning only as "N/A" will be displayed
-					logger.Warn("invalid withdrawal credentials")
-				}
-
+			if withdrawalTime.After(utils.EpochToTime(latestEpoch + (latestEpoch - lastFinalizedEpoch))) {
 				// create the table data
 				tableData := make([][]interface{}, 0, 1)
-				var withdrawalCredentialsTemplate template.HTML
-				if address != nil {
-					eth1ExplorerAddr := utils.Config.Frontend.Eth1Explorer
-					withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="%s/address/0x%x"><span class="text-muted">%s</span></a>`, eth1ExplorerAddr, address, utils.FormatAddress(address, nil, "", false, false, true)))
-				} else {
-					withdrawalCredentialsTemplate = `<span class="text-muted">N/A</span>`
-				}
+				withdrawalCredentialsTemplate := template.HTML(fmt.Sprintf(`<span class="text-muted">%s</span>`, utils.FormatExecutionAddress(nextWithdrawal.Address)))
 
-				var withdrawalAmont uint64
-				if isFullWithdrawal {
-					withdrawalAmont = validatorPageData.CurrentBalance
-				} else {
-					withdrawalAmont = validatorPageData.CurrentBalance - utils.Config.Chain.Config.MaxEffectiveBalance
-				}
-
-				if latestEpoch == lastWithdrawalsEpoch {
-					withdrawalAmont = 0
-				}
 				tableData = append(tableData, []interface{}{
-					template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(uint64(utils.TimeToEpoch(timeToWithdrawal))))),
-					template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(utils.TimeToSlot(uint64(timeToWithdrawal.Unix()))))),
-					template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(timeToWithdrawal.Unix()))),
+					template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(utils.EpochOfSlot(nextWithdrawal.Slot)))),
+					template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(nextWithdrawal.Slot))),
+					template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(withdrawalTime.Unix()))),
 					withdrawalCredentialsTemplate,
-					template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="If the withdrawal were to be processed at this very moment, this amount would be withdrawn"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatAmount(new(big.Int).Mul(new(big.Int).SetUint64(withdrawalAmont), big.NewInt(1e9)), "BOA", 6))),
+					template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="Predicted by simulating the withdrawal sweep with the current balances of all validators"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatAmount(new(big.Int).Mul(new(big.Int).SetUint64(nextWithdrawal.Amount), big.NewInt(1e9)), "BOA", 6))),
 				})
 
 				validatorPageData.NextWithdrawalRow = tableData
@@ -654,6 +664,20 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		validatorPageData.AttestationInclusionEffectiveness = 1.0 / validatorPageData.AverageAttestationInclusionDistance * 100
 	}
 
+	attestationCorrectnessStartEpoch := uint64(0)
+	if validatorPageData.Epoch > 100 {
+		attestationCorrectnessStartEpoch = validatorPageData.Epoch - 100
+	}
+	attestationCorrectness, err := db.GetAttestationCorrectness([]uint64{index}, attestationCorrectnessStartEpoch)
+	if err != nil {
+		logger.Errorf("error retrieving attestation correctness: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(attestationCorrectness) > 0 && attestationCorrectness[0].Included > 0 {
+		validatorPageData.AttestationCorrectness = attestationCorrectness[0]
+	}
+
 	var attestationStreaks []struct {
 		Length uint64
 	}
@@ -717,6 +741,22 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		validatorPageData.OrphanedSyncCount = syncStats.OrphanedSync + syncStatsNotInStats.OrphanedSync
 
 		validatorPageData.UnmissedSyncPercentage = float64(validatorPageData.SyncCount-validatorPageData.MissedSyncCount) / float64(validatorPageData.SyncCount)
+
+		syncPeriods, err := db.GetValidatorSyncCommitteePeriods(index)
+		if err != nil {
+			logger.Errorf("error retrieving validator sync committee periods: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		for _, period := range syncPeriods {
+			performance, err := db.GetSyncCommitteePerformance(period, []uint64{index})
+			if err != nil {
+				logger.Errorf("error retrieving validator sync committee performance: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			validatorPageData.SyncCommitteePerformance = append(validatorPageData.SyncCommitteePerformance, performance...)
+		}
 	}
 
 	// add rocketpool-data if available
@@ -885,12 +925,13 @@ func ValidatorProposedBlocks(w http.ResponseWriter, r *http.Request) {
 
 	orderColumn := q.Get("order[0][column]")
 	orderByMap := map[string]string{
-		"0": "epoch",
-		"2": "status",
-		"5": "attestationscount",
-		"6": "depositscount",
-		"8": "voluntaryexitscount",
-		"9": "graffiti",
+		"0":  "epoch",
+		"2":  "status",
+		"5":  "attestationscount",
+		"6":  "depositscount",
+		"8":  "voluntaryexitscount",
+		"9":  "COALESCE(exec_priority_fees, 0)",
+		"10": "graffiti",
 	}
 	orderBy, exists := orderByMap[orderColumn]
 	if !exists {
@@ -915,7 +956,8 @@ func ValidatorProposedBlocks(w http.ResponseWriter, r *http.Request) {
 			blocks.proposerslashingscount,
 			blocks.attesterslashingscount,
 			blocks.status,
-			blocks.graffiti
+			blocks.graffiti,
+			blocks.exec_priority_fees::text AS exec_priority_fees
 		FROM blocks
 		WHERE blocks.proposer = $1
 		ORDER BY `+orderBy+` `+orderDir+`
@@ -929,6 +971,12 @@ func ValidatorProposedBlocks(w http.ResponseWriter, r *http.Request) {
 
 	tableData := make([][]interface{}, len(blocks))
 	for i, b := range blocks {
+		execRewards := template.HTML("-")
+		if b.ExecPriorityFees != nil {
+			if fees, ok := new(big.Int).SetString(*b.ExecPriorityFees, 10); ok {
+				execRewards = utils.FormatAmount(fees, "BOA", 5)
+			}
+		}
 		tableData[i] = []interface{}{
 			utils.FormatEpoch(b.Epoch),
 			utils.FormatBlockSlot(b.Slot),
@@ -939,6 +987,7 @@ func ValidatorProposedBlocks(w http.ResponseWriter, r *http.Request) {
 			b.Deposits,
 			fmt.Sprintf("%v / %v", b.Proposerslashings, b.Attesterslashings),
 			b.Exits,
+			execRewards,
 			utils.FormatGraffiti(b.Graffiti),
 		}
 	}
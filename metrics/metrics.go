@@ -59,6 +59,26 @@ var (
 		Name: "notifications_sent",
 		Help: "Counter of notifications sent with the channel and notification type in the label",
 	}, []string{"channel", "status"})
+	RPCNodeHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rpc_node_healthy",
+		Help: "Gauge that is 1 if the beacon-node in the label passed the last health-check",
+	}, []string{"node"})
+	BackfillEpochsTotal = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backfill_epochs_total",
+		Help: "Gauge of the number of epochs a backfill has to export with the backfill in the label",
+	}, []string{"backfill"})
+	BackfillEpochsDone = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backfill_epochs_done",
+		Help: "Gauge of the number of epochs a backfill has exported with the backfill in the label",
+	}, []string{"backfill"})
+	BackfillETA = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backfill_eta_seconds",
+		Help: "Gauge of the estimated seconds until a backfill completes with the backfill in the label",
+	}, []string{"backfill"})
+	ExportFailures = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "export_failures",
+		Help: "Gauge of the number of epochs that failed to export and are awaiting a retry",
+	})
 )
 
 var logger = logrus.New().WithField("module", "metrics")
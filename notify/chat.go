@@ -0,0 +1,318 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultTelegramApiUrl is the bot api used if no other url is configured
+const DefaultTelegramApiUrl = "https://api.telegram.org"
+
+// SlackWebhookUrlPrefix is the prefix of every slack incoming webhook url
+const SlackWebhookUrlPrefix = "https://hooks.slack.com/"
+
+// IsSlackWebhookUrl returns true if the url points to a slack incoming webhook
+func IsSlackWebhookUrl(webhookUrl string) bool {
+	u, err := url.Parse(webhookUrl)
+	return err == nil && strings.HasPrefix(webhookUrl, SlackWebhookUrlPrefix) && u.Host == "hooks.slack.com" && u.User == nil
+}
+
+// RateLimitError is returned if a chat platform rejected a message because of its rate limit
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %v", e.RetryAfter)
+}
+
+// cgnatRange is the shared address space of carrier-grade nat, it is not reachable from the internet
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP returns false for loopback, private, link-local and other addresses that are not reachable from the internet
+func IsPublicIP(ip net.IP) bool {
+	return ip != nil && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !cgnatRange.Contains(ip)
+}
+
+// publicOnlyTransport will only connect to public addresses, it is used for servers that users configure so they can
+// not make us send requests into the internal network. The address is checked after the name has been resolved and
+// for every connection, including those of redirects.
+func publicOnlyTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: time.Second * 30,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(net.ParseIP(host)) {
+				return fmt.Errorf("error connecting to non-public address %v", host)
+			}
+			return nil
+		},
+	}
+	return &http.Transport{
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		},
+		TLSHandshakeTimeout: time.Second * 10,
+	}
+}
+
+// RateLimiter enforces a minimum interval between requests with the same key
+type RateLimiter struct {
+	interval time.Duration
+	mux      sync.Mutex
+	next     map[string]time.Time
+}
+
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{interval: interval, next: make(map[string]time.Time)}
+}
+
+// Wait will block until a request with the key is allowed
+func (l *RateLimiter) Wait(key string) {
+	l.mux.Lock()
+	now := time.Now()
+	next, exists := l.next[key]
+	if !exists || next.Before(now) {
+		next = now
+	}
+	l.next[key] = next.Add(l.interval)
+	l.mux.Unlock()
+
+	time.Sleep(time.Until(next))
+}
+
+// TelegramClient sends messages with a telegram bot. Telegram allows a bot about one message per second per chat and
+// 30 messages per second overall.
+type TelegramClient struct {
+	ApiUrl   string
+	BotToken string
+	Client   *http.Client
+
+	chatLimiter   *RateLimiter
+	globalLimiter *RateLimiter
+}
+
+func NewTelegramClient(apiUrl, botToken string) *TelegramClient {
+	if apiUrl == "" {
+		apiUrl = DefaultTelegramApiUrl
+	}
+	return &TelegramClient{
+		ApiUrl:        strings.TrimSuffix(apiUrl, "/"),
+		BotToken:      botToken,
+		Client:        &http.Client{Timeout: time.Second * 30},
+		chatLimiter:   NewRateLimiter(time.Second),
+		globalLimiter: NewRateLimiter(time.Second / 30),
+	}
+}
+
+// Send will post an html formatted message to a chat
+func (c *TelegramClient) Send(chatID, text string) error {
+	c.globalLimiter.Wait("")
+	c.chatLimiter.Wait(chatID)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client.Post(fmt.Sprintf("%v/bot%v/sendMessage", c.ApiUrl, c.BotToken), "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the error contains the url and with it the token of the bot
+		return fmt.Errorf("error sending telegram message to chat %v", chatID)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int64 `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{RetryAfter: time.Duration(result.Parameters.RetryAfter) * time.Second}
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding telegram response with status %v: %v", resp.Status, err)
+	}
+	if !result.Ok {
+		return fmt.Errorf("error telegram rejected message with status %v: %v", resp.Status, result.Description)
+	}
+	return nil
+}
+
+// TelegramUpdate is a message sent to the bot
+type TelegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// GetUpdates will return the messages sent to the bot after the given update id. The request waits up to timeout for
+// new messages, updates before offset are confirmed and not returned again.
+func (c *TelegramClient) GetUpdates(offset int64, timeout time.Duration) ([]*TelegramUpdate, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int64(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Post(fmt.Sprintf("%v/bot%v/getUpdates", c.ApiUrl, c.BotToken), "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the error contains the url and with it the token of the bot
+		return nil, fmt.Errorf("error retrieving telegram updates")
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok          bool              `json:"ok"`
+		Description string            `json:"description"`
+		Result      []*TelegramUpdate `json:"result"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<22)).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding telegram updates with status %v: %v", resp.Status, err)
+	}
+	if !result.Ok {
+		return nil, fmt.Errorf("error telegram rejected update request with status %v: %v", resp.Status, result.Description)
+	}
+	return result.Result, nil
+}
+
+// SlackClient posts messages to slack incoming webhooks, which accept about one message per second
+type SlackClient struct {
+	Client *http.Client
+
+	limiter *RateLimiter
+}
+
+func NewSlackClient() *SlackClient {
+	return &SlackClient{
+		Client:  &http.Client{Timeout: time.Second * 30},
+		limiter: NewRateLimiter(time.Second),
+	}
+}
+
+// Send will post a message in slack mrkdwn to an incoming webhook
+func (c *SlackClient) Send(webhookUrl, text string) error {
+	if !IsSlackWebhookUrl(webhookUrl) {
+		return fmt.Errorf("error slack webhook url does not start with %v", SlackWebhookUrlPrefix)
+	}
+	c.limiter.Wait(webhookUrl)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"text":         text,
+		"mrkdwn":       true,
+		"unfurl_links": false,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client.Post(webhookUrl, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the url of an incoming webhook is its secret
+		return fmt.Errorf("error sending slack message")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64)
+		return &RateLimitError{RetryAfter: time.Duration(retryAfter) * time.Second}
+	}
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("error slack rejected message with status %v: %s", resp.Status, b)
+	}
+	return nil
+}
+
+// MatrixClient sends messages to matrix rooms with the client-server api of the homeserver of the user. The homeserver
+// is chosen by the user, so the default client only connects to public addresses.
+type MatrixClient struct {
+	Client *http.Client
+
+	limiter *RateLimiter
+}
+
+func NewMatrixClient() *MatrixClient {
+	return &MatrixClient{
+		Client: &http.Client{
+			Timeout:   time.Second * 30,
+			Transport: publicOnlyTransport(),
+		},
+		limiter: NewRateLimiter(time.Second),
+	}
+}
+
+// Send will post a message with a plain and an html body to a room. The transaction id makes the request idempotent,
+// a retried message with the same id is only shown once.
+func (c *MatrixClient) Send(homeserver, accessToken, roomID, txnID, text, formattedText string) error {
+	c.limiter.Wait(homeserver)
+
+	content := map[string]interface{}{
+		"msgtype": "m.notice",
+		"body":    text,
+	}
+	if formattedText != "" {
+		content["format"] = "org.matrix.custom.html"
+		content["formatted_body"] = formattedText
+	}
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	reqUrl := fmt.Sprintf("%v/_matrix/client/v3/rooms/%v/send/m.room.message/%v", strings.TrimSuffix(homeserver, "/"), url.PathEscape(roomID), url.PathEscape(txnID))
+	req, err := http.NewRequest(http.MethodPut, reqUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending matrix message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		var result struct {
+			RetryAfterMs int64 `json:"retry_after_ms"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result)
+		return &RateLimitError{RetryAfter: time.Duration(result.RetryAfterMs) * time.Millisecond}
+	}
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("error matrix rejected message with status %v: %s", resp.Status, b)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chatStandIn is a local stand-in for a chat platform that records the last request and answers with a fixed response
+type chatStandIn struct {
+	status  int
+	header  map[string]string
+	body    string
+	method  string
+	path    string
+	auth    string
+	payload map[string]interface{}
+}
+
+func (s *chatStandIn) start(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.method = r.Method
+		s.path = r.URL.Path
+		s.auth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		s.payload = map[string]interface{}{}
+		json.Unmarshal(b, &s.payload)
+		for k, v := range s.header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(s.status)
+		w.Write([]byte(s.body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// standInTransport sends every request to the stand-in, whatever its url is
+type standInTransport struct {
+	srv *httptest.Server
+}
+
+func (t standInTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = "http"
+	r.URL.Host = t.srv.Listener.Addr().String()
+	return t.srv.Client().Transport.RoundTrip(r)
+}
+
+func checkSendError(t *testing.T, err error, wantErr string, wantRetryAfter time.Duration) {
+	t.Helper()
+	var rateLimitErr *RateLimitError
+	switch {
+	case wantRetryAfter > 0:
+		if !errors.As(err, &rateLimitErr) {
+			t.Fatalf("expected a RateLimitError, got %v", err)
+		}
+		if rateLimitErr.RetryAfter != wantRetryAfter {
+			t.Errorf("expected retry after %v, got %v", wantRetryAfter, rateLimitErr.RetryAfter)
+		}
+	case wantErr != "":
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("expected an error containing %q, got %v", wantErr, err)
+		}
+		if errors.As(err, &rateLimitErr) {
+			t.Errorf("expected no RateLimitError, got %v", err)
+		}
+	default:
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+}
+
+func TestTelegramClientSend(t *testing.T) {
+	tests := []struct {
+		name           string
+		standIn        chatStandIn
+		wantErr        string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:    "success",
+			standIn: chatStandIn{status: http.StatusOK, body: `{"ok":true,"result":{}}`},
+		},
+		{
+			name:           "rate limited",
+			standIn:        chatStandIn{status: http.StatusTooManyRequests, body: `{"ok":false,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`},
+			wantRetryAfter: time.Second * 7,
+		},
+		{
+			name:    "rejected",
+			standIn: chatStandIn{status: http.StatusBadRequest, body: `{"ok":false,"description":"Bad Request: chat not found"}`},
+			wantErr: "chat not found",
+		},
+		{
+			name:    "invalid body",
+			standIn: chatStandIn{status: http.StatusBadGateway, body: `<html>bad gateway</html>`},
+			wantErr: "error decoding telegram response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := tt.standIn.start(t)
+			client := NewTelegramClient(srv.URL+"/", "123:secret")
+
+			err := client.Send("-100123", "<b>hello</b>")
+			checkSendError(t, err, tt.wantErr, tt.wantRetryAfter)
+
+			if tt.standIn.path != "/bot123:secret/sendMessage" {
+				t.Errorf("unexpected path %v", tt.standIn.path)
+			}
+			if tt.standIn.payload["chat_id"] != "-100123" || tt.standIn.payload["text"] != "<b>hello</b>" || tt.standIn.payload["parse_mode"] != "HTML" {
+				t.Errorf("unexpected payload %v", tt.standIn.payload)
+			}
+		})
+	}
+}
+
+func TestTelegramClientGetUpdates(t *testing.T) {
+	standIn := &chatStandIn{status: http.StatusOK, body: `{"ok":true,"result":[{"update_id":5,"message":{"text":"/start abc","chat":{"id":-42}}},{"update_id":6}]}`}
+	srv := standIn.start(t)
+	client := NewTelegramClient(srv.URL, "123:secret")
+
+	updates, err := client.GetUpdates(5, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if standIn.path != "/bot123:secret/getUpdates" || standIn.payload["offset"] != float64(5) {
+		t.Errorf("unexpected request %v %v", standIn.path, standIn.payload)
+	}
+	if len(updates) != 2 || updates[0].Message == nil || updates[0].Message.Text != "/start abc" || updates[0].Message.Chat.ID != -42 || updates[1].Message != nil {
+		t.Errorf("unexpected updates %+v", updates)
+	}
+}
+
+func TestSlackClientSend(t *testing.T) {
+	tests := []struct {
+		name           string
+		standIn        chatStandIn
+		wantErr        string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:    "success",
+			standIn: chatStandIn{status: http.StatusOK, body: `ok`},
+		},
+		{
+			name:           "rate limited",
+			standIn:        chatStandIn{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "30"}, body: `rate_limited`},
+			wantRetryAfter: time.Second * 30,
+		},
+		{
+			name:    "rejected",
+			standIn: chatStandIn{status: http.StatusNotFound, body: `no_service`},
+			wantErr: "no_service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := tt.standIn.start(t)
+			client := NewSlackClient()
+			// the stand-in takes the place of hooks.slack.com
+			client.Client = &http.Client{Transport: standInTransport{srv}}
+
+			err := client.Send(SlackWebhookUrlPrefix+"services/T0/B0/secret", "*hello*")
+			checkSendError(t, err, tt.wantErr, tt.wantRetryAfter)
+
+			if tt.standIn.path != "/services/T0/B0/secret" || tt.standIn.payload["text"] != "*hello*" {
+				t.Errorf("unexpected request %v %v", tt.standIn.path, tt.standIn.payload)
+			}
+		})
+	}
+}
+
+func TestSlackClientRejectsOtherHosts(t *testing.T) {
+	for _, webhookUrl := range []string{"https://example.com/services/x", "http://hooks.slack.com/services/x", "https://hooks.slack.com.evil.org/x", "https://user@hooks.slack.com/x"} {
+		err := NewSlackClient().Send(webhookUrl, "hello")
+		if err == nil {
+			t.Errorf("expected %v to be rejected", webhookUrl)
+		}
+	}
+}
+
+func TestMatrixClientSend(t *testing.T) {
+	tests := []struct {
+		name           string
+		standIn        chatStandIn
+		wantErr        string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:    "success",
+			standIn: chatStandIn{status: http.StatusOK, body: `{"event_id":"$1"}`},
+		},
+		{
+			name:           "rate limited",
+			standIn:        chatStandIn{status: http.StatusTooManyRequests, body: `{"errcode":"M_LIMIT_EXCEEDED","retry_after_ms":1500}`},
+			wantRetryAfter: time.Millisecond * 1500,
+		},
+		{
+			name:    "rejected",
+			standIn: chatStandIn{status: http.StatusForbidden, body: `{"errcode":"M_FORBIDDEN","error":"not in room"}`},
+			wantErr: "not in room",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := tt.standIn.start(t)
+			client := NewMatrixClient()
+			// the default client refuses to connect to the loopback address of the stand-in
+			client.Client = srv.Client()
+
+			err := client.Send(srv.URL+"/", "token", "!room:example.org", "txn 1", "hello", "<b>hello</b>")
+			checkSendError(t, err, tt.wantErr, tt.wantRetryAfter)
+
+			if tt.standIn.method != http.MethodPut || tt.standIn.path != "/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/txn 1" {
+				t.Errorf("unexpected request %v %v", tt.standIn.method, tt.standIn.path)
+			}
+			if tt.standIn.auth != "Bearer token" {
+				t.Errorf("unexpected authorization %v", tt.standIn.auth)
+			}
+			if tt.standIn.payload["body"] != "hello" || tt.standIn.payload["formatted_body"] != "<b>hello</b>" || tt.standIn.payload["format"] != "org.matrix.custom.html" {
+				t.Errorf("unexpected payload %v", tt.standIn.payload)
+			}
+		})
+	}
+}
+
+func TestMatrixClientRefusesInternalAddresses(t *testing.T) {
+	standIn := &chatStandIn{status: http.StatusOK, body: `{}`}
+	srv := standIn.start(t)
+
+	err := NewMatrixClient().Send(srv.URL, "token", "!room:example.org", "1", "hello", "")
+	if err == nil || standIn.method != "" {
+		t.Fatalf("expected the request to %v to be refused, got %v", srv.URL, err)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%v) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
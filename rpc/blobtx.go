@@ -0,0 +1,98 @@
+package rpc
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	gtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// blobTxType is the EIP-2718 type of EIP-4844 blob transactions, which the go-ethereum version in use can not decode
+const blobTxType = 0x03
+
+// blobTx is the network-less encoding of a blob transaction as included in execution payloads,
+// see https://eips.ethereum.org/EIPS/eip-4844#blob-transaction
+type blobTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         common.Address
+	Value      *big.Int
+	Data       []byte
+	AccessList gtypes.AccessList
+	BlobFeeCap *big.Int
+	BlobHashes []common.Hash
+	V, R, S    *big.Int
+}
+
+// isBlobTx returns true if the raw transaction is a blob transaction
+func isBlobTx(rawTx []byte) bool {
+	return len(rawTx) > 0 && rawTx[0] == blobTxType
+}
+
+// decodeBlobTx will decode a raw blob transaction and recover its sender
+func decodeBlobTx(rawTx []byte) (*types.Transaction, error) {
+	if !isBlobTx(rawTx) {
+		return nil, fmt.Errorf("transaction of type %#x is not a blob transaction", rawTx[0])
+	}
+
+	var decTx blobTx
+	err := rlp.DecodeBytes(rawTx[1:], &decTx)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding blob transaction: %v", err)
+	}
+
+	sigHash, err := rlp.EncodeToBytes([]interface{}{
+		decTx.ChainID,
+		decTx.Nonce,
+		decTx.GasTipCap,
+		decTx.GasFeeCap,
+		decTx.Gas,
+		decTx.To,
+		decTx.Value,
+		decTx.Data,
+		decTx.AccessList,
+		decTx.BlobFeeCap,
+		decTx.BlobHashes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding signing payload of blob transaction: %v", err)
+	}
+	if !decTx.V.IsUint64() || decTx.V.Uint64() > 1 {
+		return nil, fmt.Errorf("invalid signature parity %v of blob transaction", decTx.V)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	decTx.R.FillBytes(sig[0:32])
+	decTx.S.FillBytes(sig[32:64])
+	sig[64] = byte(decTx.V.Uint64())
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(append([]byte{blobTxType}, sigHash...)), sig)
+	if err != nil {
+		return nil, fmt.Errorf("blob transaction with invalid sender: %v", err)
+	}
+
+	tx := &types.Transaction{
+		Raw:                  rawTx,
+		TxHash:               crypto.Keccak256(rawTx),
+		AccountNonce:         decTx.Nonce,
+		Price:                decTx.GasFeeCap.Bytes(),
+		GasLimit:             decTx.Gas,
+		Sender:               crypto.PubkeyToAddress(*pubkey).Bytes(),
+		Recipient:            decTx.To.Bytes(),
+		Amount:               decTx.Value.Bytes(),
+		Payload:              decTx.Data,
+		MaxPriorityFeePerGas: decTx.GasTipCap.Uint64(),
+		MaxFeePerGas:         decTx.GasFeeCap.Uint64(),
+		MaxFeePerBlobGas:     decTx.BlobFeeCap.Uint64(),
+		BlobVersionedHashes:  make([][]byte, len(decTx.BlobHashes)),
+	}
+	for i, hash := range decTx.BlobHashes {
+		tx.BlobVersionedHashes[i] = hash.Bytes()
+	}
+	return tx, nil
+}
@@ -0,0 +1,465 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"eth2-exporter/metrics"
+	"eth2-exporter/types"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// multiClientMaxHeadLag is the number of slots a node may lag behind the best known head and still be considered synced
+const multiClientMaxHeadLag = 4
+
+// MultiClient is a composite client that routes every request to the healthiest synced node of a set of beacon-nodes.
+// If a request fails the next node is tried, so a restarting node does not stall the exporter.
+type MultiClient struct {
+	nodes         []*multiClientNode
+	nodesMux      *sync.RWMutex
+	quorum        bool
+	checkInterval time.Duration
+	chainEventCh  chan *types.ChainEvent
+}
+
+type multiClientNode struct {
+	name    string
+	client  Client
+	healthy bool
+	synced  bool
+	head    *types.ChainHead
+	latency time.Duration
+}
+
+// NewMultiClient is used to create a new composite client from a list of clients.
+// names are used for logging and have to be in the same order as clients.
+// If quorum is set, the head and finality checkpoints of all nodes are cross-checked and divergence is logged.
+func NewMultiClient(clients []Client, names []string, quorum bool, checkInterval time.Duration) (*MultiClient, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no beacon-nodes provided")
+	}
+	if len(clients) != len(names) {
+		return nil, fmt.Errorf("got %v beacon-nodes but %v names", len(clients), len(names))
+	}
+
+	mc := &MultiClient{
+		nodes:         make([]*multiClientNode, 0, len(clients)),
+		nodesMux:      &sync.RWMutex{},
+		quorum:        quorum,
+		checkInterval: checkInterval,
+		chainEventCh:  make(chan *types.ChainEvent, 10),
+	}
+	for i, client := range clients {
+		mc.nodes = append(mc.nodes, &multiClientNode{name: names[i], client: client, healthy: true, synced: true})
+	}
+
+	mc.checkNodes()
+	go func() {
+		for {
+			time.Sleep(mc.checkInterval)
+			mc.checkNodes()
+		}
+	}()
+
+	return mc, nil
+}
+
+// checkNodes will fetch the chain head of every node and update the health and sync state of the nodes
+func (mc *MultiClient) checkNodes() {
+	heads := make([]*types.ChainHead, len(mc.nodes))
+	latencies := make([]time.Duration, len(mc.nodes))
+
+	wg := &sync.WaitGroup{}
+	for i, node := range mc.nodes {
+		wg.Add(1)
+		go func(i int, node *multiClientNode) {
+			defer wg.Done()
+			start := time.Now()
+			head, err := node.client.GetChainHead()
+			if err != nil {
+				logger.Warnf("beacon-node %v failed health-check: %v", node.name, err)
+				return
+			}
+			heads[i] = head
+			latencies[i] = time.Since(start)
+		}(i, node)
+	}
+	wg.Wait()
+
+	bestHeadSlot := uint64(0)
+	for _, head := range heads {
+		if head != nil && head.HeadSlot > bestHeadSlot {
+			bestHeadSlot = head.HeadSlot
+		}
+	}
+
+	mc.nodesMux.Lock()
+	for i, node := range mc.nodes {
+		node.head = heads[i]
+		node.latency = latencies[i]
+		node.healthy = heads[i] != nil
+		node.synced = node.healthy && heads[i].HeadSlot+multiClientMaxHeadLag >= bestHeadSlot
+
+		if node.healthy {
+			metrics.RPCNodeHealthy.WithLabelValues(node.name).Set(1)
+		} else {
+			metrics.RPCNodeHealthy.WithLabelValues(node.name).Set(0)
+		}
+	}
+	mc.nodesMux.Unlock()
+
+	if mc.quorum {
+		mc.checkQuorum(heads)
+	}
+}
+
+// checkQuorum will compare the heads and finality checkpoints of all nodes and log any divergence
+func (mc *MultiClient) checkQuorum(heads []*types.ChainHead) {
+	for i := 0; i < len(heads); i++ {
+		for j := i + 1; j < len(heads); j++ {
+			a, b := heads[i], heads[j]
+			if a == nil || b == nil {
+				continue
+			}
+			if a.FinalizedEpoch == b.FinalizedEpoch && !bytes.Equal(a.FinalizedBlockRoot, b.FinalizedBlockRoot) {
+				logger.Errorf("beacon-nodes %v and %v diverge on finalized checkpoint of epoch %v: %#x != %#x", mc.nodes[i].name, mc.nodes[j].name, a.FinalizedEpoch, a.FinalizedBlockRoot, b.FinalizedBlockRoot)
+				metrics.Errors.WithLabelValues("rpc_node_finalized_divergence").Inc()
+			}
+			if a.JustifiedEpoch == b.JustifiedEpoch && !bytes.Equal(a.JustifiedBlockRoot, b.JustifiedBlockRoot) {
+				logger.Errorf("beacon-nodes %v and %v diverge on justified checkpoint of epoch %v: %#x != %#x", mc.nodes[i].name, mc.nodes[j].name, a.JustifiedEpoch, a.JustifiedBlockRoot, b.JustifiedBlockRoot)
+				metrics.Errors.WithLabelValues("rpc_node_justified_divergence").Inc()
+			}
+			if a.HeadSlot == b.HeadSlot && !bytes.Equal(a.HeadBlockRoot, b.HeadBlockRoot) {
+				logger.Warnf("beacon-nodes %v and %v diverge on head of slot %v: %#x != %#x", mc.nodes[i].name, mc.nodes[j].name, a.HeadSlot, a.HeadBlockRoot, b.HeadBlockRoot)
+				metrics.Errors.WithLabelValues("rpc_node_head_divergence").Inc()
+			}
+		}
+	}
+}
+
+// candidates will return the nodes ordered by preference: healthy before unhealthy, synced before syncing, then by lowest latency
+func (mc *MultiClient) candidates() []*multiClientNode {
+	mc.nodesMux.RLock()
+	defer mc.nodesMux.RUnlock()
+
+	nodes := make([]*multiClientNode, len(mc.nodes))
+	copy(nodes, mc.nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].healthy != nodes[j].healthy {
+			return nodes[i].healthy
+		}
+		if nodes[i].synced != nodes[j].synced {
+			return nodes[i].synced
+		}
+		return nodes[i].latency < nodes[j].latency
+	})
+	return nodes
+}
+
+// isNodeFailure will return whether err was caused by the beacon-node (transport errors, timeouts, 5xx responses and
+// unavailable grpc endpoints) rather than by the request, only those are worth retrying on another node
+func isNodeFailure(err error) bool {
+	var ne *nodeErr
+	if errors.As(err, &ne) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted, codes.Aborted:
+			return true
+		}
+	}
+	return false
+}
+
+// do will run f against the preferred node and fail over to the next node if the node failed.
+// Other errors, e.g. a not found or bad request response, are returned right away and do not mark the node unhealthy.
+func (mc *MultiClient) do(method string, f func(client Client) error) error {
+	var err error
+	for _, node := range mc.candidates() {
+		err = f(node.client)
+		if err == nil {
+			return nil
+		}
+		if !isNodeFailure(err) {
+			return err
+		}
+
+		logger.Warnf("error calling %v on beacon-node %v, failing over: %v", method, node.name, err)
+		mc.nodesMux.Lock()
+		node.healthy = false
+		mc.nodesMux.Unlock()
+		metrics.RPCNodeHealthy.WithLabelValues(node.name).Set(0)
+	}
+	return fmt.Errorf("error calling %v on all beacon-nodes: %w", method, err)
+}
+
+// GetChainHead gets the chain head from the preferred node
+func (mc *MultiClient) GetChainHead() (*types.ChainHead, error) {
+	var res *types.ChainHead
+	err := mc.do("GetChainHead", func(client Client) (err error) {
+		res, err = client.GetChainHead()
+		return err
+	})
+	return res, err
+}
+
+// GetEpochData gets the epoch data from the preferred node
+func (mc *MultiClient) GetEpochData(epoch uint64) (*types.EpochData, error) {
+	var res *types.EpochData
+	err := mc.do("GetEpochData", func(client Client) (err error) {
+		res, err = client.GetEpochData(epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetSlotData gets the slot data from the preferred node
+func (mc *MultiClient) GetSlotData(block *types.Block) (*types.SlotData, error) {
+	var res *types.SlotData
+	err := mc.do("GetSlotData", func(client Client) (err error) {
+		res, err = client.GetSlotData(block)
+		return err
+	})
+	return res, err
+}
+
+// GetValidatorQueue gets the validator queue from the preferred node
+func (mc *MultiClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
+	var res *types.ValidatorQueue
+	err := mc.do("GetValidatorQueue", func(client Client) (err error) {
+		res, err = client.GetValidatorQueue()
+		return err
+	})
+	return res, err
+}
+
+// GetEpochAssignments gets the epoch assignments from the preferred node
+func (mc *MultiClient) GetEpochAssignments(epoch uint64) (*types.EpochAssignments, error) {
+	var res *types.EpochAssignments
+	err := mc.do("GetEpochAssignments", func(client Client) (err error) {
+		res, err = client.GetEpochAssignments(epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetBlocksBySlot gets the blocks of a slot from the preferred node
+func (mc *MultiClient) GetBlocksBySlot(slot uint64) ([]*types.Block, error) {
+	var res []*types.Block
+	err := mc.do("GetBlocksBySlot", func(client Client) (err error) {
+		res, err = client.GetBlocksBySlot(slot)
+		return err
+	})
+	return res, err
+}
+
+// GetValidatorParticipation gets the validator participation from the preferred node
+func (mc *MultiClient) GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	var res *types.ValidatorParticipation
+	err := mc.do("GetValidatorParticipation", func(client Client) (err error) {
+		res, err = client.GetValidatorParticipation(epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetBlockStatusByEpoch gets the canonical status of the blocks of an epoch from the preferred node
+func (mc *MultiClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonBlock, error) {
+	var res []*types.CanonBlock
+	err := mc.do("GetBlockStatusByEpoch", func(client Client) (err error) {
+		res, err = client.GetBlockStatusByEpoch(epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetFinalityCheckpoints gets the finality checkpoints from the preferred node
+func (mc *MultiClient) GetFinalityCheckpoints(epoch uint64) (*types.FinalityCheckpoints, error) {
+	var res *types.FinalityCheckpoints
+	err := mc.do("GetFinalityCheckpoints", func(client Client) (err error) {
+		res, err = client.GetFinalityCheckpoints(epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetSyncCommittee gets the sync committee from the preferred node
+func (mc *MultiClient) GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error) {
+	var res *StandardSyncCommittee
+	err := mc.do("GetSyncCommittee", func(client Client) (err error) {
+		res, err = client.GetSyncCommittee(stateID, epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetBlobSidecars gets the blob sidecars of a block from the preferred node that provides blob sidecars
+func (mc *MultiClient) GetBlobSidecars(block *types.Block) ([]*types.BlobSidecar, error) {
+	var err error
+	for _, node := range mc.candidates() {
+		provider, ok := node.client.(BlobSidecarProvider)
+		if !ok {
+			continue
+		}
+		var res []*types.BlobSidecar
+		res, err = provider.GetBlobSidecars(block)
+		if err == nil || errors.Is(err, notFoundErr) {
+			return res, err
+		}
+		logger.Warnf("error calling GetBlobSidecars on beacon-node %v, failing over: %v", node.name, err)
+	}
+	if err == nil {
+		return nil, fmt.Errorf("no beacon-node provides blob sidecars")
+	}
+	return nil, fmt.Errorf("error calling GetBlobSidecars on all beacon-nodes: %w", err)
+}
+
+// GetEpochRewards gets the rewards of an epoch from the preferred node that provides rewards
+func (mc *MultiClient) GetEpochRewards(epoch uint64) (map[uint64]*types.ValidatorRewards, error) {
+	var err error
+	for _, node := range mc.candidates() {
+		provider, ok := node.client.(RewardsProvider)
+		if !ok {
+			continue
+		}
+		var res map[uint64]*types.ValidatorRewards
+		res, err = provider.GetEpochRewards(epoch)
+		if err == nil {
+			return res, nil
+		}
+		// nodes that pruned the state of the epoch can not provide its rewards, this is not a node failure
+		logger.Warnf("error calling GetEpochRewards on beacon-node %v, failing over: %v", node.name, err)
+	}
+	if err == nil {
+		return nil, fmt.Errorf("no beacon-node provides rewards")
+	}
+	return nil, fmt.Errorf("error calling GetEpochRewards on all beacon-nodes: %w", err)
+}
+
+// GetBLSChangePool gets the bls to execution change pool from the preferred node that provides it
+func (mc *MultiClient) GetBLSChangePool() ([]*types.SignedBLSToExecutionChange, error) {
+	var err error
+	for _, node := range mc.candidates() {
+		provider, ok := node.client.(BLSChangePoolProvider)
+		if !ok {
+			continue
+		}
+		var res []*types.SignedBLSToExecutionChange
+		res, err = provider.GetBLSChangePool()
+		if err == nil {
+			return res, nil
+		}
+		logger.Warnf("error calling GetBLSChangePool on beacon-node %v, failing over: %v", node.name, err)
+	}
+	if err == nil {
+		return nil, fmt.Errorf("no beacon-node provides the bls to execution change pool")
+	}
+	return nil, fmt.Errorf("error calling GetBLSChangePool on all beacon-nodes: %w", err)
+}
+
+// GetGenesisValidatorsRoot gets the genesis validators root from the preferred node that provides it
+func (mc *MultiClient) GetGenesisValidatorsRoot() ([]byte, error) {
+	var err error
+	for _, node := range mc.candidates() {
+		provider, ok := node.client.(BLSChangePoolProvider)
+		if !ok {
+			continue
+		}
+		var res []byte
+		res, err = provider.GetGenesisValidatorsRoot()
+		if err == nil {
+			return res, nil
+		}
+		logger.Warnf("error calling GetGenesisValidatorsRoot on beacon-node %v, failing over: %v", node.name, err)
+	}
+	if err == nil {
+		return nil, fmt.Errorf("no beacon-node provides the genesis validators root")
+	}
+	return nil, fmt.Errorf("error calling GetGenesisValidatorsRoot on all beacon-nodes: %w", err)
+}
+
+// GetNewBlockChan merges the new blocks of all nodes into a single channel, every block root is only pushed once
+func (mc *MultiClient) GetNewBlockChan() chan *types.Block {
+	blkCh := make(chan *types.Block, 10)
+	seen, _ := lru.New(1000)
+	seenMux := &sync.Mutex{}
+
+	for _, node := range mc.nodes {
+		go func(node *multiClientNode) {
+			for block := range node.client.GetNewBlockChan() {
+				seenMux.Lock()
+				known, _ := seen.ContainsOrAdd(string(block.BlockRoot), true)
+				seenMux.Unlock()
+				if !known {
+					blkCh <- block
+				}
+			}
+		}(node)
+	}
+	return blkCh
+}
+
+// GetChainEventChan merges the chain events of all nodes that support them into a single channel, duplicate events are dropped
+func (mc *MultiClient) GetChainEventChan() chan *types.ChainEvent {
+	seen, _ := lru.New(100)
+	seenMux := &sync.Mutex{}
+
+	for _, node := range mc.nodes {
+		subscriber, ok := node.client.(ChainEventSubscriber)
+		if !ok {
+			continue
+		}
+		go func(ch chan *types.ChainEvent) {
+			for event := range ch {
+				key := ""
+				if event.Reorg != nil {
+					key = fmt.Sprintf("reorg:%x", event.Reorg.NewHeadBlock)
+				} else if event.Finalized != nil {
+					key = fmt.Sprintf("finalized:%x", event.Finalized.Block)
+				}
+				seenMux.Lock()
+				known, _ := seen.ContainsOrAdd(key, true)
+				seenMux.Unlock()
+				if !known {
+					mc.chainEventCh <- event
+				}
+			}
+		}(subscriber.GetChainEventChan())
+	}
+	return mc.chainEventCh
+}
+
+// GetProposerDuties gets the proposer duties of an epoch from the preferred node that provides them
+func (mc *MultiClient) GetProposerDuties(epoch uint64) ([]*types.ProposerDuty, error) {
+	var err error
+	for _, node := range mc.candidates() {
+		provider, ok := node.client.(ProposerDutiesProvider)
+		if !ok {
+			continue
+		}
+		var res []*types.ProposerDuty
+		res, err = provider.GetProposerDuties(epoch)
+		if err == nil {
+			return res, nil
+		}
+		logger.Warnf("error calling GetProposerDuties on beacon-node %v, failing over: %v", node.name, err)
+	}
+	if err == nil {
+		return nil, fmt.Errorf("no beacon-node provides proposer duties")
+	}
+	return nil, fmt.Errorf("error calling GetProposerDuties on all beacon-nodes: %w", err)
+}
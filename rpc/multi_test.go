@@ -0,0 +1,229 @@
+package rpc
+
+import (
+	"errors"
+	"eth2-exporter/metrics"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newTestMultiClient(t *testing.T, names []string, standIns []*beaconStandIn, quorum bool) *MultiClient {
+	t.Helper()
+	clients := make([]Client, 0, len(standIns))
+	for _, standIn := range standIns {
+		srv := standIn.start(t)
+		clients = append(clients, newStandardClient(srv.URL, big.NewInt(1)))
+	}
+	mc, err := NewMultiClient(clients, names, quorum, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mc
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	tests := []struct {
+		name string
+		// the first node is the fastest and preferred as long as it is healthy and synced
+		headSlots []uint64
+		failing   []bool
+		// failStatus is the status failing nodes answer with, internal errors if it is not set
+		failStatus int
+		// call is run after the health-check of the nodes
+		call            func(mc *MultiClient) error
+		wantErr         string
+		wantNotFound    bool
+		wantServedBy    int
+		wantUnhealthy   []bool
+		servedByRequest string
+	}{
+		{
+			name:            "preferred node",
+			headSlots:       []uint64{15, 15},
+			failing:         []bool{false, false},
+			call:            func(mc *MultiClient) error { _, err := mc.GetBlockStatusByEpoch(3); return err },
+			wantServedBy:    0,
+			wantUnhealthy:   []bool{false, false},
+			servedByRequest: "/eth/v1/beacon/headers?slot=12",
+		},
+		{
+			name:            "fail over to the next node",
+			headSlots:       []uint64{15, 15},
+			failing:         []bool{true, false},
+			call:            func(mc *MultiClient) error { _, err := mc.GetBlockStatusByEpoch(3); return err },
+			wantServedBy:    1,
+			wantUnhealthy:   []bool{true, false},
+			servedByRequest: "/eth/v1/beacon/headers?slot=12",
+		},
+		{
+			name:            "lagging node is not preferred",
+			headSlots:       []uint64{15, 25},
+			failing:         []bool{false, false},
+			call:            func(mc *MultiClient) error { _, err := mc.GetBlockStatusByEpoch(3); return err },
+			wantServedBy:    1,
+			wantUnhealthy:   []bool{false, false},
+			servedByRequest: "/eth/v1/beacon/headers?slot=12",
+		},
+		{
+			name:          "all nodes failing",
+			headSlots:     []uint64{15, 15},
+			failing:       []bool{true, true},
+			call:          func(mc *MultiClient) error { _, err := mc.GetBlockStatusByEpoch(3); return err },
+			wantErr:       "error calling GetBlockStatusByEpoch on all beacon-nodes",
+			wantUnhealthy: []bool{true, true},
+		},
+		{
+			name:          "bad request is not failed over",
+			headSlots:     []uint64{15, 15},
+			failing:       []bool{true, false},
+			failStatus:    http.StatusBadRequest,
+			call:          func(mc *MultiClient) error { _, err := mc.GetBlockStatusByEpoch(3); return err },
+			wantErr:       "error-response: Bad Request",
+			wantUnhealthy: []bool{false, false},
+		},
+		{
+			name:            "not found is not failed over",
+			headSlots:       []uint64{15, 15},
+			failing:         []bool{false, false},
+			call:            func(mc *MultiClient) error { _, err := mc.GetSyncCommittee("head", 3); return err },
+			wantNotFound:    true,
+			wantServedBy:    0,
+			wantUnhealthy:   []bool{false, false},
+			servedByRequest: "/eth/v1/beacon/states/head/sync_committees?epoch=3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{"failover-a-" + tt.name, "failover-b-" + tt.name}
+			standIns := []*beaconStandIn{newBeaconStandIn(tt.headSlots[0]), newBeaconStandIn(tt.headSlots[1])}
+			standIns[1].delay = time.Millisecond * 50
+			for _, standIn := range standIns {
+				standIn.failStatus = tt.failStatus
+			}
+			mc := newTestMultiClient(t, names, standIns, false)
+
+			for i, standIn := range standIns {
+				standIn.setFailing(tt.failing[i])
+			}
+
+			err := tt.call(mc)
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			case tt.wantNotFound:
+				if !errors.Is(err, notFoundErr) {
+					t.Fatalf("expected a not found error, got %v", err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if tt.servedByRequest != "" {
+				for i, standIn := range standIns {
+					served := standIn.requestCount(tt.servedByRequest) > 0
+					if served != (i == tt.wantServedBy) {
+						t.Errorf("expected the request to be served by node %v, node %v served it: %v", tt.wantServedBy, i, served)
+					}
+				}
+			}
+
+			for i, node := range mc.nodes {
+				mc.nodesMux.RLock()
+				healthy := node.healthy
+				mc.nodesMux.RUnlock()
+				if healthy == tt.wantUnhealthy[i] {
+					t.Errorf("expected node %v to be healthy: %v", i, !tt.wantUnhealthy[i])
+				}
+				if got := testutil.ToFloat64(metrics.RPCNodeHealthy.WithLabelValues(names[i])); (got == 0) != tt.wantUnhealthy[i] {
+					t.Errorf("unexpected health metric %v of node %v", got, i)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiClientHealthCheck(t *testing.T) {
+	standIns := []*beaconStandIn{newBeaconStandIn(15), newBeaconStandIn(25), newBeaconStandIn(15)}
+	standIns[2].failing = true
+	mc := newTestMultiClient(t, []string{"check-a", "check-b", "check-c"}, standIns, false)
+
+	want := []struct{ healthy, synced bool }{{true, false}, {true, true}, {false, false}}
+	for i, node := range mc.nodes {
+		if node.healthy != want[i].healthy || node.synced != want[i].synced {
+			t.Errorf("expected node %v to be healthy: %v and synced: %v, got %v and %v", i, want[i].healthy, want[i].synced, node.healthy, node.synced)
+		}
+	}
+
+	candidates := mc.candidates()
+	if candidates[0].name != "check-b" || candidates[2].name != "check-c" {
+		t.Errorf("unexpected order of candidates %v, %v, %v", candidates[0].name, candidates[1].name, candidates[2].name)
+	}
+}
+
+func TestMultiClientQuorum(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *beaconStandIn)
+		want   map[string]float64
+	}{
+		{
+			name:   "agreeing nodes",
+			modify: func(s *beaconStandIn) {},
+			want:   map[string]float64{},
+		},
+		{
+			name:   "diverging finalized checkpoint",
+			modify: func(s *beaconStandIn) { s.finalizedRoot = testRoot(7) },
+			want:   map[string]float64{"rpc_node_finalized_divergence": 1},
+		},
+		{
+			name:   "diverging justified checkpoint",
+			modify: func(s *beaconStandIn) { s.justifiedRoot = testRoot(11) },
+			want:   map[string]float64{"rpc_node_justified_divergence": 1},
+		},
+		{
+			name: "diverging head",
+			modify: func(s *beaconStandIn) {
+				s.addBlock(15, 215, 14)
+				s.head = testRoot(215)
+			},
+			want: map[string]float64{"rpc_node_head_divergence": 1},
+		},
+		{
+			name: "checkpoints of different epochs",
+			modify: func(s *beaconStandIn) {
+				s.finalizedEpoch = 3
+				s.finalizedRoot = testRoot(12)
+			},
+			want: map[string]float64{},
+		},
+	}
+
+	labels := []string{"rpc_node_finalized_divergence", "rpc_node_justified_divergence", "rpc_node_head_divergence"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := map[string]float64{}
+			for _, label := range labels {
+				before[label] = testutil.ToFloat64(metrics.Errors.WithLabelValues(label))
+			}
+
+			standIns := []*beaconStandIn{newBeaconStandIn(15), newBeaconStandIn(15)}
+			tt.modify(standIns[1])
+			newTestMultiClient(t, []string{"quorum-a", "quorum-b"}, standIns, true)
+
+			for _, label := range labels {
+				if got := testutil.ToFloat64(metrics.Errors.WithLabelValues(label)) - before[label]; got != tt.want[label] {
+					t.Errorf("expected %v %v errors, got %v", tt.want[label], label, got)
+				}
+			}
+		})
+	}
+}
@@ -162,7 +162,7 @@ func (pc *PrysmClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
 	validators, err := pc.client.GetValidatorQueue(context.Background(), &empty.Empty{})
 
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving validator queue data: %v", err)
+		return nil, fmt.Errorf("error retrieving validator queue data: %w", err)
 	}
 
 	return &types.ValidatorQueue{
@@ -202,7 +202,7 @@ func (pc *PrysmClient) GetEpochAssignments(epoch uint64) (*types.EpochAssignment
 		validatorAssignmentRequest.PageToken = validatorAssignmentResponse.NextPageToken
 		validatorAssignmentResponse, err = pc.client.ListValidatorAssignments(context.Background(), validatorAssignmentRequest)
 		if err != nil {
-			return nil, fmt.Errorf("error retrieving validator assignment response for caching: %v", err)
+			return nil, fmt.Errorf("error retrieving validator assignment response for caching: %w", err)
 		}
 
 		validatorAssignmentes = append(validatorAssignmentes, validatorAssignmentResponse.Assignments...)
@@ -254,12 +254,12 @@ func (pc *PrysmClient) GetEpochData(epoch uint64) (*types.EpochData, error) {
 
 	validatorsResp, err := pc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", pc.endpoint, lastSlot))
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving validators for slot %v: %v", lastSlot, err)
+		return nil, fmt.Errorf("error retrieving validators for slot %v: %w", lastSlot, err)
 	}
 	var parsedValidators StandardValidatorsResponse
 	err = json.Unmarshal(validatorsResp, &parsedValidators)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing epoch validators: %v", err)
+		return nil, fmt.Errorf("error parsing epoch validators: %w", err)
 	}
 
 	slot1d := int64(lastSlot) - 7200
@@ -353,7 +353,7 @@ func (pc *PrysmClient) GetEpochData(epoch uint64) (*types.EpochData, error) {
 
 	data.ValidatorAssignmentes, err = pc.GetEpochAssignments(epoch)
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving assignments for epoch %v: %v", epoch, err)
+		return nil, fmt.Errorf("error retrieving assignments for epoch %v: %w", epoch, err)
 	}
 	logger.Printf("retrieved validator assignment data for epoch %v", epoch)
 
@@ -467,7 +467,7 @@ func (pc *PrysmClient) GetEpochData(epoch uint64) (*types.EpochData, error) {
 
 	data.EpochParticipationStats, err = pc.GetValidatorParticipation(epoch)
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving epoch participation statistics for epoch %v: %v", epoch, err)
+		return nil, fmt.Errorf("error retrieving epoch participation statistics for epoch %v: %w", epoch, err)
 	}
 
 	return data, nil
@@ -574,12 +574,9 @@ func (pc *PrysmClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonBlock,
 	}
 
 	for _, block := range blocksResponse.BlockContainers {
-		var slot eth2types.Slot = 0
-
-		if altairBlock := block.GetAltairBlock(); altairBlock != nil {
-			slot = altairBlock.Block.GetSlot()
-		} else {
-			slot = block.GetPhase0Block().GetBlock().GetSlot()
+		_, slot, err := prysmBlockFork(block)
+		if err != nil {
+			return nil, err
 		}
 
 		blocks = append(blocks, &types.CanonBlock{
@@ -592,24 +589,44 @@ func (pc *PrysmClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonBlock,
 	return blocks, nil
 }
 
-func (pc *PrysmClient) parseRpcBlock(block *ethpb.BeaconBlockContainer) (*types.Block, error) {
-	phase0Block := block.GetPhase0Block()
-	if phase0Block != nil {
-		return pc.parsePhase0Block(block)
+// prysmBlockParsers maps the name of a fork to the parser of the block containers of that fork
+var prysmBlockParsers = map[string]func(pc *PrysmClient, block *ethpb.BeaconBlockContainer) (*types.Block, error){
+	"phase0":    (*PrysmClient).parsePhase0Block,
+	"altair":    (*PrysmClient).parseAltairBlock,
+	"bellatrix": (*PrysmClient).parseBellatrixBlock,
+	"capella":   (*PrysmClient).parseCapellaBlock,
+}
+
+// prysmBlockFork will return the name of the fork and the slot of a block container
+func prysmBlockFork(block *ethpb.BeaconBlockContainer) (string, eth2types.Slot, error) {
+	if blk := block.GetPhase0Block(); blk != nil {
+		return "phase0", blk.GetBlock().GetSlot(), nil
+	}
+	if blk := block.GetAltairBlock(); blk != nil {
+		return "altair", blk.GetBlock().GetSlot(), nil
 	}
-	altairBlock := block.GetAltairBlock()
-	if altairBlock != nil {
-		return pc.parseAltairBlock(block)
+	if blk := block.GetBellatrixBlock(); blk != nil {
+		return "bellatrix", blk.GetBlock().GetSlot(), nil
+	}
+	if blk := block.GetCapellaBlock(); blk != nil {
+		return "capella", blk.GetBlock().GetSlot(), nil
+	}
+	return "", 0, fmt.Errorf("block container of unknown fork")
+}
+
+func (pc *PrysmClient) parseRpcBlock(block *ethpb.BeaconBlockContainer) (*types.Block, error) {
+	name, slot, err := prysmBlockFork(block)
+	if err != nil {
+		return nil, err
 	}
-	bellatrixBlock := block.GetBellatrixBlock()
-	if bellatrixBlock != nil {
-		return pc.parseBellatrixBlock(block)
+	if fork := utils.ForkAtSlot(uint64(slot)); fork.Name != name {
+		logger.Warnf("block at slot %v is a %v block but the fork schedule expects %v, check the fork epochs of the chain config", slot, name, fork.Name)
 	}
-	capellaBlock := block.GetCapellaBlock()
-	if capellaBlock != nil {
-		return pc.parseCapellaBlock(block)
+	parse, found := prysmBlockParsers[name]
+	if !found {
+		return nil, fmt.Errorf("no parser registered for %v blocks", name)
 	}
-	return nil, fmt.Errorf("block is neither phase0 nor altair nor bellatrix nor capella")
+	return parse(pc, block)
 }
 
 func (pc *PrysmClient) parsePhase0Block(block *ethpb.BeaconBlockContainer) (*types.Block, error) {
@@ -721,7 +738,7 @@ func (pc *PrysmClient) parsePhase0Block(block *ethpb.BeaconBlockContainer) (*typ
 		aggregationBits := bitfield.Bitlist(a.AggregationBits)
 		assignments, err := pc.GetEpochAssignments(a.Data.Slot / utils.Config.Chain.Config.SlotsPerEpoch)
 		if err != nil {
-			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %v", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
+			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %w", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
 		}
 
 		a.Attesters = make([]uint64, 0)
@@ -876,7 +893,7 @@ func (pc *PrysmClient) parseAltairBlock(block *ethpb.BeaconBlockContainer) (*typ
 		aggregationBits := bitfield.Bitlist(a.AggregationBits)
 		assignments, err := pc.GetEpochAssignments(a.Data.Slot / utils.Config.Chain.Config.SlotsPerEpoch)
 		if err != nil {
-			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %v", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
+			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %w", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
 		}
 
 		a.Attesters = make([]uint64, 0)
@@ -947,7 +964,7 @@ func (pc *PrysmClient) parseBellatrixBlock(block *ethpb.BeaconBlockContainer) (*
 			tx := &types.Transaction{Raw: rawTx}
 			var decTx gtypes.Transaction
 			if err := decTx.UnmarshalBinary(rawTx); err != nil {
-				return nil, fmt.Errorf("error parsing tx %d block %x: %v", i, payload.BlockHash, err)
+				return nil, fmt.Errorf("error parsing tx %d block %x: %w", i, payload.BlockHash, err)
 			} else {
 				h := decTx.Hash()
 				tx.TxHash = h[:]
@@ -957,7 +974,7 @@ func (pc *PrysmClient) parseBellatrixBlock(block *ethpb.BeaconBlockContainer) (*
 				tx.GasLimit = decTx.Gas()
 				sender, err := pc.signer.Sender(&decTx)
 				if err != nil {
-					return nil, fmt.Errorf("transaction with invalid sender (tx hash: %x): %v", h, err)
+					return nil, fmt.Errorf("transaction with invalid sender (tx hash: %x): %w", h, err)
 				}
 				tx.Sender = sender.Bytes()
 				if v := decTx.To(); v != nil {
@@ -1082,7 +1099,7 @@ func (pc *PrysmClient) parseBellatrixBlock(block *ethpb.BeaconBlockContainer) (*
 		aggregationBits := bitfield.Bitlist(a.AggregationBits)
 		assignments, err := pc.GetEpochAssignments(a.Data.Slot / utils.Config.Chain.Config.SlotsPerEpoch)
 		if err != nil {
-			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %v", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
+			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %w", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
 		}
 
 		a.Attesters = make([]uint64, 0)
@@ -1158,7 +1175,7 @@ func (pc *PrysmClient) parseCapellaBlock(block *ethpb.BeaconBlockContainer) (*ty
 			tx := &types.Transaction{Raw: rawTx}
 			var decTx gtypes.Transaction
 			if err := decTx.UnmarshalBinary(rawTx); err != nil {
-				return nil, fmt.Errorf("error parsing tx %d block %x: %v", i, payload.BlockHash, err)
+				return nil, fmt.Errorf("error parsing tx %d block %x: %w", i, payload.BlockHash, err)
 			} else {
 				h := decTx.Hash()
 				tx.TxHash = h[:]
@@ -1168,7 +1185,7 @@ func (pc *PrysmClient) parseCapellaBlock(block *ethpb.BeaconBlockContainer) (*ty
 				tx.GasLimit = decTx.Gas()
 				sender, err := pc.signer.Sender(&decTx)
 				if err != nil {
-					return nil, fmt.Errorf("transaction with invalid sender (tx hash: %x): %v", h, err)
+					return nil, fmt.Errorf("transaction with invalid sender (tx hash: %x): %w", h, err)
 				}
 				tx.Sender = sender.Bytes()
 				if v := decTx.To(); v != nil {
@@ -1309,7 +1326,7 @@ func (pc *PrysmClient) parseCapellaBlock(block *ethpb.BeaconBlockContainer) (*ty
 		aggregationBits := bitfield.Bitlist(a.AggregationBits)
 		assignments, err := pc.GetEpochAssignments(a.Data.Slot / utils.Config.Chain.Config.SlotsPerEpoch)
 		if err != nil {
-			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %v", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
+			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %w", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
 		}
 
 		a.Attesters = make([]uint64, 0)
@@ -1389,7 +1406,7 @@ func (pc *PrysmClient) GetValidatorParticipation(epoch uint64) (*types.Validator
 func (pc *PrysmClient) GetFinalityCheckpoints(epoch uint64) (*types.FinalityCheckpoints, error) {
 	// finalityResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%s/finality_checkpoints", lc.endpoint, id))
 	// if err != nil {
-	// 	return nil, fmt.Errorf("error retrieving finality checkpoints of head: %v", err)
+	// 	return nil, fmt.Errorf("error retrieving finality checkpoints of head: %w", err)
 	// }
 	return nil, fmt.Errorf("not implemented yet")
 }
@@ -1424,12 +1441,12 @@ func (pc *PrysmClient) GetSlotData(block *types.Block) (*types.SlotData, error)
 
 	validatorsResp, err := pc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", pc.endpoint, slot))
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving validators for slot %v: %v", slot, err)
+		return nil, fmt.Errorf("error retrieving validators for slot %v: %w", slot, err)
 	}
 	var parsedValidators StandardValidatorsResponse
 	err = json.Unmarshal(validatorsResp, &parsedValidators)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing epoch validators: %v", err)
+		return nil, fmt.Errorf("error parsing epoch validators: %w", err)
 	}
 
 	slot1d := int64(slot) - 7200
@@ -2,6 +2,10 @@ package rpc
 
 import (
 	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -21,4 +25,80 @@ type Client interface {
 	GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error)
 }
 
+// ChainEventSubscriber is implemented by clients that can deliver chain_reorg and finalized_checkpoint events
+type ChainEventSubscriber interface {
+	GetChainEventChan() chan *types.ChainEvent
+}
+
+// BlobSidecarProvider is implemented by clients that can retrieve the blob sidecars of deneb blocks
+type BlobSidecarProvider interface {
+	GetBlobSidecars(block *types.Block) ([]*types.BlobSidecar, error)
+}
+
+// RewardsProvider is implemented by clients that can retrieve the consensus layer rewards of validators
+type RewardsProvider interface {
+	GetEpochRewards(epoch uint64) (map[uint64]*types.ValidatorRewards, error)
+}
+
+// BLSChangePoolProvider is implemented by clients that can retrieve the bls to execution changes waiting in the operation pool,
+// the genesis validators root is needed to verify the signatures of the changes
+type BLSChangePoolProvider interface {
+	GetBLSChangePool() ([]*types.SignedBLSToExecutionChange, error)
+	GetGenesisValidatorsRoot() ([]byte, error)
+}
+
+// ProposerDutiesProvider is implemented by clients that can retrieve the proposer duties of the current and the next epoch
+// without computing the full epoch assignments
+type ProposerDutiesProvider interface {
+	GetProposerDuties(epoch uint64) ([]*types.ProposerDuty, error)
+}
+
 var logger = logrus.New().WithField("module", "rpc")
+
+// NewIndexerClient will create the client for the nodes configured in the indexer config.
+// If more than one node is configured, the clients are combined into a MultiClient.
+func NewIndexerClient() (Client, error) {
+	chainID := new(big.Int).SetUint64(utils.Config.Chain.Config.DepositChainID)
+	if len(utils.Config.Indexer.Nodes) == 0 {
+		return NewNodeClient(utils.Config.Indexer.Node, chainID)
+	}
+
+	nodes := utils.Config.Indexer.Nodes
+	if utils.Config.Indexer.Node.Host != "" {
+		nodes = append([]types.IndexerNode{utils.Config.Indexer.Node}, nodes...)
+	}
+
+	clients := make([]Client, 0, len(nodes))
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		client, err := NewNodeClient(node, chainID)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+		names = append(names, node.Host+":"+node.Port)
+	}
+
+	if utils.Config.Indexer.NodeCheckInterval == 0 {
+		utils.Config.Indexer.NodeCheckInterval = time.Second * 12
+	}
+	return NewMultiClient(clients, names, utils.Config.Indexer.NodeQuorum, utils.Config.Indexer.NodeCheckInterval)
+}
+
+// NewNodeClient will create the client matching the type of the node
+func NewNodeClient(node types.IndexerNode, chainID *big.Int) (Client, error) {
+	switch node.Type {
+	case "prysm":
+		if utils.Config.Indexer.Node.PageSize == 0 {
+			logger.Printf("setting default rpc page size to 500")
+			utils.Config.Indexer.Node.PageSize = 500
+		}
+		return NewPrysmClient(node.Host+":"+node.GRPCPort, "http://"+node.Host+":"+node.Port, chainID)
+	case "lighthouse":
+		return NewLighthouseClient("http://"+node.Host+":"+node.Port, chainID)
+	case "standard":
+		return NewStandardClient("http://"+node.Host+":"+node.Port, chainID)
+	default:
+		return nil, fmt.Errorf("invalid note type %v specified. supported node types are prysm, lighthouse and standard", node.Type)
+	}
+}
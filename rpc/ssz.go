@@ -0,0 +1,475 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"eth2-exporter/utils"
+)
+
+// Minimal SSZ decoding of signed beacon blocks as returned by /eth/v2/beacon/blocks/{block_id}
+// with "Accept: application/octet-stream", see https://github.com/ethereum/consensus-specs/blob/dev/ssz/simple-serialize.md
+// The decoded block is returned in the same shape as the JSON response so both share the same parsing path.
+
+const (
+	sszOffsetSize           = 4
+	sszBeaconBlockHeaderLen = 8 + 8 + 32 + 32 + 32
+	sszSignedHeaderLen      = sszBeaconBlockHeaderLen + 96
+	sszProposerSlashingLen  = 2 * sszSignedHeaderLen
+	sszAttestationDataLen   = 8 + 8 + 32 + (8 + 32) + (8 + 32)
+	sszDepositLen           = 33*32 + 48 + 32 + 8 + 96
+	sszVoluntaryExitLen     = 8 + 8 + 96
+	sszBLSChangeLen         = 8 + 48 + 20 + 96
+	sszWithdrawalLen        = 8 + 8 + 20 + 8
+	sszKZGCommitmentLen     = 48
+)
+
+func hex0x(b []byte) string {
+	return fmt.Sprintf("0x%x", b)
+}
+
+func sszUint64(b []byte) uint64Str {
+	return uint64Str(binary.LittleEndian.Uint64(b))
+}
+
+func sszOffset(b []byte, pos int) (int, error) {
+	if len(b) < pos+sszOffsetSize {
+		return 0, fmt.Errorf("ssz: offset at %d out of range", pos)
+	}
+	return int(binary.LittleEndian.Uint32(b[pos : pos+sszOffsetSize])), nil
+}
+
+// sszVariableParts splits the variable part of a container into the fields referenced by the given offsets
+func sszVariableParts(b []byte, offsets []int) ([][]byte, error) {
+	parts := make([][]byte, len(offsets))
+	for i, start := range offsets {
+		end := len(b)
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		if start > end || end > len(b) {
+			return nil, fmt.Errorf("ssz: invalid offsets %d-%d (length %d)", start, end, len(b))
+		}
+		parts[i] = b[start:end]
+	}
+	return parts, nil
+}
+
+// sszFixedList splits a list of fixed size elements
+func sszFixedList(b []byte, size int) ([][]byte, error) {
+	if len(b)%size != 0 {
+		return nil, fmt.Errorf("ssz: list length %d is not a multiple of element size %d", len(b), size)
+	}
+	items := make([][]byte, 0, len(b)/size)
+	for i := 0; i < len(b); i += size {
+		items = append(items, b[i:i+size])
+	}
+	return items, nil
+}
+
+// sszVariableList splits a list of variable size elements
+func sszVariableList(b []byte) ([][]byte, error) {
+	if len(b) == 0 {
+		return [][]byte{}, nil
+	}
+	first, err := sszOffset(b, 0)
+	if err != nil {
+		return nil, err
+	}
+	if first%sszOffsetSize != 0 || first > len(b) || first == 0 {
+		return nil, fmt.Errorf("ssz: invalid first offset %d", first)
+	}
+	offsets := make([]int, first/sszOffsetSize)
+	for i := range offsets {
+		offsets[i], err = sszOffset(b, i*sszOffsetSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sszVariableParts(b, offsets)
+}
+
+func decodeSSZSignedBlock(version string, b []byte) (*AnySignedBlock, error) {
+	fork, err := utils.ForkByName(version)
+	if err != nil {
+		return nil, fmt.Errorf("ssz: unsupported block version: %v", err)
+	}
+
+	if len(b) < sszOffsetSize+96 {
+		return nil, fmt.Errorf("ssz: signed block too short (%d bytes)", len(b))
+	}
+	messageOffset, err := sszOffset(b, 0)
+	if err != nil {
+		return nil, err
+	}
+	if messageOffset != sszOffsetSize+96 || messageOffset > len(b) {
+		return nil, fmt.Errorf("ssz: invalid message offset %d", messageOffset)
+	}
+
+	blk := &AnySignedBlock{}
+	blk.Signature = append(bytesHexStr{}, b[sszOffsetSize:sszOffsetSize+96]...)
+
+	msg := b[messageOffset:]
+	if len(msg) < 8+8+32+32+sszOffsetSize {
+		return nil, fmt.Errorf("ssz: block message too short (%d bytes)", len(msg))
+	}
+	blk.Message.Slot = sszUint64(msg[0:8])
+	blk.Message.ProposerIndex = sszUint64(msg[8:16])
+	blk.Message.ParentRoot = hex0x(msg[16:48])
+	blk.Message.StateRoot = hex0x(msg[48:80])
+	bodyOffset, err := sszOffset(msg, 80)
+	if err != nil {
+		return nil, err
+	}
+	if bodyOffset != 84 || bodyOffset > len(msg) {
+		return nil, fmt.Errorf("ssz: invalid body offset %d", bodyOffset)
+	}
+
+	err = decodeSSZBlockBody(blk, fork, msg[bodyOffset:])
+	if err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+func decodeSSZBlockBody(blk *AnySignedBlock, fork *utils.Fork, b []byte) error {
+	body := &blk.Message.Body
+	syncBitsLen := int(utils.Config.Chain.Config.SyncCommitteeSize / 8)
+
+	fixedLen := 96 + 72 + 32 + 5*sszOffsetSize
+	if fork.Has(utils.ForkFeatureSyncCommittees) {
+		fixedLen += syncBitsLen + 96
+	}
+	if fork.Has(utils.ForkFeatureExecutionPayload) {
+		fixedLen += sszOffsetSize
+	}
+	if fork.Has(utils.ForkFeatureBLSToExecutionChanges) {
+		fixedLen += sszOffsetSize
+	}
+	if fork.Has(utils.ForkFeatureBlobs) {
+		fixedLen += sszOffsetSize
+	}
+	if len(b) < fixedLen {
+		return fmt.Errorf("ssz: block body too short (%d bytes, expected at least %d)", len(b), fixedLen)
+	}
+
+	pos := 0
+	body.RandaoReveal = hex0x(b[pos : pos+96])
+	pos += 96
+	body.Eth1Data.DepositRoot = hex0x(b[pos : pos+32])
+	body.Eth1Data.DepositCount = sszUint64(b[pos+32 : pos+40])
+	body.Eth1Data.BlockHash = hex0x(b[pos+40 : pos+72])
+	pos += 72
+	body.Graffiti = hex0x(b[pos : pos+32])
+	pos += 32
+
+	offsets := make([]int, 0, 8)
+	readOffset := func() error {
+		offset, err := sszOffset(b, pos)
+		if err != nil {
+			return err
+		}
+		offsets = append(offsets, offset)
+		pos += sszOffsetSize
+		return nil
+	}
+	for i := 0; i < 5; i++ {
+		if err := readOffset(); err != nil {
+			return err
+		}
+	}
+	if fork.Has(utils.ForkFeatureSyncCommittees) {
+		body.SyncAggregate = &SyncAggregate{
+			SyncCommitteeBits:      hex0x(b[pos : pos+syncBitsLen]),
+			SyncCommitteeSignature: hex0x(b[pos+syncBitsLen : pos+syncBitsLen+96]),
+		}
+		pos += syncBitsLen + 96
+	}
+	if fork.Has(utils.ForkFeatureExecutionPayload) {
+		if err := readOffset(); err != nil {
+			return err
+		}
+	}
+	if fork.Has(utils.ForkFeatureBLSToExecutionChanges) {
+		if err := readOffset(); err != nil {
+			return err
+		}
+	}
+	if fork.Has(utils.ForkFeatureBlobs) {
+		if err := readOffset(); err != nil {
+			return err
+		}
+	}
+	if offsets[0] != fixedLen {
+		return fmt.Errorf("ssz: invalid first body offset %d, expected %d", offsets[0], fixedLen)
+	}
+
+	parts, err := sszVariableParts(b, offsets)
+	if err != nil {
+		return err
+	}
+
+	proposerSlashings, err := sszFixedList(parts[0], sszProposerSlashingLen)
+	if err != nil {
+		return err
+	}
+	body.ProposerSlashings = make([]ProposerSlashing, len(proposerSlashings))
+	for i, item := range proposerSlashings {
+		body.ProposerSlashings[i] = ProposerSlashing{
+			SignedHeader1: decodeSSZSignedHeader(item[:sszSignedHeaderLen]),
+			SignedHeader2: decodeSSZSignedHeader(item[sszSignedHeaderLen:]),
+		}
+	}
+
+	attesterSlashings, err := sszVariableList(parts[1])
+	if err != nil {
+		return err
+	}
+	body.AttesterSlashings = make([]AttesterSlashing, len(attesterSlashings))
+	for i, item := range attesterSlashings {
+		if len(item) < 2*sszOffsetSize {
+			return fmt.Errorf("ssz: attester slashing %d too short", i)
+		}
+		o1, _ := sszOffset(item, 0)
+		o2, _ := sszOffset(item, sszOffsetSize)
+		atts, err := sszVariableParts(item, []int{o1, o2})
+		if err != nil {
+			return err
+		}
+		att1, err := decodeSSZIndexedAttestation(atts[0])
+		if err != nil {
+			return err
+		}
+		att2, err := decodeSSZIndexedAttestation(atts[1])
+		if err != nil {
+			return err
+		}
+		body.AttesterSlashings[i] = AttesterSlashing{Attestation1: *att1, Attestation2: *att2}
+	}
+
+	attestations, err := sszVariableList(parts[2])
+	if err != nil {
+		return err
+	}
+	body.Attestations = make([]Attestation, len(attestations))
+	for i, item := range attestations {
+		if len(item) < sszOffsetSize+sszAttestationDataLen+96 {
+			return fmt.Errorf("ssz: attestation %d too short", i)
+		}
+		bitsOffset, _ := sszOffset(item, 0)
+		if bitsOffset != sszOffsetSize+sszAttestationDataLen+96 {
+			return fmt.Errorf("ssz: invalid aggregation bits offset %d of attestation %d", bitsOffset, i)
+		}
+		a := &body.Attestations[i]
+		a.Data = decodeSSZAttestationData(item[sszOffsetSize : sszOffsetSize+sszAttestationDataLen])
+		a.Signature = hex0x(item[sszOffsetSize+sszAttestationDataLen : bitsOffset])
+		a.AggregationBits = hex0x(item[bitsOffset:])
+	}
+
+	deposits, err := sszFixedList(parts[3], sszDepositLen)
+	if err != nil {
+		return err
+	}
+	body.Deposits = make([]Deposit, len(deposits))
+	for i, item := range deposits {
+		d := &body.Deposits[i]
+		d.Proof = make([]string, 33)
+		for j := 0; j < 33; j++ {
+			d.Proof[j] = hex0x(item[j*32 : (j+1)*32])
+		}
+		data := item[33*32:]
+		d.Data.Pubkey = hex0x(data[0:48])
+		d.Data.WithdrawalCredentials = hex0x(data[48:80])
+		d.Data.Amount = sszUint64(data[80:88])
+		d.Data.Signature = hex0x(data[88:184])
+	}
+
+	exits, err := sszFixedList(parts[4], sszVoluntaryExitLen)
+	if err != nil {
+		return err
+	}
+	body.VoluntaryExits = make([]VoluntaryExit, len(exits))
+	for i, item := range exits {
+		body.VoluntaryExits[i].Message.Epoch = sszUint64(item[0:8])
+		body.VoluntaryExits[i].Message.ValidatorIndex = sszUint64(item[8:16])
+		body.VoluntaryExits[i].Signature = hex0x(item[16:112])
+	}
+
+	if fork.Has(utils.ForkFeatureExecutionPayload) {
+		body.ExecutionPayload, err = decodeSSZExecutionPayload(fork, parts[5])
+		if err != nil {
+			return err
+		}
+	}
+
+	if fork.Has(utils.ForkFeatureBLSToExecutionChanges) {
+		changes, err := sszFixedList(parts[6], sszBLSChangeLen)
+		if err != nil {
+			return err
+		}
+		body.BlsToExecutionChanges = make([]SignedBLSToExecutionChange, len(changes))
+		for i, item := range changes {
+			c := &body.BlsToExecutionChanges[i]
+			c.Message.ValidatorIndex = sszUint64(item[0:8])
+			c.Message.FromBlsPubkey = append(bytesHexStr{}, item[8:56]...)
+			c.Message.ToExecutionAddress = append(bytesHexStr{}, item[56:76]...)
+			c.Signature = append(bytesHexStr{}, item[76:172]...)
+		}
+	}
+
+	if fork.Has(utils.ForkFeatureBlobs) {
+		commitments, err := sszFixedList(parts[7], sszKZGCommitmentLen)
+		if err != nil {
+			return err
+		}
+		body.BlobKzgCommitments = make([]bytesHexStr, len(commitments))
+		for i, item := range commitments {
+			body.BlobKzgCommitments[i] = append(bytesHexStr{}, item...)
+		}
+	}
+
+	return nil
+}
+
+func decodeSSZSignedHeader(b []byte) (header SignedBeaconBlockHeader) {
+	header.Message.Slot = sszUint64(b[0:8])
+	header.Message.ProposerIndex = sszUint64(b[8:16])
+	header.Message.ParentRoot = hex0x(b[16:48])
+	header.Message.StateRoot = hex0x(b[48:80])
+	header.Message.BodyRoot = hex0x(b[80:112])
+	header.Signature = hex0x(b[112:208])
+	return header
+}
+
+func decodeSSZAttestationData(b []byte) (data AttestationData) {
+	data.Slot = sszUint64(b[0:8])
+	data.Index = sszUint64(b[8:16])
+	data.BeaconBlockRoot = hex0x(b[16:48])
+	data.Source.Epoch = sszUint64(b[48:56])
+	data.Source.Root = hex0x(b[56:88])
+	data.Target.Epoch = sszUint64(b[88:96])
+	data.Target.Root = hex0x(b[96:128])
+	return data
+}
+
+func decodeSSZIndexedAttestation(b []byte) (*IndexedAttestation, error) {
+	fixedLen := sszOffsetSize + sszAttestationDataLen + 96
+	if len(b) < fixedLen {
+		return nil, fmt.Errorf("ssz: indexed attestation too short (%d bytes)", len(b))
+	}
+	indicesOffset, _ := sszOffset(b, 0)
+	if indicesOffset != fixedLen {
+		return nil, fmt.Errorf("ssz: invalid attesting indices offset %d", indicesOffset)
+	}
+	items, err := sszFixedList(b[fixedLen:], 8)
+	if err != nil {
+		return nil, err
+	}
+	att := &IndexedAttestation{
+		AttestingIndices: make([]uint64Str, len(items)),
+		Data:             decodeSSZAttestationData(b[sszOffsetSize : sszOffsetSize+sszAttestationDataLen]),
+		Signature:        hex0x(b[sszOffsetSize+sszAttestationDataLen : fixedLen]),
+	}
+	for i, item := range items {
+		att.AttestingIndices[i] = sszUint64(item)
+	}
+	return att, nil
+}
+
+func decodeSSZExecutionPayload(fork *utils.Fork, b []byte) (*ExecutionPayload, error) {
+	fixedLen := 32 + 20 + 32 + 32 + 256 + 32 + 4*8 + sszOffsetSize + 32 + 32 + sszOffsetSize
+	if fork.Has(utils.ForkFeatureWithdrawals) {
+		fixedLen += sszOffsetSize
+	}
+	if fork.Has(utils.ForkFeatureBlobs) {
+		fixedLen += 8 + 8
+	}
+	if len(b) < fixedLen {
+		return nil, fmt.Errorf("ssz: execution payload too short (%d bytes, expected at least %d)", len(b), fixedLen)
+	}
+
+	payload := &ExecutionPayload{}
+	pos := 0
+	field := func(size int) bytesHexStr {
+		v := append(bytesHexStr{}, b[pos:pos+size]...)
+		pos += size
+		return v
+	}
+	payload.ParentHash = field(32)
+	payload.FeeRecipient = field(20)
+	payload.StateRoot = field(32)
+	payload.ReceiptsRoot = field(32)
+	payload.LogsBloom = field(256)
+	payload.PrevRandao = field(32)
+	payload.BlockNumber = sszUint64(field(8))
+	payload.GasLimit = sszUint64(field(8))
+	payload.GasUsed = sszUint64(field(8))
+	payload.Timestamp = sszUint64(field(8))
+
+	offsets := make([]int, 0, 3)
+	extraDataOffset, _ := sszOffset(b, pos)
+	offsets = append(offsets, extraDataOffset)
+	pos += sszOffsetSize
+
+	// base_fee_per_gas is an uint256 in little endian
+	baseFee := field(32)
+	for i, j := 0, len(baseFee)-1; i < j; i, j = i+1, j-1 {
+		baseFee[i], baseFee[j] = baseFee[j], baseFee[i]
+	}
+	baseFeeInt := new(big.Int).SetBytes(baseFee)
+	if !baseFeeInt.IsUint64() {
+		return nil, fmt.Errorf("ssz: base fee per gas %v exceeds uint64", baseFeeInt)
+	}
+	payload.BaseFeePerGas = uint64Str(baseFeeInt.Uint64())
+	payload.BlockHash = field(32)
+
+	txsOffset, _ := sszOffset(b, pos)
+	offsets = append(offsets, txsOffset)
+	pos += sszOffsetSize
+	if fork.Has(utils.ForkFeatureWithdrawals) {
+		withdrawalsOffset, _ := sszOffset(b, pos)
+		offsets = append(offsets, withdrawalsOffset)
+		pos += sszOffsetSize
+	}
+	if fork.Has(utils.ForkFeatureBlobs) {
+		payload.BlobGasUsed = sszUint64(field(8))
+		payload.ExcessBlobGas = sszUint64(field(8))
+	}
+	if offsets[0] != fixedLen {
+		return nil, fmt.Errorf("ssz: invalid extra data offset %d, expected %d", offsets[0], fixedLen)
+	}
+
+	parts, err := sszVariableParts(b, offsets)
+	if err != nil {
+		return nil, err
+	}
+	payload.ExtraData = append(bytesHexStr{}, parts[0]...)
+
+	txs, err := sszVariableList(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	payload.Transactions = make([]bytesHexStr, len(txs))
+	for i, tx := range txs {
+		payload.Transactions[i] = append(bytesHexStr{}, tx...)
+	}
+
+	if fork.Has(utils.ForkFeatureWithdrawals) {
+		withdrawals, err := sszFixedList(parts[2], sszWithdrawalLen)
+		if err != nil {
+			return nil, err
+		}
+		payload.Withdrawals = make([]Withdrawal, len(withdrawals))
+		for i, item := range withdrawals {
+			payload.Withdrawals[i] = Withdrawal{
+				Index:          sszUint64(item[0:8]),
+				ValidatorIndex: sszUint64(item[8:16]),
+				Address:        append(bytesHexStr{}, item[16:36]...),
+				Amount:         sszUint64(item[36:44]),
+			}
+		}
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,248 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// sszVar marks a variable size field of a container, it is referenced by an offset in the fixed part
+type sszVar []byte
+
+// sszEncode will serialize a container with the given fixed ([]byte) and variable (sszVar) size fields, a list of
+// variable size elements is encoded the same way
+func sszEncode(fields ...interface{}) []byte {
+	fixedLen := 0
+	for _, f := range fields {
+		switch f := f.(type) {
+		case sszVar:
+			fixedLen += sszOffsetSize
+		case []byte:
+			fixedLen += len(f)
+		}
+	}
+	var fixed, variable []byte
+	for _, f := range fields {
+		switch f := f.(type) {
+		case sszVar:
+			offset := make([]byte, sszOffsetSize)
+			binary.LittleEndian.PutUint32(offset, uint32(fixedLen+len(variable)))
+			fixed = append(fixed, offset...)
+			variable = append(variable, f...)
+		case []byte:
+			fixed = append(fixed, f...)
+		}
+	}
+	return append(fixed, variable...)
+}
+
+func sszU64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+func filled(n int, b byte) []byte {
+	return bytes.Repeat([]byte{b}, n)
+}
+
+func filledHex(n int, b byte) string {
+	return hex0x(filled(n, b))
+}
+
+func testSSZAttestationData(slot uint64) []byte {
+	return bytes.Join([][]byte{sszU64(slot), sszU64(3), filled(32, 0x31), sszU64(9), filled(32, 0x32), sszU64(10), filled(32, 0x33)}, nil)
+}
+
+func testSSZSignedHeader(slot uint64) []byte {
+	return bytes.Join([][]byte{sszU64(slot), sszU64(5), filled(32, 0x21), filled(32, 0x22), filled(32, 0x23), filled(96, 0x24)}, nil)
+}
+
+// testSSZSignedBlock will encode a signed block of the fork with one operation of every kind the fork supports
+func testSSZSignedBlock(version string) []byte {
+	indexedAttestation := func(indices ...uint64) sszVar {
+		list := []byte{}
+		for _, i := range indices {
+			list = append(list, sszU64(i)...)
+		}
+		return sszEncode(sszVar(list), testSSZAttestationData(40), filled(96, 0x34))
+	}
+
+	fields := []interface{}{
+		filled(96, 0x01),
+		bytes.Join([][]byte{filled(32, 0x02), sszU64(7), filled(32, 0x03)}, nil),
+		filled(32, 0x04),
+		sszVar(append(testSSZSignedHeader(20), testSSZSignedHeader(20)...)),
+		sszVar(sszEncode(sszVar(sszEncode(indexedAttestation(1, 2), indexedAttestation(2, 3))))),
+		sszVar(sszEncode(sszVar(sszEncode(sszVar([]byte{0xff, 0x01}), testSSZAttestationData(41), filled(96, 0x35))))),
+		sszVar(bytes.Join([][]byte{filled(33*32, 0x41), filled(48, 0x42), filled(32, 0x43), sszU64(32e9), filled(96, 0x44)}, nil)),
+		sszVar(bytes.Join([][]byte{sszU64(11), sszU64(12), filled(96, 0x51)}, nil)),
+	}
+
+	if version != "phase0" {
+		fields = append(fields, filled(4, 0xff), filled(96, 0x61))
+	}
+	if version == "bellatrix" || version == "capella" || version == "deneb" {
+		baseFee := make([]byte, 32)
+		binary.LittleEndian.PutUint64(baseFee, 7e9)
+		payload := []interface{}{
+			filled(32, 0x71), filled(20, 0x72), filled(32, 0x73), filled(32, 0x74), filled(256, 0x75), filled(32, 0x76),
+			sszU64(100), sszU64(30e6), sszU64(21000), sszU64(1700000000),
+			sszVar("extra"),
+			baseFee,
+			filled(32, 0x77),
+			sszVar(sszEncode(sszVar{0x02, 0x01}, sszVar{0x02, 0x02, 0x03})),
+		}
+		if version == "capella" || version == "deneb" {
+			payload = append(payload, sszVar(bytes.Join([][]byte{sszU64(1000), sszU64(12), filled(20, 0x78), sszU64(15000)}, nil)))
+		}
+		if version == "deneb" {
+			payload = append(payload, sszU64(131072), sszU64(262144))
+		}
+		fields = append(fields, sszVar(sszEncode(payload...)))
+	}
+	if version == "capella" || version == "deneb" {
+		fields = append(fields, sszVar(bytes.Join([][]byte{sszU64(13), filled(48, 0x81), filled(20, 0x82), filled(96, 0x83)}, nil)))
+	}
+	if version == "deneb" {
+		fields = append(fields, sszVar(append(filled(48, 0x91), filled(48, 0x92)...)))
+	}
+
+	body := sszEncode(fields...)
+	message := sszEncode(sszU64(64), sszU64(8), filled(32, 0x11), filled(32, 0x12), sszVar(body))
+	return sszEncode(sszVar(message), filled(96, 0x13))
+}
+
+func TestDecodeSSZSignedBlock(t *testing.T) {
+	tests := []struct {
+		version           string
+		wantSyncAggregate bool
+		wantPayload       bool
+		wantWithdrawals   int
+		wantBLSChanges    int
+		wantCommitments   int
+		wantBlobGasUsed   uint64
+	}{
+		{version: "phase0"},
+		{version: "altair", wantSyncAggregate: true},
+		{version: "bellatrix", wantSyncAggregate: true, wantPayload: true},
+		{version: "capella", wantSyncAggregate: true, wantPayload: true, wantWithdrawals: 1, wantBLSChanges: 1},
+		{version: "deneb", wantSyncAggregate: true, wantPayload: true, wantWithdrawals: 1, wantBLSChanges: 1, wantCommitments: 2, wantBlobGasUsed: 131072},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			blk, err := decodeSSZSignedBlock(tt.version, testSSZSignedBlock(tt.version))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := blk.Message
+			body := msg.Body
+			checks := []struct {
+				name      string
+				got, want interface{}
+			}{
+				{"signature", hex0x(blk.Signature), filledHex(96, 0x13)},
+				{"slot", msg.Slot, uint64Str(64)},
+				{"proposer", msg.ProposerIndex, uint64Str(8)},
+				{"parent root", msg.ParentRoot, filledHex(32, 0x11)},
+				{"state root", msg.StateRoot, filledHex(32, 0x12)},
+				{"randao reveal", body.RandaoReveal, filledHex(96, 0x01)},
+				{"deposit count", body.Eth1Data.DepositCount, uint64Str(7)},
+				{"eth1 block hash", body.Eth1Data.BlockHash, filledHex(32, 0x03)},
+				{"graffiti", body.Graffiti, filledHex(32, 0x04)},
+				{"proposer slashings", len(body.ProposerSlashings), 1},
+				{"attester slashings", len(body.AttesterSlashings), 1},
+				{"attestations", len(body.Attestations), 1},
+				{"deposits", len(body.Deposits), 1},
+				{"voluntary exits", len(body.VoluntaryExits), 1},
+				{"sync aggregate", body.SyncAggregate != nil, tt.wantSyncAggregate},
+				{"execution payload", body.ExecutionPayload != nil, tt.wantPayload},
+				{"bls changes", len(body.BlsToExecutionChanges), tt.wantBLSChanges},
+				{"blob commitments", len(body.BlobKzgCommitments), tt.wantCommitments},
+			}
+			for _, c := range checks {
+				if fmt.Sprint(c.got) != fmt.Sprint(c.want) {
+					t.Errorf("unexpected %v: expected %v, got %v", c.name, c.want, c.got)
+				}
+			}
+			if t.Failed() {
+				return
+			}
+
+			if got := body.ProposerSlashings[0].SignedHeader2.Message.Slot; got != 20 {
+				t.Errorf("expected proposer slashing of slot 20, got %v", got)
+			}
+			if got := fmt.Sprint(body.AttesterSlashings[0].Attestation2.AttestingIndices); got != "[2 3]" {
+				t.Errorf("expected attesting indices [2 3], got %v", got)
+			}
+			if a := body.Attestations[0]; a.AggregationBits != "0xff01" || a.Data.Slot != 41 || a.Data.Target.Epoch != 10 {
+				t.Errorf("unexpected attestation %+v", a)
+			}
+			if d := body.Deposits[0]; len(d.Proof) != 33 || d.Data.Amount != 32e9 || d.Data.Pubkey != filledHex(48, 0x42) {
+				t.Errorf("unexpected deposit %+v", d.Data)
+			}
+			if e := body.VoluntaryExits[0]; e.Message.Epoch != 11 || e.Message.ValidatorIndex != 12 {
+				t.Errorf("unexpected voluntary exit %+v", e.Message)
+			}
+			if tt.wantSyncAggregate && body.SyncAggregate.SyncCommitteeBits != "0xffffffff" {
+				t.Errorf("unexpected sync committee bits %v", body.SyncAggregate.SyncCommitteeBits)
+			}
+			if tt.wantPayload {
+				p := body.ExecutionPayload
+				if p.BlockNumber != 100 || p.GasUsed != 21000 || p.BaseFeePerGas != 7e9 || string(p.ExtraData) != "extra" || hex0x(p.FeeRecipient) != filledHex(20, 0x72) {
+					t.Errorf("unexpected execution payload %+v", p)
+				}
+				if len(p.Transactions) != 2 || hex0x(p.Transactions[1]) != "0x020203" {
+					t.Errorf("unexpected transactions %v", p.Transactions)
+				}
+				if len(p.Withdrawals) != tt.wantWithdrawals {
+					t.Fatalf("expected %v withdrawals, got %v", tt.wantWithdrawals, len(p.Withdrawals))
+				}
+				if len(p.Withdrawals) > 0 && (p.Withdrawals[0].Index != 1000 || p.Withdrawals[0].Amount != 15000) {
+					t.Errorf("unexpected withdrawal %+v", p.Withdrawals[0])
+				}
+				if uint64(p.BlobGasUsed) != tt.wantBlobGasUsed {
+					t.Errorf("expected blob gas used %v, got %v", tt.wantBlobGasUsed, p.BlobGasUsed)
+				}
+			}
+			if tt.wantBLSChanges > 0 && body.BlsToExecutionChanges[0].Message.ValidatorIndex != 13 {
+				t.Errorf("unexpected bls change %+v", body.BlsToExecutionChanges[0].Message)
+			}
+			if tt.wantCommitments > 0 && hex0x(body.BlobKzgCommitments[1]) != filledHex(48, 0x92) {
+				t.Errorf("unexpected blob commitment %x", body.BlobKzgCommitments[1])
+			}
+		})
+	}
+}
+
+func TestDecodeSSZSignedBlockErrors(t *testing.T) {
+	capella := testSSZSignedBlock("capella")
+	invalidMessageOffset := append([]byte{}, capella...)
+	binary.LittleEndian.PutUint32(invalidMessageOffset, 99)
+
+	tests := []struct {
+		name    string
+		version string
+		data    []byte
+		wantErr string
+	}{
+		{name: "unknown fork", version: "unknown", data: capella, wantErr: "unsupported block version"},
+		{name: "too short", version: "capella", data: capella[:50], wantErr: "signed block too short"},
+		{name: "invalid message offset", version: "capella", data: invalidMessageOffset, wantErr: "invalid message offset"},
+		{name: "block of an earlier fork", version: "capella", data: testSSZSignedBlock("altair"), wantErr: "invalid first body offset"},
+		{name: "truncated list", version: "capella", data: capella[:len(capella)-1], wantErr: "not a multiple of element size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeSSZSignedBlock(tt.version, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,1731 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gtypes "github.com/ethereum/go-ethereum/core/types"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/sirupsen/logrus"
+)
+
+// StandardClient holds the info of a client that only uses the standard beacon-node REST api
+type StandardClient struct {
+	endpoint            string
+	ssz                 bool
+	participation       func(epoch uint64) (*types.ValidatorParticipation, error)
+	assignmentsCache    *lru.Cache
+	assignmentsCacheMux *sync.Mutex
+	signer              gtypes.Signer
+	chainEventCh        chan *types.ChainEvent
+
+	// canonCursor is the lowest canonical header walked back to from a finalized checkpoint
+	canonCursor    *StandardBeaconHeader
+	canonCursorMux *sync.Mutex
+}
+
+// NewStandardClient is used to create a new client for any spec-compliant beacon-node
+func NewStandardClient(endpoint string, chainID *big.Int) (*StandardClient, error) {
+	client := newStandardClient(endpoint, chainID)
+	client.ssz = true
+	return client, nil
+}
+
+func newStandardClient(endpoint string, chainID *big.Int) *StandardClient {
+	signer := gtypes.NewLondonSigner(chainID)
+	client := &StandardClient{
+		endpoint:            endpoint,
+		assignmentsCacheMux: &sync.Mutex{},
+		signer:              signer,
+		chainEventCh:        make(chan *types.ChainEvent, 10),
+		canonCursorMux:      &sync.Mutex{},
+	}
+	client.assignmentsCache, _ = lru.New(10)
+	client.participation = client.getStandardValidatorParticipation
+
+	return client
+}
+
+// GetNewBlockChan subscribes to the event stream of the node and pushes every new block into the returned channel.
+// If the stream drops, the node is polled every half slot until the stream can be re-established.
+func (sc *StandardClient) GetNewBlockChan() chan *types.Block {
+	blkCh := make(chan *types.Block, 10)
+	go func() {
+		lastHeadSlot := uint64(0)
+		headResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/head", sc.endpoint))
+		if err == nil {
+			var parsedHead StandardBeaconHeaderResponse
+			err = json.Unmarshal(headResp, &parsedHead)
+			if err != nil {
+				logger.Warnf("failed to decode head, starting blocks channel at slot 0")
+			} else {
+				lastHeadSlot = uint64(parsedHead.Data.Header.Message.Slot)
+			}
+		} else {
+			logger.Warnf("failed to fetch head, starting blocks channel at slot 0")
+		}
+
+		for {
+			err := sc.streamEvents(blkCh, &lastHeadSlot)
+			logger.Warnf("event stream dropped, falling back to polling: %v", err)
+			sc.pollNewBlocks(blkCh, &lastHeadSlot, eventStreamRetryInterval)
+		}
+	}()
+	return blkCh
+}
+
+// GetChainEventChan returns the channel that receives the chain_reorg and finalized_checkpoint events of the event stream
+func (sc *StandardClient) GetChainEventChan() chan *types.ChainEvent {
+	return sc.chainEventCh
+}
+
+var eventStreamRetryInterval = time.Minute
+
+// streamEvents reads the event stream of the node until it drops and returns the reason
+func (sc *StandardClient) streamEvents(blkCh chan *types.Block, lastHeadSlot *uint64) error {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/eth/v1/events?topics=head,block,chain_reorg,finalized_checkpoint", sc.endpoint), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// no timeout, the stream is expected to stay open
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("error-response: %s", data)
+	}
+	logger.Infof("subscribed to event stream of %v", sc.endpoint)
+
+	// a stalled stream is treated as dropped, nodes send a head event at least every slot
+	stallTimeout := time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot) * 4
+	stallTimer := time.AfterFunc(stallTimeout, func() { resp.Body.Close() })
+	defer stallTimer.Stop()
+
+	// blocks are fetched and pushed on a separate goroutine, so neither a slow node nor a slow consumer of the blocks
+	// holds up reading the stream and trips the stall timer
+	blockEvents := make(chan *StandardBlockEvent, 32)
+	fetcherDone := make(chan struct{})
+	go func() {
+		defer close(fetcherDone)
+		for blockEvent := range blockEvents {
+			err := sc.pushBlockEvent(blockEvent, blkCh, lastHeadSlot)
+			if err != nil {
+				logger.Errorf("error handling block event of slot %v: %v", blockEvent.Slot, err)
+			}
+		}
+	}()
+	// lastHeadSlot is only safe to use again once the fetcher is done
+	defer func() {
+		close(blockEvents)
+		<-fetcherDone
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	event := ""
+	data := &bytes.Buffer{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("event stream closed by node")
+			}
+			return err
+		}
+		stallTimer.Reset(stallTimeout)
+
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			// an empty line dispatches the event
+			if event != "" && data.Len() > 0 {
+				err := sc.handleEvent(event, data.Bytes(), blockEvents)
+				if err != nil {
+					logger.Errorf("error handling %v event: %v", event, err)
+				}
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// comment / keep-alive
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+}
+
+// handleEvent handles an event of the stream, block events are handed to the fetcher without waiting for it
+func (sc *StandardClient) handleEvent(event string, data []byte, blockEvents chan *StandardBlockEvent) error {
+	switch event {
+	case "block":
+		var parsedEvent StandardBlockEvent
+		err := json.Unmarshal(data, &parsedEvent)
+		if err != nil {
+			return err
+		}
+		select {
+		case blockEvents <- &parsedEvent:
+		default:
+			// the slot is filled up with the next block event or by polling
+			logger.Warnf("block event queue is full, dropping block event of slot %v", parsedEvent.Slot)
+		}
+	case "head":
+		var parsedEvent StandardHeadEvent
+		err := json.Unmarshal(data, &parsedEvent)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("new head %v at slot %v", parsedEvent.Block, parsedEvent.Slot)
+	case "chain_reorg":
+		var parsedEvent StandardChainReorgEvent
+		err := json.Unmarshal(data, &parsedEvent)
+		if err != nil {
+			return err
+		}
+		logger.Warnf("chain reorg of depth %v at slot %v: %v -> %v", parsedEvent.Depth, parsedEvent.Slot, parsedEvent.OldHeadBlock, parsedEvent.NewHeadBlock)
+		sc.pushChainEvent(&types.ChainEvent{
+			Reorg: &types.ChainReorg{
+				Slot:         uint64(parsedEvent.Slot),
+				Depth:        uint64(parsedEvent.Depth),
+				Epoch:        uint64(parsedEvent.Epoch),
+				OldHeadBlock: utils.MustParseHex(parsedEvent.OldHeadBlock),
+				NewHeadBlock: utils.MustParseHex(parsedEvent.NewHeadBlock),
+				OldHeadState: utils.MustParseHex(parsedEvent.OldHeadState),
+				NewHeadState: utils.MustParseHex(parsedEvent.NewHeadState),
+			},
+		})
+	case "finalized_checkpoint":
+		var parsedEvent StandardFinalizedCheckpointEvent
+		err := json.Unmarshal(data, &parsedEvent)
+		if err != nil {
+			return err
+		}
+		sc.pushChainEvent(&types.ChainEvent{
+			Finalized: &types.FinalizedCheckpoint{
+				Epoch: uint64(parsedEvent.Epoch),
+				Block: utils.MustParseHex(parsedEvent.Block),
+				State: utils.MustParseHex(parsedEvent.State),
+			},
+		})
+	}
+	return nil
+}
+
+// pushBlockEvent fetches the block of a block event and pushes it into the channel, slots that were missed since the
+// last pushed block are filled up first
+func (sc *StandardClient) pushBlockEvent(blockEvent *StandardBlockEvent, blkCh chan *types.Block, lastHeadSlot *uint64) error {
+	slot := uint64(blockEvent.Slot)
+
+	// fill up slots we might have missed while the stream was down
+	if slot > *lastHeadSlot+1 {
+		sc.pushBlocks(blkCh, *lastHeadSlot+1, slot-1)
+	}
+
+	block, err := sc.GetBlockByBlockroot(utils.MustParseHex(blockEvent.Block))
+	if err != nil {
+		return err
+	}
+	if block.BlockRoot == nil {
+		return fmt.Errorf("block %v of slot %v not found", blockEvent.Block, slot)
+	}
+	blkCh <- block
+	if slot > *lastHeadSlot {
+		*lastHeadSlot = slot
+	}
+	return nil
+}
+
+// pushChainEvent will not block if nobody consumes the chain events
+func (sc *StandardClient) pushChainEvent(event *types.ChainEvent) {
+	select {
+	case sc.chainEventCh <- event:
+	default:
+		logger.Warnf("chain event channel is full, dropping event")
+	}
+}
+
+// pollNewBlocks polls the sync status of the node 2 times per slot for the given duration
+func (sc *StandardClient) pollNewBlocks(blkCh chan *types.Block, lastHeadSlot *uint64, duration time.Duration) {
+	t := time.NewTicker(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot) / 2)
+	defer t.Stop()
+	deadline := time.Now().Add(duration)
+
+	for time.Now().Before(deadline) {
+		<-t.C
+		syncingResp, err := sc.get(fmt.Sprintf("%s/eth/v1/node/syncing", sc.endpoint))
+		if err != nil {
+			logger.Warnf("failed to retrieve syncing status: %v", err)
+			continue
+		}
+		var parsedSyncing StandardSyncingResponse
+		err = json.Unmarshal(syncingResp, &parsedSyncing)
+		if err != nil {
+			logger.Warnf("failed to decode syncing status: %v", err)
+			continue
+		}
+		headSlot := uint64(parsedSyncing.Data.HeadSlot)
+		if headSlot > *lastHeadSlot {
+			sc.pushBlocks(blkCh, *lastHeadSlot+1, headSlot)
+			*lastHeadSlot = headSlot
+		}
+	}
+}
+
+// pushBlocks fetches the blocks of the slots from start to end (inclusive) and pushes them into the channel
+func (sc *StandardClient) pushBlocks(blkCh chan *types.Block, start, end uint64) {
+	for slot := start; slot <= end; slot++ {
+		blks, err := sc.GetBlocksBySlot(slot)
+		if err != nil {
+			logger.Warnf("failed to fetch block(s) for slot %d: %v", slot, err)
+			continue
+		}
+		for _, blk := range blks {
+			blkCh <- blk
+		}
+	}
+}
+
+// GetChainHead gets the chain head from the node
+func (sc *StandardClient) GetChainHead() (*types.ChainHead, error) {
+	headResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/head", sc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving chain head: %w", err)
+	}
+
+	var parsedHead StandardBeaconHeaderResponse
+	err = json.Unmarshal(headResp, &parsedHead)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing chain head: %w", err)
+	}
+
+	id := parsedHead.Data.Header.Message.StateRoot
+	if parsedHead.Data.Header.Message.Slot == 0 {
+		id = "genesis"
+	}
+	finalityResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%s/finality_checkpoints", sc.endpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving finality checkpoints of head: %w", err)
+	}
+
+	var parsedFinality StandardFinalityCheckpointsResponse
+	err = json.Unmarshal(finalityResp, &parsedFinality)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing finality checkpoints of head: %w", err)
+	}
+
+	return &types.ChainHead{
+		HeadSlot:                   uint64(parsedHead.Data.Header.Message.Slot),
+		HeadEpoch:                  uint64(parsedHead.Data.Header.Message.Slot) / utils.Config.Chain.Config.SlotsPerEpoch,
+		HeadBlockRoot:              utils.MustParseHex(parsedHead.Data.Root),
+		FinalizedSlot:              uint64(parsedFinality.Data.Finalized.Epoch) * utils.Config.Chain.Config.SlotsPerEpoch,
+		FinalizedEpoch:             uint64(parsedFinality.Data.Finalized.Epoch),
+		FinalizedBlockRoot:         utils.MustParseHex(parsedFinality.Data.Finalized.Root),
+		JustifiedSlot:              uint64(parsedFinality.Data.CurrentJustified.Epoch) * utils.Config.Chain.Config.SlotsPerEpoch,
+		JustifiedEpoch:             uint64(parsedFinality.Data.CurrentJustified.Epoch),
+		JustifiedBlockRoot:         utils.MustParseHex(parsedFinality.Data.CurrentJustified.Root),
+		PreviousJustifiedSlot:      uint64(parsedFinality.Data.PreviousJustified.Epoch) * utils.Config.Chain.Config.SlotsPerEpoch,
+		PreviousJustifiedEpoch:     uint64(parsedFinality.Data.PreviousJustified.Epoch),
+		PreviousJustifiedBlockRoot: utils.MustParseHex(parsedFinality.Data.PreviousJustified.Root),
+	}, nil
+}
+
+func (sc *StandardClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
+	// pre-filter the status, to return much less validators, thus much faster!
+	validatorsResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/head/validators?status=pending_queued,exited", sc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator for head valiqdator queue check: %w", err)
+	}
+
+	var parsedValidators StandardValidatorsResponse
+	err = json.Unmarshal(validatorsResp, &parsedValidators)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing queue validators: %w", err)
+	}
+	// TODO: maybe track more status counts in the future?
+	activatingValidatorCount := uint64(0)
+	exitingValidatorCount := uint64(0)
+	for _, validator := range parsedValidators.Data {
+		switch validator.Status {
+		case "pending_initialized":
+			break
+		case "pending_queued":
+			activatingValidatorCount += 1
+			break
+		case "active_ongoing", "active_exiting", "active_slashed":
+			break
+		case "exited_unslashed", "exited_slashed":
+			exitingValidatorCount += exitingValidatorCount
+			break
+		case "withdrawal_possible", "withdrawal_done":
+			break
+		default:
+			return nil, fmt.Errorf("unrecognized validator status (validator %d): %s", validator.Index, validator.Status)
+		}
+	}
+	return &types.ValidatorQueue{
+		Activating: activatingValidatorCount,
+		Exititing:  exitingValidatorCount,
+	}, nil
+}
+
+// GetEpochAssignments will get the epoch assignments from the beacon-node api
+func (sc *StandardClient) GetEpochAssignments(epoch uint64) (*types.EpochAssignments, error) {
+	sc.assignmentsCacheMux.Lock()
+	defer sc.assignmentsCacheMux.Unlock()
+
+	var err error
+
+	cachedValue, found := sc.assignmentsCache.Get(epoch)
+	if found {
+		return cachedValue.(*types.EpochAssignments), nil
+	}
+
+	proposerResp, err := sc.get(fmt.Sprintf("%s/eth/v1/validator/duties/proposer/%d", sc.endpoint, epoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving proposer duties: %w", err)
+	}
+	var parsedProposerResponse StandardProposerDutiesResponse
+	err = json.Unmarshal(proposerResp, &parsedProposerResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing proposer duties: %w", err)
+	}
+
+	// fetch the block root that the proposer data is dependent on
+	headerResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/%s", sc.endpoint, parsedProposerResponse.DependentRoot))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving chain header: %w", err)
+	}
+	var parsedHeader StandardBeaconHeaderResponse
+	err = json.Unmarshal(headerResp, &parsedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing chain header: %w", err)
+	}
+	depStateRoot := parsedHeader.Data.Header.Message.StateRoot
+
+	// Now use the state root to make a consistent committee query
+	committeesResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%s/committees?epoch=%d", sc.endpoint, depStateRoot, epoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving committees data: %w", err)
+	}
+	var parsedCommittees StandardCommitteesResponse
+	err = json.Unmarshal(committeesResp, &parsedCommittees)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing committees data: %w", err)
+	}
+
+	assignments := &types.EpochAssignments{
+		ProposerAssignments: make(map[uint64]uint64),
+		AttestorAssignments: make(map[string]uint64),
+	}
+
+	// propose
+	for _, duty := range parsedProposerResponse.Data {
+		assignments.ProposerAssignments[uint64(duty.Slot)] = uint64(duty.ValidatorIndex)
+	}
+
+	// attest
+	for _, committee := range parsedCommittees.Data {
+		for i, valIndex := range committee.Validators {
+			valIndexU64, err := strconv.ParseUint(valIndex, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("epoch %d committee %d index %d has bad validator index %q", epoch, committee.Index, i, valIndex)
+			}
+			k := utils.FormatAttestorAssignmentKey(uint64(committee.Slot), uint64(committee.Index), uint64(i))
+			assignments.AttestorAssignments[k] = valIndexU64
+		}
+	}
+
+	if utils.ForkFeatureActive(utils.ForkFeatureSyncCommittees, epoch) {
+		syncCommitteeState := depStateRoot
+		if syncCommitteesEpoch := utils.ForkFeatureEpoch(utils.ForkFeatureSyncCommittees); epoch == syncCommitteesEpoch {
+			syncCommitteeState = fmt.Sprintf("%d", syncCommitteesEpoch*utils.Config.Chain.Config.SlotsPerEpoch)
+		}
+		parsedSyncCommittees, err := sc.GetSyncCommittee(syncCommitteeState, epoch)
+		if err != nil {
+			return nil, err
+		}
+		assignments.SyncAssignments = make([]uint64, len(parsedSyncCommittees.Validators))
+
+		// sync
+		for i, valIndexStr := range parsedSyncCommittees.Validators {
+			valIndexU64, err := strconv.ParseUint(valIndexStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("in sync_committee for epoch %d validator %d has bad validator index: %q", epoch, i, valIndexStr)
+			}
+			assignments.SyncAssignments[i] = valIndexU64
+		}
+	}
+
+	if len(assignments.AttestorAssignments) > 0 && len(assignments.ProposerAssignments) > 0 {
+		sc.assignmentsCache.Add(epoch, assignments)
+	}
+
+	return assignments, nil
+}
+
+// GetEpochData will get the epoch data from the beacon-node api
+func (sc *StandardClient) GetEpochData(epoch uint64) (*types.EpochData, error) {
+	wg := &sync.WaitGroup{}
+	mux := &sync.Mutex{}
+	var err error
+
+	data := &types.EpochData{}
+	data.Epoch = epoch
+
+	validatorsResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", sc.endpoint, epoch*utils.Config.Chain.Config.SlotsPerEpoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators for epoch %v: %w", epoch, err)
+	}
+
+	var parsedValidators StandardValidatorsResponse
+	err = json.Unmarshal(validatorsResp, &parsedValidators)
+
+	if err != nil {
+		return nil, fmt.Errorf("error parsing epoch validators: %w", err)
+	}
+
+	epoch1d := int64(epoch) - 225
+	epoch7d := int64(epoch) - 225*7
+	epoch31d := int64(epoch) - 225*31
+
+	var validatorBalances1d map[uint64]uint64
+	var validatorBalances7d map[uint64]uint64
+	var validatorBalances31d map[uint64]uint64
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		validatorBalances1d, err = sc.GetBalancesForEpoch(epoch1d)
+		if err != nil {
+			logrus.Errorf("error retrieving validator balances for epoch %v (1d): %v", epoch1d, err)
+			return
+		}
+		logger.Printf("retrieved data for %v validator balances for epoch %v (1d) took %v", len(parsedValidators.Data), epoch1d, time.Since(start))
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		validatorBalances7d, err = sc.GetBalancesForEpoch(epoch7d)
+		if err != nil {
+			logrus.Errorf("error retrieving validator balances for epoch %v (7d): %v", epoch7d, err)
+			return
+		}
+		logger.Printf("retrieved data for %v validator balances for epoch %v (7d) took %v", len(parsedValidators.Data), epoch7d, time.Since(start))
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		validatorBalances31d, err = sc.GetBalancesForEpoch(epoch31d)
+		if err != nil {
+			logrus.Errorf("error retrieving validator balances for epoch %v (31d): %v", epoch31d, err)
+			return
+		}
+		logger.Printf("retrieved data for %v validator balances for epoch %v (31d) took %v", len(parsedValidators.Data), epoch31d, time.Since(start))
+	}()
+	wg.Wait()
+
+	for _, validator := range parsedValidators.Data {
+		data.Validators = append(data.Validators, &types.Validator{
+			Index:                      uint64(validator.Index),
+			PublicKey:                  utils.MustParseHex(validator.Validator.Pubkey),
+			WithdrawalCredentials:      utils.MustParseHex(validator.Validator.WithdrawalCredentials),
+			Balance:                    uint64(validator.Balance),
+			EffectiveBalance:           uint64(validator.Validator.EffectiveBalance),
+			Slashed:                    validator.Validator.Slashed,
+			ActivationEligibilityEpoch: uint64(validator.Validator.ActivationEligibilityEpoch),
+			ActivationEpoch:            uint64(validator.Validator.ActivationEpoch),
+			ExitEpoch:                  uint64(validator.Validator.ExitEpoch),
+			WithdrawableEpoch:          uint64(validator.Validator.WithdrawableEpoch),
+			Balance1d:                  validatorBalances1d[uint64(validator.Index)],
+			Balance7d:                  validatorBalances7d[uint64(validator.Index)],
+			Balance31d:                 validatorBalances31d[uint64(validator.Index)],
+			Status:                     validator.Status,
+		})
+	}
+
+	logger.Printf("retrieved data for %v validators for epoch %v", len(data.Validators), epoch)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		data.ValidatorAssignmentes, err = sc.GetEpochAssignments(epoch)
+		if err != nil {
+			logrus.Errorf("error retrieving assignments for epoch %v: %v", epoch, err)
+			return
+		}
+		logger.Printf("retrieved validator assignment data for epoch %v", epoch)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		data.EpochParticipationStats, err = sc.GetValidatorParticipation(epoch)
+		if err != nil {
+			// the participation is saved as unknown and filled in by the epoch status update once it is available
+			logger.Errorf("error retrieving epoch participation statistics for epoch %v: %v", epoch, err)
+			data.EpochParticipationStats = nil
+		}
+	}()
+
+	// Retrieve all blocks for the epoch
+	data.Blocks = make(map[uint64]map[string]*types.Block)
+
+	for slot := epoch * utils.Config.Chain.Config.SlotsPerEpoch; slot <= (epoch+1)*utils.Config.Chain.Config.SlotsPerEpoch-1; slot++ {
+		if slot == 0 || utils.SlotToTime(slot).After(time.Now()) { // Currently slot 0 returns all blocks, also skip asking for future blocks
+			continue
+		}
+		wg.Add(1)
+		go func(slot uint64) {
+			defer wg.Done()
+			blocks, err := sc.GetBlocksBySlot(slot)
+
+			if err != nil {
+				logger.Errorf("error retrieving blocks for slot %v: %v", slot, err)
+				return
+			}
+
+			for _, block := range blocks {
+				mux.Lock()
+				if data.Blocks[block.Slot] == nil {
+					data.Blocks[block.Slot] = make(map[string]*types.Block)
+				}
+				data.Blocks[block.Slot][fmt.Sprintf("%x", block.BlockRoot)] = block
+				mux.Unlock()
+			}
+		}(slot)
+	}
+	wg.Wait()
+	logger.Printf("retrieved %v blocks for epoch %v", len(data.Blocks), epoch)
+
+	// Fill up missed and scheduled blocks
+	for slot, proposer := range data.ValidatorAssignmentes.ProposerAssignments {
+		_, found := data.Blocks[slot]
+		if !found {
+			// Proposer was assigned but did not yet propose a block
+			data.Blocks[slot] = make(map[string]*types.Block)
+			data.Blocks[slot]["0x0"] = &types.Block{
+				Status:            0,
+				Canonical:         true,
+				Proposer:          proposer,
+				BlockRoot:         []byte{0x0},
+				Slot:              slot,
+				ParentRoot:        []byte{},
+				StateRoot:         []byte{},
+				Signature:         []byte{},
+				RandaoReveal:      []byte{},
+				Graffiti:          []byte{},
+				BodyRoot:          []byte{},
+				Eth1Data:          &types.Eth1Data{},
+				ProposerSlashings: make([]*types.ProposerSlashing, 0),
+				AttesterSlashings: make([]*types.AttesterSlashing, 0),
+				Attestations:      make([]*types.Attestation, 0),
+				Deposits:          make([]*types.Deposit, 0),
+				VoluntaryExits:    make([]*types.VoluntaryExit, 0),
+				SyncAggregate:     nil,
+			}
+
+			if utils.SlotToTime(slot).After(time.Now().Add(time.Second * -60)) {
+				// Block is in the future, set status to scheduled
+				data.Blocks[slot]["0x0"].Status = 0
+				data.Blocks[slot]["0x0"].BlockRoot = []byte{0x0}
+			} else {
+				// Block is in the past, set status to missed
+				data.Blocks[slot]["0x0"].Status = 2
+				data.Blocks[slot]["0x0"].BlockRoot = []byte{0x1}
+			}
+		}
+	}
+
+	return data, nil
+}
+
+func uint64List(li []uint64Str) []uint64 {
+	out := make([]uint64, len(li), len(li))
+	for i, v := range li {
+		out[i] = uint64(v)
+	}
+	return out
+}
+
+func (sc *StandardClient) GetBalancesForEpoch(epoch int64) (map[uint64]uint64, error) {
+
+	if epoch < 0 {
+		epoch = 0
+	}
+
+	var err error
+
+	validatorBalances := make(map[uint64]uint64)
+
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validator_balances", sc.endpoint, (epoch+1)*int64(utils.Config.Chain.Config.SlotsPerEpoch)-1))
+	if err != nil {
+		return validatorBalances, err
+	}
+
+	var parsedResponse StandardValidatorBalancesResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response for validator_balances")
+	}
+
+	for _, b := range parsedResponse.Data {
+		validatorBalances[uint64(b.Index)] = uint64(b.Balance)
+	}
+
+	return validatorBalances, nil
+}
+
+func (sc *StandardClient) GetBlockByBlockroot(blockroot []byte) (*types.Block, error) {
+	resHeaders, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/0x%x", sc.endpoint, blockroot))
+	if err != nil {
+		if err == notFoundErr {
+			// no block found
+			return &types.Block{}, nil
+		}
+		return nil, fmt.Errorf("error retrieving headers for blockroot 0x%x: %w", blockroot, err)
+	}
+	var parsedHeaders StandardBeaconHeaderResponse
+	err = json.Unmarshal(resHeaders, &parsedHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing header-response for blockroot 0x%x: %w", blockroot, err)
+	}
+
+	slot := uint64(parsedHeaders.Data.Header.Message.Slot)
+
+	parsedResponse, err := sc.getBlock(parsedHeaders.Data.Root)
+	if err != nil {
+		logger.Errorf("error retrieving block data at slot %v: %v", slot, err)
+		return nil, fmt.Errorf("error retrieving block data at slot %v: %w", slot, err)
+	}
+
+	return sc.blockFromResponse(&parsedHeaders, parsedResponse)
+}
+
+// GetBlocksBySlot will get the blocks by slot from the beacon-node api
+func (sc *StandardClient) GetBlocksBySlot(slot uint64) ([]*types.Block, error) {
+	resHeaders, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/%d", sc.endpoint, slot))
+	if err != nil {
+		if err == notFoundErr {
+			// no block found
+			return []*types.Block{}, nil
+		}
+		return nil, fmt.Errorf("error retrieving headers at slot %v: %w", slot, err)
+	}
+	var parsedHeaders StandardBeaconHeaderResponse
+	err = json.Unmarshal(resHeaders, &parsedHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing header-response at slot %v: %w", slot, err)
+	}
+
+	parsedResponse, err := sc.getBlock(parsedHeaders.Data.Root)
+	if err != nil {
+		logger.Errorf("error retrieving block data at slot %v: %v", slot, err)
+		return nil, fmt.Errorf("error retrieving block data at slot %v: %w", slot, err)
+	}
+
+	block, err := sc.blockFromResponse(&parsedHeaders, parsedResponse)
+	if err != nil {
+		return nil, err
+	}
+	return []*types.Block{block}, nil
+}
+
+// GetBlockStatusByEpoch will return all blocks of an epoch known to the node with their canonical status.
+// The canonical chain is determined by walking back through the parent roots, starting at the finalized checkpoint
+// for finalized epochs, at the justified checkpoint for justified epochs and at the head otherwise.
+func (sc *StandardClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonBlock, error) {
+	startSlot := epoch * utils.Config.Chain.Config.SlotsPerEpoch
+	endSlot := (epoch+1)*utils.Config.Chain.Config.SlotsPerEpoch - 1
+
+	head, err := sc.GetChainHead()
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := head.HeadBlockRoot
+	finalized := false
+	if epoch < head.FinalizedEpoch {
+		anchor = head.FinalizedBlockRoot
+		finalized = true
+	} else if epoch < head.JustifiedEpoch {
+		anchor = head.JustifiedBlockRoot
+	}
+
+	sc.canonCursorMux.Lock()
+	defer sc.canonCursorMux.Unlock()
+
+	// continue from a previous walk if it already reached further back than the end of the epoch
+	var current *StandardBeaconHeader
+	if finalized && sc.canonCursor != nil && uint64(sc.canonCursor.Header.Message.Slot) > endSlot {
+		current = sc.canonCursor
+	} else {
+		current, err = sc.getHeader(fmt.Sprintf("0x%x", anchor))
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving header of anchor 0x%x: %w", anchor, err)
+		}
+	}
+
+	canonicalRoots := make(map[string]uint64)
+	for current != nil && uint64(current.Header.Message.Slot) >= startSlot {
+		slot := uint64(current.Header.Message.Slot)
+		if slot <= endSlot {
+			canonicalRoots[current.Root] = slot
+		}
+		if finalized {
+			sc.canonCursor = current
+		}
+		if slot == 0 {
+			break
+		}
+
+		parent, err := sc.getHeader(current.Header.Message.ParentRoot)
+		if err != nil {
+			if err == notFoundErr {
+				// the node does not know the parent (e.g. checkpoint-synced node), stop walking
+				logger.Warnf("parent %v of block %v at slot %v not found, stopping canonical chain walk", current.Header.Message.ParentRoot, current.Root, slot)
+				break
+			}
+			return nil, fmt.Errorf("error retrieving parent header %v at slot %v: %w", current.Header.Message.ParentRoot, slot, err)
+		}
+		current = parent
+	}
+
+	blocks := make([]*types.CanonBlock, 0, len(canonicalRoots))
+	for slot := startSlot; slot <= endSlot; slot++ {
+		if slot != 0 && utils.SlotToTime(slot).After(time.Now()) {
+			break
+		}
+
+		if finalized {
+			// the slot to root mapping of the node has to agree with the walk for finalized slots
+			rootResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/blocks/%d/root", sc.endpoint, slot))
+			if err != nil && err != notFoundErr {
+				return nil, fmt.Errorf("error retrieving block root at slot %v: %w", slot, err)
+			}
+			if err == nil {
+				var parsedRoot StandardV1BlockRootResponse
+				err = json.Unmarshal(rootResp, &parsedRoot)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing block root at slot %v: %w", slot, err)
+				}
+				// skipped slots resolve to the last block before them, so only slots with a block are compared
+				rootSlot, found := canonicalRoots[parsedRoot.Data.Root]
+				if slotHasCanonicalRoot(canonicalRoots, slot) && (!found || rootSlot != slot) {
+					logger.Warnf("canonical block root %v at slot %v reported by the node does not match the canonical chain", parsedRoot.Data.Root, slot)
+				}
+			}
+		}
+
+		headersResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers?slot=%d", sc.endpoint, slot))
+		if err != nil {
+			if err == notFoundErr {
+				continue
+			}
+			return nil, fmt.Errorf("error retrieving headers at slot %v: %w", slot, err)
+		}
+		var parsedHeaders StandardBeaconHeadersResponse
+		err = json.Unmarshal(headersResp, &parsedHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing headers at slot %v: %w", slot, err)
+		}
+
+		seen := make(map[string]bool)
+		for _, header := range parsedHeaders.Data {
+			if uint64(header.Header.Message.Slot) != slot || seen[header.Root] {
+				continue
+			}
+			seen[header.Root] = true
+			_, canonical := canonicalRoots[header.Root]
+			blocks = append(blocks, &types.CanonBlock{
+				BlockRoot: utils.MustParseHex(header.Root),
+				Slot:      slot,
+				Canonical: canonical,
+			})
+		}
+		// make sure the canonical block is part of the result even if the node did not list it
+		for root, rootSlot := range canonicalRoots {
+			if rootSlot == slot && !seen[root] {
+				blocks = append(blocks, &types.CanonBlock{
+					BlockRoot: utils.MustParseHex(root),
+					Slot:      slot,
+					Canonical: true,
+				})
+			}
+		}
+	}
+
+	return blocks, nil
+}
+
+func slotHasCanonicalRoot(canonicalRoots map[string]uint64, slot uint64) bool {
+	for _, rootSlot := range canonicalRoots {
+		if rootSlot == slot {
+			return true
+		}
+	}
+	return false
+}
+
+func (sc *StandardClient) getHeader(blockID string) (*StandardBeaconHeader, error) {
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/%s", sc.endpoint, blockID))
+	if err != nil {
+		return nil, err
+	}
+	var parsedHeader StandardBeaconHeaderResponse
+	err = json.Unmarshal(resp, &parsedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing header %v: %w", blockID, err)
+	}
+	return &parsedHeader.Data, nil
+}
+
+// getBlock retrieves a signed block, ssz encoded if the client is configured to and the node offers it
+func (sc *StandardClient) getBlock(blockID string) (*StandardV2BlockResponse, error) {
+	url := fmt.Sprintf("%s/eth/v2/beacon/blocks/%s", sc.endpoint, blockID)
+	if !sc.ssz {
+		resp, err := sc.get(url)
+		if err != nil {
+			return nil, err
+		}
+		var parsedResponse StandardV2BlockResponse
+		err = json.Unmarshal(resp, &parsedResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing block-response of block %v: %w", blockID, err)
+		}
+		return &parsedResponse, nil
+	}
+
+	resp, isSSZ, version, err := sc.getPreferSSZ(url)
+	if err != nil {
+		return nil, err
+	}
+	if isSSZ {
+		block, err := decodeSSZSignedBlock(version, resp)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ssz block %v: %w", blockID, err)
+		}
+		return &StandardV2BlockResponse{Version: version, Data: *block}, nil
+	}
+	var parsedResponse StandardV2BlockResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing block-response of block %v: %w", blockID, err)
+	}
+	return &parsedResponse, nil
+}
+
+func (sc *StandardClient) blockFromResponse(parsedHeaders *StandardBeaconHeaderResponse, parsedResponse *StandardV2BlockResponse) (*types.Block, error) {
+	parsedBlock := parsedResponse.Data
+	slot := uint64(parsedHeaders.Data.Header.Message.Slot)
+	if fork := utils.ForkAtSlot(slot); parsedResponse.Version != "" && fork.Name != parsedResponse.Version {
+		logger.Warnf("block at slot %v is a %v block but the fork schedule expects %v, check the fork epochs of the chain config", slot, parsedResponse.Version, fork.Name)
+	}
+	block := &types.Block{
+		Status:       1,
+		Canonical:    parsedHeaders.Data.Canonical,
+		Proposer:     uint64(parsedBlock.Message.ProposerIndex),
+		BlockRoot:    utils.MustParseHex(parsedHeaders.Data.Root),
+		Slot:         slot,
+		ParentRoot:   utils.MustParseHex(parsedBlock.Message.ParentRoot),
+		StateRoot:    utils.MustParseHex(parsedBlock.Message.StateRoot),
+		Signature:    parsedBlock.Signature,
+		RandaoReveal: utils.MustParseHex(parsedBlock.Message.Body.RandaoReveal),
+		Graffiti:     utils.MustParseHex(parsedBlock.Message.Body.Graffiti),
+		Eth1Data: &types.Eth1Data{
+			DepositRoot:  utils.MustParseHex(parsedBlock.Message.Body.Eth1Data.DepositRoot),
+			DepositCount: uint64(parsedBlock.Message.Body.Eth1Data.DepositCount),
+			BlockHash:    utils.MustParseHex(parsedBlock.Message.Body.Eth1Data.BlockHash),
+		},
+		ProposerSlashings: make([]*types.ProposerSlashing, len(parsedBlock.Message.Body.ProposerSlashings)),
+		AttesterSlashings: make([]*types.AttesterSlashing, len(parsedBlock.Message.Body.AttesterSlashings)),
+		Attestations:      make([]*types.Attestation, len(parsedBlock.Message.Body.Attestations)),
+		Deposits:          make([]*types.Deposit, len(parsedBlock.Message.Body.Deposits)),
+		VoluntaryExits:    make([]*types.VoluntaryExit, len(parsedBlock.Message.Body.VoluntaryExits)),
+	}
+
+	epochAssignments, err := sc.GetEpochAssignments(slot / utils.Config.Chain.Config.SlotsPerEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	if agg := parsedBlock.Message.Body.SyncAggregate; agg != nil {
+		bits := utils.MustParseHex(agg.SyncCommitteeBits)
+
+		if utils.Config.Chain.Config.SyncCommitteeSize != uint64(len(bits)*8) {
+			return nil, fmt.Errorf("sync-aggregate-bits-size does not match sync-committee-size: %v != %v", len(bits)*8, utils.Config.Chain.Config.SyncCommitteeSize)
+		}
+
+		block.SyncAggregate = &types.SyncAggregate{
+			SyncCommitteeValidators:    epochAssignments.SyncAssignments,
+			SyncCommitteeBits:          bits,
+			SyncAggregateParticipation: syncCommitteeParticipation(bits),
+			SyncCommitteeSignature:     utils.MustParseHex(agg.SyncCommitteeSignature),
+		}
+	}
+
+	if payload := parsedBlock.Message.Body.ExecutionPayload; payload != nil && !bytes.Equal(payload.ParentHash, make([]byte, 32)) {
+		txs := make([]*types.Transaction, 0, len(payload.Transactions))
+		for i, rawTx := range payload.Transactions {
+			if isBlobTx(rawTx) {
+				tx, err := decodeBlobTx(rawTx)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing tx %d block %x: %w", i, payload.BlockHash, err)
+				}
+				txs = append(txs, tx)
+				continue
+			}
+			tx := &types.Transaction{Raw: rawTx}
+			var decTx gtypes.Transaction
+			if err := decTx.UnmarshalBinary(rawTx); err != nil {
+				return nil, fmt.Errorf("error parsing tx %d block %x: %w", i, payload.BlockHash, err)
+			} else {
+				h := decTx.Hash()
+				tx.TxHash = h[:]
+				tx.AccountNonce = decTx.Nonce()
+				// big endian
+				tx.Price = decTx.GasPrice().Bytes()
+				tx.GasLimit = decTx.Gas()
+				sender, err := sc.signer.Sender(&decTx)
+				if err != nil {
+					return nil, fmt.Errorf("transaction with invalid sender (tx hash: %x): %w", h, err)
+				}
+				tx.Sender = sender.Bytes()
+				if v := decTx.To(); v != nil {
+					tx.Recipient = v.Bytes()
+				} else {
+					tx.Recipient = []byte{}
+				}
+				tx.Amount = decTx.Value().Bytes()
+				tx.Payload = decTx.Data()
+				tx.MaxPriorityFeePerGas = decTx.GasTipCap().Uint64()
+				tx.MaxFeePerGas = decTx.GasFeeCap().Uint64()
+			}
+			txs = append(txs, tx)
+		}
+		block.ExecutionPayload = &types.ExecutionPayload{
+			ParentHash:    payload.ParentHash,
+			FeeRecipient:  payload.FeeRecipient,
+			StateRoot:     payload.StateRoot,
+			ReceiptsRoot:  payload.ReceiptsRoot,
+			LogsBloom:     payload.LogsBloom,
+			Random:        payload.PrevRandao,
+			BlockNumber:   uint64(payload.BlockNumber),
+			GasLimit:      uint64(payload.GasLimit),
+			GasUsed:       uint64(payload.GasUsed),
+			Timestamp:     uint64(payload.Timestamp),
+			ExtraData:     payload.ExtraData,
+			BaseFeePerGas: uint64(payload.BaseFeePerGas),
+			BlockHash:     payload.BlockHash,
+			Transactions:  txs,
+			BlobGasUsed:   uint64(payload.BlobGasUsed),
+			ExcessBlobGas: uint64(payload.ExcessBlobGas),
+		}
+
+		if payload.Withdrawals != nil {
+			block.ExecutionPayload.Withdrawals = make([]*types.Withdrawals, 0, len(payload.Withdrawals))
+			for _, w := range payload.Withdrawals {
+				block.ExecutionPayload.Withdrawals = append(block.ExecutionPayload.Withdrawals, &types.Withdrawals{
+					Slot:           block.Slot,
+					BlockRoot:      block.BlockRoot,
+					Index:          uint64(w.Index),
+					ValidatorIndex: uint64(w.ValidatorIndex),
+					Address:        w.Address,
+					Amount:         uint64(w.Amount),
+				})
+			}
+		}
+	}
+
+	block.SignedBLSToExecutionChange = make([]*types.SignedBLSToExecutionChange, len(parsedBlock.Message.Body.BlsToExecutionChanges))
+	for i, change := range parsedBlock.Message.Body.BlsToExecutionChanges {
+		block.SignedBLSToExecutionChange[i] = &types.SignedBLSToExecutionChange{
+			Message: types.BLSToExecutionChange{
+				Validatorindex: uint64(change.Message.ValidatorIndex),
+				BlsPubkey:      change.Message.FromBlsPubkey,
+				Address:        change.Message.ToExecutionAddress,
+			},
+			Signature: change.Signature,
+		}
+	}
+
+	block.BlobKZGCommitments = make([][]byte, len(parsedBlock.Message.Body.BlobKzgCommitments))
+	for i, commitment := range parsedBlock.Message.Body.BlobKzgCommitments {
+		block.BlobKZGCommitments[i] = commitment
+	}
+
+	// TODO: this is legacy from old lighthouse API. Does it even still apply?
+	if block.Eth1Data.DepositCount > 2147483647 { // Sometimes the lighthouse node does return bogus data for the DepositCount value
+		block.Eth1Data.DepositCount = 0
+	}
+
+	for i, proposerSlashing := range parsedBlock.Message.Body.ProposerSlashings {
+		block.ProposerSlashings[i] = &types.ProposerSlashing{
+			ProposerIndex: uint64(proposerSlashing.SignedHeader1.Message.ProposerIndex),
+			Header1: &types.Block{
+				Slot:       uint64(proposerSlashing.SignedHeader1.Message.Slot),
+				ParentRoot: utils.MustParseHex(proposerSlashing.SignedHeader1.Message.ParentRoot),
+				StateRoot:  utils.MustParseHex(proposerSlashing.SignedHeader1.Message.StateRoot),
+				Signature:  utils.MustParseHex(proposerSlashing.SignedHeader1.Signature),
+				BodyRoot:   utils.MustParseHex(proposerSlashing.SignedHeader1.Message.BodyRoot),
+			},
+			Header2: &types.Block{
+				Slot:       uint64(proposerSlashing.SignedHeader2.Message.Slot),
+				ParentRoot: utils.MustParseHex(proposerSlashing.SignedHeader2.Message.ParentRoot),
+				StateRoot:  utils.MustParseHex(proposerSlashing.SignedHeader2.Message.StateRoot),
+				Signature:  utils.MustParseHex(proposerSlashing.SignedHeader2.Signature),
+				BodyRoot:   utils.MustParseHex(proposerSlashing.SignedHeader2.Message.BodyRoot),
+			},
+		}
+	}
+
+	for i, attesterSlashing := range parsedBlock.Message.Body.AttesterSlashings {
+		block.AttesterSlashings[i] = &types.AttesterSlashing{
+			Attestation1: &types.IndexedAttestation{
+				Data: &types.AttestationData{
+					Slot:            uint64(attesterSlashing.Attestation1.Data.Slot),
+					CommitteeIndex:  uint64(attesterSlashing.Attestation1.Data.Index),
+					BeaconBlockRoot: utils.MustParseHex(attesterSlashing.Attestation1.Data.BeaconBlockRoot),
+					Source: &types.Checkpoint{
+						Epoch: uint64(attesterSlashing.Attestation1.Data.Source.Epoch),
+						Root:  utils.MustParseHex(attesterSlashing.Attestation1.Data.Source.Root),
+					},
+					Target: &types.Checkpoint{
+						Epoch: uint64(attesterSlashing.Attestation1.Data.Target.Epoch),
+						Root:  utils.MustParseHex(attesterSlashing.Attestation1.Data.Target.Root),
+					},
+				},
+				Signature:        utils.MustParseHex(attesterSlashing.Attestation1.Signature),
+				AttestingIndices: uint64List(attesterSlashing.Attestation1.AttestingIndices),
+			},
+			Attestation2: &types.IndexedAttestation{
+				Data: &types.AttestationData{
+					Slot:            uint64(attesterSlashing.Attestation2.Data.Slot),
+					CommitteeIndex:  uint64(attesterSlashing.Attestation2.Data.Index),
+					BeaconBlockRoot: utils.MustParseHex(attesterSlashing.Attestation2.Data.BeaconBlockRoot),
+					Source: &types.Checkpoint{
+						Epoch: uint64(attesterSlashing.Attestation2.Data.Source.Epoch),
+						Root:  utils.MustParseHex(attesterSlashing.Attestation2.Data.Source.Root),
+					},
+					Target: &types.Checkpoint{
+						Epoch: uint64(attesterSlashing.Attestation2.Data.Target.Epoch),
+						Root:  utils.MustParseHex(attesterSlashing.Attestation2.Data.Target.Root),
+					},
+				},
+				Signature:        utils.MustParseHex(attesterSlashing.Attestation2.Signature),
+				AttestingIndices: uint64List(attesterSlashing.Attestation2.AttestingIndices),
+			},
+		}
+	}
+
+	for i, attestation := range parsedBlock.Message.Body.Attestations {
+		a := &types.Attestation{
+			AggregationBits: utils.MustParseHex(attestation.AggregationBits),
+			Attesters:       []uint64{},
+			Data: &types.AttestationData{
+				Slot:            uint64(attestation.Data.Slot),
+				CommitteeIndex:  uint64(attestation.Data.Index),
+				BeaconBlockRoot: utils.MustParseHex(attestation.Data.BeaconBlockRoot),
+				Source: &types.Checkpoint{
+					Epoch: uint64(attestation.Data.Source.Epoch),
+					Root:  utils.MustParseHex(attestation.Data.Source.Root),
+				},
+				Target: &types.Checkpoint{
+					Epoch: uint64(attestation.Data.Target.Epoch),
+					Root:  utils.MustParseHex(attestation.Data.Target.Root),
+				},
+			},
+			Signature: utils.MustParseHex(attestation.Signature),
+		}
+
+		aggregationBits := bitfield.Bitlist(a.AggregationBits)
+		assignments, err := sc.GetEpochAssignments(a.Data.Slot / utils.Config.Chain.Config.SlotsPerEpoch)
+		if err != nil {
+			return nil, fmt.Errorf("error receiving epoch assignment for epoch %v: %w", a.Data.Slot/utils.Config.Chain.Config.SlotsPerEpoch, err)
+		}
+
+		for i := uint64(0); i < aggregationBits.Len(); i++ {
+			if aggregationBits.BitAt(i) {
+				validator, found := assignments.AttestorAssignments[utils.FormatAttestorAssignmentKey(a.Data.Slot, a.Data.CommitteeIndex, i)]
+				if !found { // This should never happen!
+					validator = 0
+					logger.Errorf("error retrieving assigned validator for attestation %v of block %v for slot %v committee index %v member index %v", i, block.Slot, a.Data.Slot, a.Data.CommitteeIndex, i)
+				}
+				a.Attesters = append(a.Attesters, validator)
+			}
+		}
+
+		block.Attestations[i] = a
+	}
+
+	for i, deposit := range parsedBlock.Message.Body.Deposits {
+		d := &types.Deposit{
+			Proof:                 nil,
+			PublicKey:             utils.MustParseHex(deposit.Data.Pubkey),
+			WithdrawalCredentials: utils.MustParseHex(deposit.Data.WithdrawalCredentials),
+			Amount:                uint64(deposit.Data.Amount),
+			Signature:             utils.MustParseHex(deposit.Data.Signature),
+		}
+
+		block.Deposits[i] = d
+	}
+
+	for i, voluntaryExit := range parsedBlock.Message.Body.VoluntaryExits {
+		block.VoluntaryExits[i] = &types.VoluntaryExit{
+			Epoch:          uint64(voluntaryExit.Message.Epoch),
+			ValidatorIndex: uint64(voluntaryExit.Message.ValidatorIndex),
+			Signature:      utils.MustParseHex(voluntaryExit.Signature),
+		}
+	}
+
+	return block, nil
+}
+
+func syncCommitteeParticipation(bits []byte) float64 {
+	participating := 0
+	for i := 0; i < int(utils.Config.Chain.Config.SyncCommitteeSize); i++ {
+		if utils.BitAtVector(bits, i) {
+			participating++
+		}
+	}
+	return float64(participating) / float64(utils.Config.Chain.Config.SyncCommitteeSize)
+}
+
+// GetValidatorParticipation will get the validator participation of an epoch
+func (sc *StandardClient) GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	return sc.participation(epoch)
+}
+
+// getStandardValidatorParticipation calculates the participation of an epoch from the attestation rewards of the
+// epoch, as the standard api does not offer participation data. A validator that missed the target is penalized, a
+// correct target vote is rewarded or, during an inactivity leak, neither rewarded nor penalized.
+func (sc *StandardClient) getStandardValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	startSlot := epoch * utils.Config.Chain.Config.SlotsPerEpoch
+
+	// the rewards of an epoch are known once the following epoch has ended
+	if utils.SlotToTime(startSlot + 2*utils.Config.Chain.Config.SlotsPerEpoch).After(time.Now()) {
+		return nil, fmt.Errorf("error participation of epoch %v is not available before epoch %v has ended", epoch, epoch+1)
+	}
+
+	validatorsResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators?status=active", sc.endpoint, startSlot))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving active validators for epoch %v: %w", epoch, err)
+	}
+	var parsedValidators StandardValidatorsResponse
+	err = json.Unmarshal(validatorsResp, &parsedValidators)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing active validators for epoch %v: %w", epoch, err)
+	}
+
+	effectiveBalances := make(map[uint64]uint64, len(parsedValidators.Data))
+	eligible := uint64(0)
+	for _, validator := range parsedValidators.Data {
+		effectiveBalances[uint64(validator.Index)] = uint64(validator.Validator.EffectiveBalance)
+		eligible += uint64(validator.Validator.EffectiveBalance)
+	}
+
+	// an empty list of validators requests the rewards of all validators
+	rewardsResp, err := sc.post(fmt.Sprintf("%s/eth/v1/beacon/rewards/attestations/%d", sc.endpoint, epoch), []byte("[]"))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestation rewards for epoch %v: %w", epoch, err)
+	}
+	var attestationRewards StandardAttestationRewardsResponse
+	err = json.Unmarshal(rewardsResp, &attestationRewards)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing attestation rewards for epoch %v: %w", epoch, err)
+	}
+
+	voted := make(map[uint64]bool)
+	for _, reward := range attestationRewards.Data.TotalRewards {
+		if int64(reward.Target) >= 0 {
+			voted[uint64(reward.ValidatorIndex)] = true
+		}
+	}
+
+	votedEther := uint64(0)
+	for validator := range voted {
+		votedEther += effectiveBalances[validator]
+	}
+
+	finalized := false
+	head, err := sc.GetChainHead()
+	if err != nil {
+		return nil, err
+	}
+	if epoch < head.FinalizedEpoch {
+		finalized = true
+	}
+
+	participationRate := float32(0)
+	if eligible > 0 {
+		participationRate = float32(votedEther) / float32(eligible)
+	}
+
+	return &types.ValidatorParticipation{
+		Epoch:                   epoch,
+		Finalized:               finalized,
+		GlobalParticipationRate: participationRate,
+		VotedEther:              votedEther,
+		EligibleEther:           eligible,
+	}, nil
+}
+
+func (sc *StandardClient) GetFinalityCheckpoints(epoch uint64) (*types.FinalityCheckpoints, error) {
+	// finalityResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%s/finality_checkpoints", sc.endpoint, id))
+	// if err != nil {
+	//      return nil, fmt.Errorf("error retrieving finality checkpoints of head: %w", err)
+	// }
+	return &types.FinalityCheckpoints{}, nil
+}
+
+func (sc *StandardClient) GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error) {
+	syncCommitteesResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%s/sync_committees?epoch=%d", sc.endpoint, stateID, epoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync_committees for epoch %v (state: %v): %w", epoch, stateID, err)
+	}
+	var parsedSyncCommittees StandardSyncCommitteesResponse
+	err = json.Unmarshal(syncCommitteesResp, &parsedSyncCommittees)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sync_committees data for epoch %v (state: %v): %w", epoch, stateID, err)
+	}
+	return &parsedSyncCommittees.Data, nil
+}
+
+// GetSlotData will get the slot data
+func (sc *StandardClient) GetSlotData(block *types.Block) (*types.SlotData, error) {
+	wg := &sync.WaitGroup{}
+	var err error
+
+	slot := utils.EpochOfSlot(block.Slot)
+	epoch := utils.EpochOfSlot(slot)
+	data := &types.SlotData{}
+	data.Epoch = epoch
+	data.Slot = slot
+
+	validatorsResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", sc.endpoint, slot))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators for slot %v: %w", slot, err)
+	}
+	var parsedValidators StandardValidatorsResponse
+	err = json.Unmarshal(validatorsResp, &parsedValidators)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing epoch validators: %w", err)
+	}
+
+	slot1d := int64(slot) - 7200
+	slot7d := int64(slot) - 7200*7
+	slot31d := int64(slot) - 7200*31
+
+	var validatorBalances1d map[uint64]uint64
+	var validatorBalances7d map[uint64]uint64
+	var validatorBalances31d map[uint64]uint64
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		validatorBalances1d, err = sc.GetBalancesForSlot(slot1d)
+		if err != nil {
+			logrus.Errorf("error retrieving validator balances for slot %v (1d): %v", slot1d, err)
+			return
+		}
+		logger.Printf("retrieved data for %v validator balances for slot %v (1d) took %v", len(parsedValidators.Data), slot1d, time.Since(start))
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		validatorBalances7d, err = sc.GetBalancesForSlot(slot7d)
+		if err != nil {
+			logrus.Errorf("error retrieving validator balances for slot %v (7d): %v", slot7d, err)
+			return
+		}
+		logger.Printf("retrieved data for %v validator balances for slot %v (7d) took %v", len(parsedValidators.Data), slot7d, time.Since(start))
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		validatorBalances31d, err = sc.GetBalancesForSlot(slot31d)
+		if err != nil {
+			logrus.Errorf("error retrieving validator balances for slot %v (31d): %v", slot31d, err)
+			return
+		}
+		logger.Printf("retrieved data for %v validator balances for slot %v (31d) took %v", len(parsedValidators.Data), slot31d, time.Since(start))
+	}()
+	wg.Wait()
+
+	// Retrieve a block for the slot
+	data.Blocks = make(map[uint64]map[string]*types.Block)
+	if data.Blocks[block.Slot] == nil {
+		data.Blocks[block.Slot] = make(map[string]*types.Block)
+	}
+	data.Blocks[block.Slot][fmt.Sprintf("%x", block.BlockRoot)] = block
+	logger.Printf("retrieved a block for slot %v", slot)
+
+	// Retrieve the validator set for the slot
+	data.Validators = make([]*types.Validator, 0)
+
+	for _, validator := range parsedValidators.Data {
+		data.Validators = append(data.Validators, &types.Validator{
+			Index:                      uint64(validator.Index),
+			PublicKey:                  utils.MustParseHex(validator.Validator.Pubkey),
+			WithdrawalCredentials:      utils.MustParseHex(validator.Validator.WithdrawalCredentials),
+			Balance:                    uint64(validator.Balance),
+			EffectiveBalance:           uint64(validator.Validator.EffectiveBalance),
+			Slashed:                    validator.Validator.Slashed,
+			ActivationEligibilityEpoch: uint64(validator.Validator.ActivationEligibilityEpoch),
+			ActivationEpoch:            uint64(validator.Validator.ActivationEpoch),
+			ExitEpoch:                  uint64(validator.Validator.ExitEpoch),
+			WithdrawableEpoch:          uint64(validator.Validator.WithdrawableEpoch),
+			Balance1d:                  validatorBalances1d[uint64(validator.Index)],
+			Balance7d:                  validatorBalances7d[uint64(validator.Index)],
+			Balance31d:                 validatorBalances31d[uint64(validator.Index)],
+			Status:                     validator.Status,
+		})
+	}
+
+	logger.Printf("retrieved data for %v validators for slot %v", len(data.Validators), slot)
+
+	return data, nil
+}
+
+func (sc *StandardClient) GetBalancesForSlot(slot int64) (map[uint64]uint64, error) {
+	if slot < 0 {
+		slot = 0
+	}
+
+	var err error
+
+	validatorBalances := make(map[uint64]uint64)
+
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validator_balances", sc.endpoint, slot))
+	if err != nil {
+		return validatorBalances, err
+	}
+
+	var parsedResponse StandardValidatorBalancesResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response for validator_balances")
+	}
+
+	for _, b := range parsedResponse.Data {
+		validatorBalances[uint64(b.Index)] = uint64(b.Balance)
+	}
+
+	return validatorBalances, nil
+}
+
+// GetBlobSidecars will get the blob sidecars of a block, sidecars are pruned by the node after MIN_EPOCHS_FOR_BLOB_SIDECARS_REQUESTS
+func (sc *StandardClient) GetBlobSidecars(block *types.Block) ([]*types.BlobSidecar, error) {
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/blob_sidecars/%#x", sc.endpoint, block.BlockRoot))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving blob sidecars of block %#x at slot %v: %w", block.BlockRoot, block.Slot, err)
+	}
+
+	var parsedResponse StandardBlobSidecarsResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing blob sidecars of block %#x at slot %v: %w", block.BlockRoot, block.Slot, err)
+	}
+
+	sidecars := make([]*types.BlobSidecar, 0, len(parsedResponse.Data))
+	for _, sidecar := range parsedResponse.Data {
+		sidecars = append(sidecars, &types.BlobSidecar{
+			BlockSlot:     block.Slot,
+			BlockRoot:     block.BlockRoot,
+			Index:         uint64(sidecar.Index),
+			KZGCommitment: sidecar.KzgCommitment,
+			KZGProof:      sidecar.KzgProof,
+			Size:          uint64(utils.BlobUsedSize(sidecar.Blob)),
+		})
+	}
+	return sidecars, nil
+}
+
+// GetEpochRewards will get the attestation, sync committee and proposer rewards of all validators in an epoch.
+// Attestation rewards of an epoch are only available once the following epoch has been processed.
+func (sc *StandardClient) GetEpochRewards(epoch uint64) (map[uint64]*types.ValidatorRewards, error) {
+	rewards := make(map[uint64]*types.ValidatorRewards)
+	validatorRewards := func(index uint64) *types.ValidatorRewards {
+		r, exists := rewards[index]
+		if !exists {
+			r = &types.ValidatorRewards{ValidatorIndex: index, Epoch: epoch}
+			rewards[index] = r
+		}
+		return r
+	}
+
+	// an empty list of validators requests the rewards of all validators
+	resp, err := sc.post(fmt.Sprintf("%s/eth/v1/beacon/rewards/attestations/%d", sc.endpoint, epoch), []byte("[]"))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestation rewards for epoch %v: %w", epoch, err)
+	}
+	var attestationRewards StandardAttestationRewardsResponse
+	err = json.Unmarshal(resp, &attestationRewards)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing attestation rewards for epoch %v: %w", epoch, err)
+	}
+	for _, reward := range attestationRewards.Data.TotalRewards {
+		r := validatorRewards(uint64(reward.ValidatorIndex))
+		r.Source = int64(reward.Source)
+		r.Target = int64(reward.Target)
+		r.Head = int64(reward.Head)
+		r.InclusionDelay = int64(reward.InclusionDelay)
+		r.Inactivity = int64(reward.Inactivity)
+	}
+
+	syncCommitteesActive := utils.ForkFeatureActive(utils.ForkFeatureSyncCommittees, epoch)
+	for slot := epoch * utils.Config.Chain.Config.SlotsPerEpoch; slot < (epoch+1)*utils.Config.Chain.Config.SlotsPerEpoch; slot++ {
+		resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/rewards/blocks/%d", sc.endpoint, slot))
+		if err == notFoundErr {
+			// missed slot, there are no proposer or sync committee rewards
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving block rewards for slot %v: %w", slot, err)
+		}
+		var blockRewards StandardBlockRewardsResponse
+		err = json.Unmarshal(resp, &blockRewards)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing block rewards for slot %v: %w", slot, err)
+		}
+		r := validatorRewards(uint64(blockRewards.Data.ProposerIndex))
+		r.ProposerAttestations += int64(blockRewards.Data.Attestations)
+		r.ProposerSyncAggregate += int64(blockRewards.Data.SyncAggregate)
+		r.ProposerSlashingInclusion += int64(blockRewards.Data.ProposerSlashings) + int64(blockRewards.Data.AttesterSlashings)
+
+		if !syncCommitteesActive {
+			continue
+		}
+		resp, err = sc.post(fmt.Sprintf("%s/eth/v1/beacon/rewards/sync_committee/%d", sc.endpoint, slot), []byte("[]"))
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving sync committee rewards for slot %v: %w", slot, err)
+		}
+		var syncRewards StandardSyncCommitteeRewardsResponse
+		err = json.Unmarshal(resp, &syncRewards)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sync committee rewards for slot %v: %w", slot, err)
+		}
+		for _, reward := range syncRewards.Data {
+			validatorRewards(uint64(reward.ValidatorIndex)).SyncCommittee += int64(reward.Reward)
+		}
+	}
+
+	return rewards, nil
+}
+
+var notFoundErr = errors.New("not found 404")
+
+// nodeErr marks errors caused by the beacon-node rather than by the request: transport errors, timeouts and 5xx responses
+type nodeErr struct {
+	err error
+}
+
+func (e *nodeErr) Error() string {
+	return e.err.Error()
+}
+
+func (e *nodeErr) Unwrap() error {
+	return e.err
+}
+
+// GetBLSChangePool will get the bls to execution changes that are waiting in the operation pool of the node
+func (sc *StandardClient) GetBLSChangePool() ([]*types.SignedBLSToExecutionChange, error) {
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/pool/bls_to_execution_changes", sc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving bls to execution change pool: %w", err)
+	}
+
+	var parsedResponse StandardBLSChangePoolResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing bls to execution change pool: %w", err)
+	}
+
+	changes := make([]*types.SignedBLSToExecutionChange, 0, len(parsedResponse.Data))
+	for _, change := range parsedResponse.Data {
+		changes = append(changes, &types.SignedBLSToExecutionChange{
+			Message: types.BLSToExecutionChange{
+				Validatorindex: uint64(change.Message.ValidatorIndex),
+				BlsPubkey:      change.Message.FromBlsPubkey,
+				Address:        change.Message.ToExecutionAddress,
+			},
+			Signature: change.Signature,
+		})
+	}
+	return changes, nil
+}
+
+// GetGenesisValidatorsRoot will get the genesis validators root of the chain
+func (sc *StandardClient) GetGenesisValidatorsRoot() ([]byte, error) {
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/genesis", sc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving genesis: %w", err)
+	}
+
+	var parsedResponse StandardGenesisResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing genesis: %w", err)
+	}
+	return parsedResponse.Data.GenesisValidatorsRoot, nil
+}
+
+// GetProposerDuties will get the proposer duties of an epoch, duties of the next epoch can still change until the
+// dependent block is finalized
+func (sc *StandardClient) GetProposerDuties(epoch uint64) ([]*types.ProposerDuty, error) {
+	resp, err := sc.get(fmt.Sprintf("%s/eth/v1/validator/duties/proposer/%d", sc.endpoint, epoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving proposer duties of epoch %v: %w", epoch, err)
+	}
+
+	var parsedResponse StandardProposerDutiesResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing proposer duties of epoch %v: %w", epoch, err)
+	}
+
+	dependentRoot, err := hex.DecodeString(strings.TrimPrefix(parsedResponse.DependentRoot, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing dependent root of proposer duties of epoch %v: %w", epoch, err)
+	}
+
+	duties := make([]*types.ProposerDuty, 0, len(parsedResponse.Data))
+	for _, duty := range parsedResponse.Data {
+		duties = append(duties, &types.ProposerDuty{
+			Slot:           uint64(duty.Slot),
+			Validatorindex: uint64(duty.ValidatorIndex),
+			DependentRoot:  dependentRoot,
+		})
+	}
+	return duties, nil
+}
+
+func (sc *StandardClient) get(url string) ([]byte, error) {
+	// t0 := time.Now()
+	// defer func() { fmt.Println(url, time.Since(t0)) }()
+	client := &http.Client{Timeout: time.Second * 120}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, &nodeErr{err}
+	}
+
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, notFoundErr
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &nodeErr{fmt.Errorf("error-response: %s", data)}
+		}
+		return nil, fmt.Errorf("error-response: %s", data)
+	}
+	if err != nil {
+		return nil, &nodeErr{err}
+	}
+
+	return data, err
+}
+
+func (sc *StandardClient) post(url string, body []byte) ([]byte, error) {
+	client := &http.Client{Timeout: time.Second * 120}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, &nodeErr{err}
+	}
+
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, notFoundErr
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &nodeErr{fmt.Errorf("error-response: %s", data)}
+		}
+		return nil, fmt.Errorf("error-response: %s", data)
+	}
+	if err != nil {
+		return nil, &nodeErr{err}
+	}
+
+	return data, err
+}
+
+// getPreferSSZ requests a resource ssz encoded, falling back to json if the node does not offer ssz.
+// It returns whether the response is ssz encoded together with its consensus version.
+func (sc *StandardClient) getPreferSSZ(url string) ([]byte, bool, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, false, "", err
+	}
+	req.Header.Set("Accept", "application/octet-stream;q=1.0,application/json;q=0.9")
+
+	client := &http.Client{Timeout: time.Second * 120}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, "", &nodeErr{err}
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, "", &nodeErr{err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, false, "", notFoundErr
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, false, "", &nodeErr{fmt.Errorf("error-response: %s", data)}
+		}
+		return nil, false, "", fmt.Errorf("error-response: %s", data)
+	}
+
+	isSSZ := strings.HasPrefix(resp.Header.Get("Content-Type"), "application/octet-stream")
+	return data, isSSZ, resp.Header.Get("Eth-Consensus-Version"), nil
+}
@@ -0,0 +1,366 @@
+package rpc
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFinalizedCheckpointEvent(epoch uint64) string {
+	return fmt.Sprintf(`{"block":"%v","state":"%v","epoch":"%v","execution_optimistic":false}`, testRoot(epoch*4), testRoot(epoch*4+1000), epoch)
+}
+
+func TestStreamEvents(t *testing.T) {
+	reorgEvent := fmt.Sprintf(`{"slot":"10","depth":"1","old_head_block":"%v","new_head_block":"%v","old_head_state":"%v","new_head_state":"%v","epoch":"2","execution_optimistic":false}`,
+		testRoot(109), testRoot(10), testRoot(1109), testRoot(1010))
+	headEvent := fmt.Sprintf(`{"slot":"10","block":"%v","state":"%v","epoch_transition":false}`, testRoot(10), testRoot(1010))
+
+	tests := []struct {
+		name          string
+		stream        sseStream
+		wantErr       string
+		wantReorgs    []uint64
+		wantFinalized []uint64
+	}{
+		{
+			name: "chain events",
+			stream: sseStream{status: http.StatusOK, body: ": keep-alive\n\n" +
+				"event: head\ndata: " + headEvent + "\n\n" +
+				"event: chain_reorg\ndata: " + reorgEvent + "\n\n" +
+				"event: finalized_checkpoint\ndata: " + testFinalizedCheckpointEvent(2) + "\n\n"},
+			wantErr:       "event stream closed by node",
+			wantReorgs:    []uint64{10},
+			wantFinalized: []uint64{2},
+		},
+		{
+			name:          "crlf line endings",
+			stream:        sseStream{status: http.StatusOK, body: "event: finalized_checkpoint\r\ndata: " + testFinalizedCheckpointEvent(3) + "\r\n\r\n"},
+			wantErr:       "event stream closed by node",
+			wantFinalized: []uint64{3},
+		},
+		{
+			name: "data split over several lines",
+			stream: sseStream{status: http.StatusOK, body: fmt.Sprintf("event: finalized_checkpoint\ndata: {\"block\":\"%v\",\ndata: \"state\":\"%v\",\ndata: \"epoch\":\"4\"}\n\n",
+				testRoot(16), testRoot(1016))},
+			wantErr:       "event stream closed by node",
+			wantFinalized: []uint64{4},
+		},
+		{
+			name:    "event is only dispatched by an empty line",
+			stream:  sseStream{status: http.StatusOK, body: "event: finalized_checkpoint\ndata: " + testFinalizedCheckpointEvent(2) + "\n"},
+			wantErr: "event stream closed by node",
+		},
+		{
+			name: "malformed event is skipped",
+			stream: sseStream{status: http.StatusOK, body: "event: chain_reorg\ndata: {\"slot\":\n\n" +
+				"event: finalized_checkpoint\ndata: " + testFinalizedCheckpointEvent(2) + "\n\n"},
+			wantErr:       "event stream closed by node",
+			wantFinalized: []uint64{2},
+		},
+		{
+			name:    "error response",
+			stream:  sseStream{status: http.StatusServiceUnavailable, body: "node is syncing"},
+			wantErr: "node is syncing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := newBeaconStandIn(15)
+			standIn.streams = []sseStream{tt.stream}
+			srv := standIn.start(t)
+			client := newStandardClient(srv.URL, big.NewInt(1))
+
+			lastHeadSlot := uint64(15)
+			err := client.streamEvents(make(chan *types.Block, 10), &lastHeadSlot)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if standIn.eventAccept != "text/event-stream" || standIn.eventQuery != "topics=head,block,chain_reorg,finalized_checkpoint" {
+				t.Errorf("unexpected subscription %q %q", standIn.eventAccept, standIn.eventQuery)
+			}
+
+			var reorgs, finalized []uint64
+			for len(client.chainEventCh) > 0 {
+				event := <-client.chainEventCh
+				if event.Reorg != nil {
+					reorgs = append(reorgs, event.Reorg.Slot)
+					if fmt.Sprintf("0x%x", event.Reorg.NewHeadBlock) != testRoot(10) || fmt.Sprintf("0x%x", event.Reorg.OldHeadBlock) != testRoot(109) {
+						t.Errorf("unexpected reorg %+v", event.Reorg)
+					}
+				}
+				if event.Finalized != nil {
+					finalized = append(finalized, event.Finalized.Epoch)
+					if fmt.Sprintf("0x%x", event.Finalized.Block) != testRoot(event.Finalized.Epoch*4) {
+						t.Errorf("unexpected finalized checkpoint %+v", event.Finalized)
+					}
+				}
+			}
+			if fmt.Sprint(reorgs) != fmt.Sprint(tt.wantReorgs) || fmt.Sprint(finalized) != fmt.Sprint(tt.wantFinalized) {
+				t.Errorf("expected reorgs %v and finalized epochs %v, got %v and %v", tt.wantReorgs, tt.wantFinalized, reorgs, finalized)
+			}
+		})
+	}
+}
+
+func TestStreamEventsSlowBlockFetch(t *testing.T) {
+	blockEvent := fmt.Sprintf(`{"slot":"16","block":"%v","execution_optimistic":false}`, testRoot(16))
+
+	standIn := newBeaconStandIn(15)
+	standIn.headerDelay = time.Millisecond * 1500
+	standIn.streams = []sseStream{{status: http.StatusOK, body: "event: block\ndata: " + blockEvent + "\n\n" +
+		"event: finalized_checkpoint\ndata: " + testFinalizedCheckpointEvent(3) + "\n\n"}}
+	srv := standIn.start(t)
+	client := newStandardClient(srv.URL, big.NewInt(1))
+
+	done := make(chan error)
+	go func() {
+		lastHeadSlot := uint64(15)
+		done <- client.streamEvents(make(chan *types.Block), &lastHeadSlot)
+	}()
+
+	// the finalized checkpoint is read while the block of the previous event is still being fetched
+	select {
+	case event := <-client.chainEventCh:
+		if event.Finalized == nil || event.Finalized.Epoch != 3 {
+			t.Errorf("unexpected chain event %+v", event)
+		}
+	case <-time.After(time.Millisecond * 750):
+		t.Fatalf("reading the stream is held up by fetching a block")
+	}
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "event stream closed by node") {
+			t.Errorf("expected the stream to be closed by the node, got %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("the stream did not return after the block was fetched")
+	}
+	if standIn.requestCount("/eth/v1/beacon/headers/"+testRoot(16)) != 1 {
+		t.Errorf("expected the block of the block event to be fetched")
+	}
+}
+
+func TestPollNewBlocks(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastHeadSlot uint64
+		failing      bool
+		wantHeadSlot uint64
+		wantSlots    []uint64
+	}{
+		{
+			name:         "missed slots are fetched",
+			lastHeadSlot: 12,
+			wantHeadSlot: 15,
+			wantSlots:    []uint64{13, 14, 15},
+		},
+		{
+			name:         "node behind the last head",
+			lastHeadSlot: 17,
+			wantHeadSlot: 17,
+		},
+		{
+			name:         "failing node",
+			lastHeadSlot: 12,
+			failing:      true,
+			wantHeadSlot: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := newBeaconStandIn(15)
+			standIn.failing = tt.failing
+			srv := standIn.start(t)
+			client := newStandardClient(srv.URL, big.NewInt(1))
+
+			lastHeadSlot := tt.lastHeadSlot
+			client.pollNewBlocks(make(chan *types.Block, 10), &lastHeadSlot, time.Millisecond*600)
+
+			if lastHeadSlot != tt.wantHeadSlot {
+				t.Errorf("expected head slot %v, got %v", tt.wantHeadSlot, lastHeadSlot)
+			}
+			if standIn.requestCount("/eth/v1/node/syncing") == 0 {
+				t.Errorf("expected the sync status to be polled")
+			}
+			for slot := uint64(0); slot <= 20; slot++ {
+				want := 0
+				for _, s := range tt.wantSlots {
+					if s == slot {
+						want = 1
+					}
+				}
+				if got := standIn.requestCount(fmt.Sprintf("/eth/v1/beacon/headers/%d", slot)); got != want {
+					t.Errorf("expected %v requests for the block of slot %v, got %v", want, slot, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEventStreamReconnect(t *testing.T) {
+	standIn := newBeaconStandIn(15)
+	standIn.syncHeadSlot = 17
+	standIn.streams = []sseStream{
+		{status: http.StatusServiceUnavailable, body: "node is starting"},
+		{status: http.StatusOK, body: "event: finalized_checkpoint\ndata: " + testFinalizedCheckpointEvent(3) + "\n\n"},
+	}
+	srv := standIn.start(t)
+	client := newStandardClient(srv.URL, big.NewInt(1))
+
+	client.GetNewBlockChan()
+
+	select {
+	case event := <-client.GetChainEventChan():
+		if event.Finalized == nil || event.Finalized.Epoch != 3 {
+			t.Errorf("unexpected chain event %+v", event)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected a chain event after reconnecting to the event stream")
+	}
+
+	standIn.mux.Lock()
+	streamCount := standIn.streamCount
+	standIn.mux.Unlock()
+	if streamCount != 2 {
+		t.Errorf("expected 2 subscriptions, got %v", streamCount)
+	}
+	// the slots that passed while the stream was down are fetched by polling
+	for _, slot := range []uint64{16, 17} {
+		if standIn.requestCount(fmt.Sprintf("/eth/v1/beacon/headers/%d", slot)) != 1 {
+			t.Errorf("expected the block of slot %v to be fetched while polling", slot)
+		}
+	}
+}
+
+type testCanonBlock struct {
+	slot      uint64
+	id        uint64
+	canonical bool
+}
+
+func TestGetBlockStatusByEpoch(t *testing.T) {
+	tests := []struct {
+		name    string
+		epoch   uint64
+		modify  func(s *beaconStandIn)
+		want    []testCanonBlock
+		wantErr string
+	}{
+		{
+			name:  "finalized epoch with a skipped slot",
+			epoch: 1,
+			want:  []testCanonBlock{{4, 4, true}, {5, 5, true}, {7, 7, true}},
+		},
+		{
+			name:  "justified epoch with an orphaned block",
+			epoch: 2,
+			want:  []testCanonBlock{{8, 8, true}, {9, 9, true}, {9, 109, false}, {10, 10, true}, {11, 11, true}},
+		},
+		{
+			name:  "epoch after the justified checkpoint",
+			epoch: 3,
+			want:  []testCanonBlock{{12, 12, true}, {13, 13, true}, {14, 14, true}, {15, 15, true}},
+		},
+		{
+			name:  "orphaned head",
+			epoch: 2,
+			modify: func(s *beaconStandIn) {
+				s.head = testRoot(109)
+				s.justifiedEpoch = 2
+			},
+			want: []testCanonBlock{{8, 8, true}, {9, 9, false}, {9, 109, true}, {10, 10, false}, {11, 11, false}},
+		},
+		{
+			name:   "canonical block missing from the headers of its slot",
+			epoch:  1,
+			modify: func(s *beaconStandIn) { s.hidden[testRoot(5)] = true },
+			want:   []testCanonBlock{{4, 4, true}, {5, 5, true}, {7, 7, true}},
+		},
+		{
+			name:  "checkpoint-synced node",
+			epoch: 1,
+			modify: func(s *beaconStandIn) {
+				s.pruned[testRoot(3)] = true
+				s.pruned[testRoot(4)] = true
+			},
+			want: []testCanonBlock{{5, 5, true}, {7, 7, true}},
+		},
+		{
+			name:    "failing parent lookup",
+			epoch:   1,
+			modify:  func(s *beaconStandIn) { s.broken[testRoot(4)] = true },
+			wantErr: "error retrieving parent header",
+		},
+		{
+			name:    "failing node",
+			epoch:   1,
+			modify:  func(s *beaconStandIn) { s.failing = true },
+			wantErr: "error retrieving chain head",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := newBeaconStandIn(15)
+			if tt.modify != nil {
+				tt.modify(standIn)
+			}
+			srv := standIn.start(t)
+			client := newStandardClient(srv.URL, big.NewInt(1))
+
+			blocks, err := client.GetBlockStatusByEpoch(tt.epoch)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]testCanonBlock, 0, len(blocks))
+			for _, block := range blocks {
+				id, err := strconv.ParseUint(fmt.Sprintf("%x", block.BlockRoot), 16, 64)
+				if err != nil {
+					t.Fatalf("unexpected block root %#x", block.BlockRoot)
+				}
+				got = append(got, testCanonBlock{block.Slot, id, block.Canonical})
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetBlockStatusByEpochReusesCanonicalWalk(t *testing.T) {
+	standIn := newBeaconStandIn(15)
+	srv := standIn.start(t)
+	client := newStandardClient(srv.URL, big.NewInt(1))
+
+	for _, epoch := range []uint64{1, 0} {
+		_, err := client.GetBlockStatusByEpoch(epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the walk of epoch 0 continues where the walk of epoch 1 stopped instead of starting again at the checkpoint
+	if got := standIn.requestCount("/eth/v1/beacon/headers/" + standIn.finalizedRoot); got != 1 {
+		t.Errorf("expected the finalized checkpoint to be fetched once, got %v", got)
+	}
+	for _, id := range []uint64{7, 5, 4} {
+		if got := standIn.requestCount("/eth/v1/beacon/headers/" + testRoot(id)); got != 1 {
+			t.Errorf("expected the header of block %v to be fetched once, got %v", id, got)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package rpc
+
+import (
+	"encoding/json"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.Config.SlotsPerEpoch = 4
+	utils.Config.Chain.Config.SecondsPerSlot = 1
+	utils.Config.Chain.Config.SyncCommitteeSize = 32
+	utils.Config.Chain.GenesisTimestamp = uint64(time.Now().Unix()) - 1000
+	// set before any client starts polling, the polling goroutines of GetNewBlockChan never stop
+	eventStreamRetryInterval = time.Millisecond * 600
+	os.Exit(m.Run())
+}
+
+func testRoot(id uint64) string {
+	return fmt.Sprintf("0x%064x", id)
+}
+
+// sseStream is the answer of the stand-in to a single subscription to the event stream
+type sseStream struct {
+	status int
+	body   string
+}
+
+// beaconStandIn is a local stand-in for a beacon-node that serves the headers of a chain, the finality checkpoints and
+// a scripted event stream
+type beaconStandIn struct {
+	mux sync.Mutex
+
+	headers        []StandardBeaconHeader
+	head           string
+	finalizedEpoch uint64
+	finalizedRoot  string
+	justifiedEpoch uint64
+	justifiedRoot  string
+	syncHeadSlot   uint64
+
+	// hidden blocks are known to the node but missing from the headers of their slot
+	hidden map[string]bool
+	// pruned blocks are unknown to the node
+	pruned map[string]bool
+	// broken blocks are answered with an internal error
+	broken map[string]bool
+	// a failing node answers every request with an internal error, or with failStatus if it is set
+	failing    bool
+	failStatus int
+	// delay is added to every chain head request
+	delay time.Duration
+	// headerDelay is added to every request of a header by root or slot
+	headerDelay time.Duration
+
+	streams     []sseStream
+	streamCount int
+	eventAccept string
+	eventQuery  string
+	requests    map[string]int
+}
+
+// newBeaconStandIn creates a stand-in with a chain from genesis up to headSlot, the block of every slot uses the slot as
+// id of its root. Slot 6 is skipped and slot 9 has an orphaned sibling with id 109. The finalized checkpoint is at epoch 2
+// and the justified checkpoint at epoch 3.
+func newBeaconStandIn(headSlot uint64) *beaconStandIn {
+	s := &beaconStandIn{
+		hidden:   map[string]bool{},
+		pruned:   map[string]bool{},
+		broken:   map[string]bool{},
+		requests: map[string]int{},
+	}
+	parent := uint64(0)
+	for slot := uint64(0); slot <= headSlot; slot++ {
+		if slot == 6 {
+			continue
+		}
+		s.addBlock(slot, slot, parent)
+		if slot == 9 {
+			s.addBlock(9, 109, 8)
+		}
+		parent = slot
+	}
+	s.head = testRoot(headSlot)
+	s.finalizedEpoch = 2
+	s.finalizedRoot = testRoot(2 * utils.Config.Chain.Config.SlotsPerEpoch)
+	s.justifiedEpoch = 3
+	s.justifiedRoot = testRoot(3 * utils.Config.Chain.Config.SlotsPerEpoch)
+	s.syncHeadSlot = headSlot
+	return s
+}
+
+func (s *beaconStandIn) addBlock(slot, id, parent uint64) {
+	header := StandardBeaconHeader{Root: testRoot(id)}
+	header.Header.Message.Slot = uint64Str(slot)
+	header.Header.Message.ParentRoot = testRoot(parent)
+	header.Header.Message.StateRoot = testRoot(id + 1000)
+	s.headers = append(s.headers, header)
+}
+
+func (s *beaconStandIn) header(root string) *StandardBeaconHeader {
+	for i := range s.headers {
+		if s.headers[i].Root == root {
+			return &s.headers[i]
+		}
+	}
+	return nil
+}
+
+// canonicalRootAt returns the root of the canonical block at the slot or of the last canonical block before it
+func (s *beaconStandIn) canonicalRootAt(slot uint64) string {
+	current := s.header(s.head)
+	for current != nil && uint64(current.Header.Message.Slot) > slot {
+		current = s.header(current.Header.Message.ParentRoot)
+	}
+	if current == nil {
+		return ""
+	}
+	return current.Root
+}
+
+func (s *beaconStandIn) requestCount(path string) int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.requests[path]
+}
+
+func (s *beaconStandIn) setFailing(failing bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.failing = failing
+}
+
+func (s *beaconStandIn) start(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (s *beaconStandIn) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	path := r.URL.Path
+	if r.URL.RawQuery != "" && path != "/eth/v1/events" {
+		path += "?" + r.URL.RawQuery
+	}
+	s.requests[path]++
+
+	if s.failing {
+		if s.failStatus != 0 {
+			http.Error(w, http.StatusText(s.failStatus), s.failStatus)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/eth/v1/events":
+		s.eventAccept = r.Header.Get("Accept")
+		s.eventQuery = r.URL.RawQuery
+		if s.streamCount >= len(s.streams) {
+			http.Error(w, "event stream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		stream := s.streams[s.streamCount]
+		s.streamCount++
+		if stream.status != http.StatusOK {
+			http.Error(w, stream.body, stream.status)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(stream.body))
+	case r.URL.Path == "/eth/v1/node/syncing":
+		var res StandardSyncingResponse
+		res.Data.HeadSlot = uint64Str(s.syncHeadSlot)
+		json.NewEncoder(w).Encode(res)
+	case r.URL.Path == "/eth/v1/beacon/headers":
+		slot, err := strconv.ParseUint(r.URL.Query().Get("slot"), 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		res := StandardBeaconHeadersResponse{Data: []StandardBeaconHeader{}}
+		for _, header := range s.headers {
+			if uint64(header.Header.Message.Slot) == slot && !s.hidden[header.Root] && !s.pruned[header.Root] {
+				res.Data = append(res.Data, header)
+			}
+		}
+		json.NewEncoder(w).Encode(res)
+	case len(parts) == 5 && parts[3] == "headers":
+		root := parts[4]
+		if root == "head" {
+			time.Sleep(s.delay)
+			root = s.head
+		} else {
+			time.Sleep(s.headerDelay)
+		}
+		header := s.header(root)
+		switch {
+		case s.broken[root]:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		case header == nil || s.pruned[root]:
+			http.NotFound(w, r)
+		default:
+			json.NewEncoder(w).Encode(StandardBeaconHeaderResponse{Data: *header})
+		}
+	case len(parts) == 6 && parts[3] == "states" && parts[5] == "finality_checkpoints":
+		var res StandardFinalityCheckpointsResponse
+		res.Data.Finalized.Epoch = uint64Str(s.finalizedEpoch)
+		res.Data.Finalized.Root = s.finalizedRoot
+		res.Data.CurrentJustified.Epoch = uint64Str(s.justifiedEpoch)
+		res.Data.CurrentJustified.Root = s.justifiedRoot
+		res.Data.PreviousJustified.Epoch = uint64Str(s.finalizedEpoch)
+		res.Data.PreviousJustified.Root = s.finalizedRoot
+		json.NewEncoder(w).Encode(res)
+	case len(parts) == 6 && parts[3] == "blocks" && parts[5] == "root":
+		slot, err := strconv.ParseUint(parts[4], 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var res StandardV1BlockRootResponse
+		res.Data.Root = s.canonicalRootAt(slot)
+		json.NewEncoder(w).Encode(res)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -3,7 +3,6 @@ package rpc
 import (
 	"encoding/hex"
 	"errors"
-	"eth2-exporter/types"
 	"fmt"
 	"strconv"
 )
@@ -51,21 +50,42 @@ func Uint64Unmarshal(v *uint64, b []byte) error {
 	return nil
 }
 
+// int64Str is a signed amount encoded as a decimal string, as used for rewards and penalties
+type int64Str int64
+
+func (s *int64Str) UnmarshalJSON(b []byte) error {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		b = b[1 : len(b)-1]
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return err
+	}
+	*s = int64Str(n)
+	return nil
+}
+
+type StandardBeaconHeader struct {
+	Root      string `json:"root"`
+	Canonical bool   `json:"canonical"`
+	Header    struct {
+		Message struct {
+			Slot          uint64Str `json:"slot"`
+			ProposerIndex uint64Str `json:"proposer_index"`
+			ParentRoot    string    `json:"parent_root"`
+			StateRoot     string    `json:"state_root"`
+			BodyRoot      string    `json:"body_root"`
+		} `json:"message"`
+		Signature string `json:"signature"`
+	} `json:"header"`
+}
+
 type StandardBeaconHeaderResponse struct {
-	Data struct {
-		Root      string `json:"root"`
-		Canonical bool   `json:"canonical"`
-		Header    struct {
-			Message struct {
-				Slot          uint64Str `json:"slot"`
-				ProposerIndex uint64Str `json:"proposer_index"`
-				ParentRoot    string    `json:"parent_root"`
-				StateRoot     string    `json:"state_root"`
-				BodyRoot      string    `json:"body_root"`
-			} `json:"message"`
-			Signature string `json:"signature"`
-		} `json:"header"`
-	} `json:"data"`
+	Data StandardBeaconHeader `json:"data"`
+}
+
+type StandardBeaconHeadersResponse struct {
+	Data []StandardBeaconHeader `json:"data"`
 }
 
 type StandardFinalityCheckpointsResponse struct {
@@ -125,82 +145,50 @@ type LighthouseValidatorParticipationResponse struct {
 	} `json:"data"`
 }
 
+type SignedBeaconBlockHeader struct {
+	Message struct {
+		Slot          uint64Str `json:"slot"`
+		ProposerIndex uint64Str `json:"proposer_index"`
+		ParentRoot    string    `json:"parent_root"`
+		StateRoot     string    `json:"state_root"`
+		BodyRoot      string    `json:"body_root"`
+	} `json:"message"`
+	Signature string `json:"signature"`
+}
+
 type ProposerSlashing struct {
-	SignedHeader1 struct {
-		Message struct {
-			Slot          uint64Str `json:"slot"`
-			ProposerIndex uint64Str `json:"proposer_index"`
-			ParentRoot    string    `json:"parent_root"`
-			StateRoot     string    `json:"state_root"`
-			BodyRoot      string    `json:"body_root"`
-		} `json:"message"`
-		Signature string `json:"signature"`
-	} `json:"signed_header_1"`
-	SignedHeader2 struct {
-		Message struct {
-			Slot          uint64Str `json:"slot"`
-			ProposerIndex uint64Str `json:"proposer_index"`
-			ParentRoot    string    `json:"parent_root"`
-			StateRoot     string    `json:"state_root"`
-			BodyRoot      string    `json:"body_root"`
-		} `json:"message"`
-		Signature string `json:"signature"`
-	} `json:"signed_header_2"`
+	SignedHeader1 SignedBeaconBlockHeader `json:"signed_header_1"`
+	SignedHeader2 SignedBeaconBlockHeader `json:"signed_header_2"`
+}
+
+type Checkpoint struct {
+	Epoch uint64Str `json:"epoch"`
+	Root  string    `json:"root"`
+}
+
+type AttestationData struct {
+	Slot            uint64Str  `json:"slot"`
+	Index           uint64Str  `json:"index"`
+	BeaconBlockRoot string     `json:"beacon_block_root"`
+	Source          Checkpoint `json:"source"`
+	Target          Checkpoint `json:"target"`
+}
+
+type IndexedAttestation struct {
+	AttestingIndices []uint64Str     `json:"attesting_indices"`
+	Signature        string          `json:"signature"`
+	Data             AttestationData `json:"data"`
 }
 
 type AttesterSlashing struct {
-	Attestation1 struct {
-		AttestingIndices []uint64Str `json:"attesting_indices"`
-		Signature        string      `json:"signature"`
-		Data             struct {
-			Slot            uint64Str `json:"slot"`
-			Index           uint64Str `json:"index"`
-			BeaconBlockRoot string    `json:"beacon_block_root"`
-			Source          struct {
-				Epoch uint64Str `json:"epoch"`
-				Root  string    `json:"root"`
-			} `json:"source"`
-			Target struct {
-				Epoch uint64Str `json:"epoch"`
-				Root  string    `json:"root"`
-			} `json:"target"`
-		} `json:"data"`
-	} `json:"attestation_1"`
-	Attestation2 struct {
-		AttestingIndices []uint64Str `json:"attesting_indices"`
-		Signature        string      `json:"signature"`
-		Data             struct {
-			Slot            uint64Str `json:"slot"`
-			Index           uint64Str `json:"index"`
-			BeaconBlockRoot string    `json:"beacon_block_root"`
-			Source          struct {
-				Epoch uint64Str `json:"epoch"`
-				Root  string    `json:"root"`
-			} `json:"source"`
-			Target struct {
-				Epoch uint64Str `json:"epoch"`
-				Root  string    `json:"root"`
-			} `json:"target"`
-		} `json:"data"`
-	} `json:"attestation_2"`
+	Attestation1 IndexedAttestation `json:"attestation_1"`
+	Attestation2 IndexedAttestation `json:"attestation_2"`
 }
 
 type Attestation struct {
-	AggregationBits string `json:"aggregation_bits"`
-	Signature       string `json:"signature"`
-	Data            struct {
-		Slot            uint64Str `json:"slot"`
-		Index           uint64Str `json:"index"`
-		BeaconBlockRoot string    `json:"beacon_block_root"`
-		Source          struct {
-			Epoch uint64Str `json:"epoch"`
-			Root  string    `json:"root"`
-		} `json:"source"`
-		Target struct {
-			Epoch uint64Str `json:"epoch"`
-			Root  string    `json:"root"`
-		} `json:"target"`
-	} `json:"data"`
+	AggregationBits string          `json:"aggregation_bits"`
+	Signature       string          `json:"signature"`
+	Data            AttestationData `json:"data"`
 }
 
 type Deposit struct {
@@ -249,6 +237,29 @@ type ExecutionPayload struct {
 	BaseFeePerGas uint64Str     `json:"base_fee_per_gas"`
 	BlockHash     bytesHexStr   `json:"block_hash"`
 	Transactions  []bytesHexStr `json:"transactions"`
+
+	// present only after capella
+	Withdrawals []Withdrawal `json:"withdrawals"`
+
+	// present only after deneb
+	BlobGasUsed   uint64Str `json:"blob_gas_used"`
+	ExcessBlobGas uint64Str `json:"excess_blob_gas"`
+}
+
+type Withdrawal struct {
+	Index          uint64Str   `json:"index"`
+	ValidatorIndex uint64Str   `json:"validator_index"`
+	Address        bytesHexStr `json:"address"`
+	Amount         uint64Str   `json:"amount"`
+}
+
+type SignedBLSToExecutionChange struct {
+	Message struct {
+		ValidatorIndex     uint64Str   `json:"validator_index"`
+		FromBlsPubkey      bytesHexStr `json:"from_bls_pubkey"`
+		ToExecutionAddress bytesHexStr `json:"to_execution_address"`
+	} `json:"message"`
+	Signature bytesHexStr `json:"signature"`
 }
 
 type AnySignedBlock struct {
@@ -272,6 +283,12 @@ type AnySignedBlock struct {
 
 			// not present in phase0/altair blocks
 			ExecutionPayload *ExecutionPayload `json:"execution_payload"`
+
+			// not present in phase0/altair/bellatrix blocks
+			BlsToExecutionChanges []SignedBLSToExecutionChange `json:"bls_to_execution_changes"`
+
+			// not present in phase0/altair/bellatrix/capella blocks
+			BlobKzgCommitments []bytesHexStr `json:"blob_kzg_commitments"`
 		} `json:"body"`
 	} `json:"message"`
 	Signature bytesHexStr `json:"signature"`
@@ -282,6 +299,16 @@ type StandardV2BlockResponse struct {
 	Data    AnySignedBlock `json:"data"`
 }
 
+// https://ethereum.github.io/beacon-APIs/#/Beacon/getBlobSidecars
+type StandardBlobSidecarsResponse struct {
+	Data []struct {
+		Index         uint64Str   `json:"index"`
+		Blob          bytesHexStr `json:"blob"`
+		KzgCommitment bytesHexStr `json:"kzg_commitment"`
+		KzgProof      bytesHexStr `json:"kzg_proof"`
+	} `json:"data"`
+}
+
 type StandardV1BlockRootResponse struct {
 	Data struct {
 		Root string `json:"root"`
@@ -308,11 +335,6 @@ type StandardValidatorsResponse struct {
 	Data []StandardValidatorEntry `json:"data"`
 }
 
-func (pc *LighthouseClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonBlock, error) {
-	blocks := make([]*types.CanonBlock, 0)
-	return blocks, nil
-}
-
 type StandardSyncingResponse struct {
 	Data struct {
 		IsSyncing    bool      `json:"is_syncing"`
@@ -327,3 +349,90 @@ type StandardValidatorBalancesResponse struct {
 		Balance uint64Str `json:"balance"`
 	} `json:"data"`
 }
+
+// https://ethereum.github.io/beacon-APIs/#/Events/eventstream
+type StandardHeadEvent struct {
+	Slot                      uint64Str `json:"slot"`
+	Block                     string    `json:"block"`
+	State                     string    `json:"state"`
+	EpochTransition           bool      `json:"epoch_transition"`
+	PreviousDutyDependentRoot string    `json:"previous_duty_dependent_root"`
+	CurrentDutyDependentRoot  string    `json:"current_duty_dependent_root"`
+	ExecutionOptimistic       bool      `json:"execution_optimistic"`
+}
+
+type StandardBlockEvent struct {
+	Slot                uint64Str `json:"slot"`
+	Block               string    `json:"block"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type StandardChainReorgEvent struct {
+	Slot                uint64Str `json:"slot"`
+	Depth               uint64Str `json:"depth"`
+	OldHeadBlock        string    `json:"old_head_block"`
+	NewHeadBlock        string    `json:"new_head_block"`
+	OldHeadState        string    `json:"old_head_state"`
+	NewHeadState        string    `json:"new_head_state"`
+	Epoch               uint64Str `json:"epoch"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type StandardFinalizedCheckpointEvent struct {
+	Block               string    `json:"block"`
+	State               string    `json:"state"`
+	Epoch               uint64Str `json:"epoch"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+// https://ethereum.github.io/beacon-APIs/#/Rewards/getAttestationsRewards
+type StandardAttestationRewardsResponse struct {
+	ExecutionOptimistic bool `json:"execution_optimistic"`
+	Finalized           bool `json:"finalized"`
+	Data                struct {
+		TotalRewards []struct {
+			ValidatorIndex uint64Str `json:"validator_index"`
+			Head           int64Str  `json:"head"`
+			Target         int64Str  `json:"target"`
+			Source         int64Str  `json:"source"`
+			InclusionDelay int64Str  `json:"inclusion_delay"`
+			Inactivity     int64Str  `json:"inactivity"`
+		} `json:"total_rewards"`
+	} `json:"data"`
+}
+
+// https://ethereum.github.io/beacon-APIs/#/Rewards/getSyncCommitteeRewards
+type StandardSyncCommitteeRewardsResponse struct {
+	ExecutionOptimistic bool `json:"execution_optimistic"`
+	Finalized           bool `json:"finalized"`
+	Data                []struct {
+		ValidatorIndex uint64Str `json:"validator_index"`
+		Reward         int64Str  `json:"reward"`
+	} `json:"data"`
+}
+
+type StandardBLSChangePoolResponse struct {
+	Data []SignedBLSToExecutionChange `json:"data"`
+}
+
+type StandardGenesisResponse struct {
+	Data struct {
+		GenesisTime           uint64Str   `json:"genesis_time"`
+		GenesisValidatorsRoot bytesHexStr `json:"genesis_validators_root"`
+		GenesisForkVersion    bytesHexStr `json:"genesis_fork_version"`
+	} `json:"data"`
+}
+
+// https://ethereum.github.io/beacon-APIs/#/Rewards/getBlockRewards
+type StandardBlockRewardsResponse struct {
+	ExecutionOptimistic bool `json:"execution_optimistic"`
+	Finalized           bool `json:"finalized"`
+	Data                struct {
+		ProposerIndex     uint64Str `json:"proposer_index"`
+		Total             int64Str  `json:"total"`
+		Attestations      int64Str  `json:"attestations"`
+		SyncAggregate     int64Str  `json:"sync_aggregate"`
+		ProposerSlashings int64Str  `json:"proposer_slashings"`
+		AttesterSlashings int64Str  `json:"attester_slashings"`
+	} `json:"data"`
+}
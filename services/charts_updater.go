@@ -19,12 +19,13 @@ type chartHandler struct {
 }
 
 var ChartHandlers = map[string]chartHandler{
-	"blocks":             {1, blocksChartData},
-	"validators":         {2, activeValidatorsChartData},
-	"staked_ether":       {3, stakedEtherChartData},
-	"average_balance":    {4, averageBalanceChartData},
-	"network_liveness":   {5, networkLivenessChartData},
-	"participation_rate": {6, participationRateChartData},
+	"blocks":                       {1, blocksChartData},
+	"validators":                   {2, activeValidatorsChartData},
+	"staked_ether":                 {3, stakedEtherChartData},
+	"average_balance":              {4, averageBalanceChartData},
+	"network_liveness":             {5, networkLivenessChartData},
+	"participation_rate":           {6, participationRateChartData},
+	"sync_committee_participation": {7, syncCommitteeParticipationChartData},
 	// "inclusion_distance":             {7, inclusionDistanceChartData},
 	// "incorrect_attestations":         {6, incorrectAttestationsChartData},
 	// "validator_income":               {7, averageDailyValidatorIncomeChartData},
@@ -283,7 +284,7 @@ func stakedEtherChartData() (*types.GenericChartData, error) {
 		EligibleEther uint64
 	}{}
 
-	err := db.ReaderDb.Select(&rows, "SELECT epoch, eligibleether FROM epochs ORDER BY epoch")
+	err := db.ReaderDb.Select(&rows, "SELECT epoch, eligibleether FROM epochs WHERE eligibleether IS NOT NULL ORDER BY epoch")
 	if err != nil {
 		return nil, err
 	}
@@ -417,7 +418,7 @@ func participationRateChartData() (*types.GenericChartData, error) {
 		Globalparticipationrate float64
 	}{}
 
-	err := db.ReaderDb.Select(&rows, "SELECT epoch, globalparticipationrate FROM epochs WHERE epoch < $1 ORDER BY epoch", LatestEpoch())
+	err := db.ReaderDb.Select(&rows, "SELECT epoch, globalparticipationrate FROM epochs WHERE epoch < $1 AND globalparticipationrate IS NOT NULL ORDER BY epoch", LatestEpoch())
 	if err != nil {
 		return nil, err
 	}
@@ -449,6 +450,54 @@ func participationRateChartData() (*types.GenericChartData, error) {
 	return chartData, nil
 }
 
+func syncCommitteeParticipationChartData() (*types.GenericChartData, error) {
+	if LatestEpoch() == 0 {
+		return nil, fmt.Errorf("chart-data not available pre-genesis")
+	}
+
+	rows := []struct {
+		Epoch         uint64
+		Participation float64
+	}{}
+
+	// slots without a canonical block are not counted, they are reflected in the network liveness
+	err := db.ReaderDb.Select(&rows, `
+		SELECT epoch, AVG(syncaggregate_participation) AS participation
+		FROM blocks
+		WHERE epoch >= $1 AND epoch < $2 AND status = '1'
+		GROUP BY epoch
+		ORDER BY epoch`, utils.ForkFeatureEpoch(utils.ForkFeatureSyncCommittees), LatestEpoch())
+	if err != nil {
+		return nil, err
+	}
+
+	seriesData := [][]float64{}
+
+	for _, row := range rows {
+		seriesData = append(seriesData, []float64{
+			float64(utils.EpochToTime(row.Epoch).Unix() * 1000),
+			utils.RoundDecimals(row.Participation*100, 2),
+		})
+	}
+
+	chartData := &types.GenericChartData{
+		Title:        "Sync Committee Participation",
+		Subtitle:     "Sync Committee Participation measures how many of the sync committee signatures are included in the blocks of an epoch.",
+		XAxisTitle:   "",
+		YAxisTitle:   "Sync Committee Participation [%]",
+		StackingMode: "false",
+		Type:         "line",
+		Series: []*types.GenericChartDataSeries{
+			{
+				Name: "Sync Committee Participation",
+				Data: seriesData,
+			},
+		},
+	}
+
+	return chartData, nil
+}
+
 func inclusionDistanceChartData() (*types.GenericChartData, error) {
 	if LatestEpoch() == 0 {
 		return nil, fmt.Errorf("chart-data not available pre-genesis")
@@ -777,7 +826,7 @@ func estimatedValidatorIncomeChartData() (*types.GenericChartData, error) {
 				group by epoch
 			)
 		select
-			epochs.epoch, eligibleether, votedether, validatorscount, globalparticipationrate,
+			epochs.epoch, coalesce(eligibleether,0) as eligibleether, coalesce(votedether,0) as votedether, validatorscount, coalesce(globalparticipationrate,0) as globalparticipationrate,
 			coalesce(totalvalidatorbalance - coalesce(ed.amount,0),0) as totalvalidatorbalance
 		from epochs
 			left join extradeposits ed on epochs.epoch = ed.epoch
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/notify"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// chatMaxAttempts is the number of delivery attempts of a chat message before it is dropped
+const chatMaxAttempts = 5
+
+// chatRetryBackoff is the delay before the second delivery attempt of a chat message, it doubles with every further attempt
+const chatRetryBackoff = time.Minute
+
+// chatMessageLimits is the maximum length of a single message per chat platform
+var chatMessageLimits = map[types.NotificationChannel]int{
+	types.TelegramNotificationChannel: 4096,
+	types.SlackNotificationChannel:    4000,
+	types.MatrixNotificationChannel:   16000,
+}
+
+var markdownLinkRE = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+// telegramStartRE matches the /start command with a link token, in group chats the command carries the name of the bot
+var telegramStartRE = regexp.MustCompile(`^/start(@\w+)?(\s+(\S+))?$`)
+
+var telegramClient *notify.TelegramClient
+var slackClient = notify.NewSlackClient()
+var matrixClient = notify.NewMatrixClient()
+var telegramClientOnce sync.Once
+
+func getTelegramClient() *notify.TelegramClient {
+	telegramClientOnce.Do(func() {
+		telegramClient = notify.NewTelegramClient(utils.Config.Notifications.TelegramApiUrl, utils.Config.Notifications.TelegramBotToken)
+	})
+	return telegramClient
+}
+
+// telegramChatLinker will read the messages sent to the telegram bot and link the chat of every /start <token> message to
+// the user the token belongs to, so users can only receive notifications in chats they are a member of
+func telegramChatLinker() {
+	client := getTelegramClient()
+	offset := int64(0)
+	for {
+		updates, err := client.GetUpdates(offset, time.Second*20)
+		if err != nil {
+			logger.WithError(err).Error("error retrieving telegram updates")
+			time.Sleep(time.Second * 10)
+			continue
+		}
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil {
+				continue
+			}
+			handleTelegramMessage(client, strconv.FormatInt(update.Message.Chat.ID, 10), strings.TrimSpace(update.Message.Text))
+		}
+	}
+}
+
+func handleTelegramMessage(client *notify.TelegramClient, chatID, text string) {
+	match := telegramStartRE.FindStringSubmatch(text)
+	if match == nil {
+		return
+	}
+
+	reply := fmt.Sprintf("Open the notification settings on %v to get a link for this chat.", html.EscapeString(utils.Config.Frontend.SiteDomain))
+	if token := match[3]; token != "" {
+		userID, err := db.LinkTelegramChat(token, chatID)
+		if err != nil {
+			logger.WithError(err).Errorf("error linking telegram chat %v", chatID)
+			return
+		}
+		if userID != 0 {
+			logger.Infof("linked telegram chat %v to user %v", chatID, userID)
+			reply = fmt.Sprintf("This chat will now receive your notifications from %v.", html.EscapeString(utils.Config.Frontend.SiteDomain))
+		} else {
+			reply = "The link is invalid or has expired. " + reply
+		}
+	}
+
+	err := client.Send(chatID, reply)
+	if err != nil {
+		logger.WithError(err).Errorf("error replying to telegram chat %v", chatID)
+	}
+}
+
+// chatQueueItem is a queued chat message together with the current channel settings of its user
+type chatQueueItem struct {
+	Id          uint64                   `db:"id"`
+	Content     types.TransitChatContent `db:"content"`
+	Attempts    uint64                   `db:"attempts"`
+	Destination sql.NullString           `db:"destination"`
+	Server      sql.NullString           `db:"server"`
+	Token       sql.NullString           `db:"token"`
+}
+
+// markdownToHtml will convert the links of a notification in markdown to html and escape everything else
+func markdownToHtml(md string) string {
+	return markdownLinkRE.ReplaceAllString(html.EscapeString(md), `<a href="$2">$1</a>`)
+}
+
+// markdownToSlack will convert the links of a notification in markdown to slack mrkdwn
+func markdownToSlack(md string) string {
+	escaped := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(md)
+	return markdownLinkRE.ReplaceAllString(escaped, `<$2|$1>`)
+}
+
+// chatMessageBuilder batches lines into messages that stay below the length limit of a platform
+type chatMessageBuilder struct {
+	limit    int
+	messages []types.TransitChatContent
+	text     strings.Builder
+	html     strings.Builder
+}
+
+func (b *chatMessageBuilder) add(text, html string) {
+	if b.text.Len() > 0 && (b.text.Len()+len(text)+1 > b.limit || b.html.Len()+len(html)+4 > b.limit) {
+		b.flush()
+	}
+	if b.text.Len() > 0 {
+		b.text.WriteString("\n")
+		if html != "" {
+			b.html.WriteString("<br>")
+		}
+	}
+	b.text.WriteString(text)
+	b.html.WriteString(html)
+}
+
+func (b *chatMessageBuilder) flush() {
+	if b.text.Len() == 0 {
+		return
+	}
+	b.messages = append(b.messages, types.TransitChatContent{Text: b.text.String(), FormattedText: b.html.String()})
+	b.text.Reset()
+	b.html.Reset()
+}
+
+// formatChatMessages will format the notifications of a user for a chat platform and batch them into as few messages
+// as the length limit of the platform allows
+func formatChatMessages(channel types.NotificationChannel, userNotifications map[types.EventName][]types.Notification) []types.TransitChatContent {
+	events := make([]types.EventName, 0, len(userNotifications))
+	for event := range userNotifications {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+
+	b := &chatMessageBuilder{limit: chatMessageLimits[channel]}
+	for _, event := range events {
+		label := types.EventLabel[event]
+		if label == "" {
+			label = string(event)
+		}
+
+		switch channel {
+		case types.TelegramNotificationChannel:
+			b.add("<b>"+html.EscapeString(label)+"</b>", "")
+		case types.SlackNotificationChannel:
+			b.add("*"+markdownToSlack(label)+"*", "")
+		case types.MatrixNotificationChannel:
+			b.add("**"+label+"**", "<b>"+html.EscapeString(label)+"</b>")
+		}
+
+		for _, n := range userNotifications[event] {
+			md := n.GetInfoMarkdown()
+			switch channel {
+			case types.TelegramNotificationChannel:
+				b.add("• "+markdownToHtml(md), "")
+			case types.SlackNotificationChannel:
+				b.add("• "+markdownToSlack(md), "")
+			case types.MatrixNotificationChannel:
+				b.add("- "+md, "• "+markdownToHtml(md))
+			}
+			metrics.NotificationsQueued.WithLabelValues(string(channel), string(event)).Inc()
+		}
+	}
+	b.flush()
+	return b.messages
+}
+
+// queueChatNotifications will queue the notifications of every user for the chat channels the user configured, all
+// notifications of a user are batched into as few messages per channel as possible
+func queueChatNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, deliverAfter map[uint64]sql.NullTime, useDB *sqlx.DB) error {
+	userIDs := make([]int64, 0, len(notificationsByUserID))
+	for userID := range notificationsByUserID {
+		userIDs = append(userIDs, int64(userID))
+	}
+	channels := make([]string, 0, len(types.ChatNotificationChannels))
+	for _, ch := range types.ChatNotificationChannels {
+		if ch == types.TelegramNotificationChannel && utils.Config.Notifications.TelegramBotToken == "" {
+			continue
+		}
+		channels = append(channels, string(ch))
+	}
+
+	var userChannels []struct {
+		UserID  uint64                    `db:"user_id"`
+		Channel types.NotificationChannel `db:"channel"`
+	}
+	err := useDB.Select(&userChannels, `
+		SELECT user_id, channel
+		FROM users_notification_channels
+		WHERE user_id = ANY($1) AND channel::text = ANY($2) AND active AND destination IS NOT NULL AND destination != ''`,
+		pq.Array(userIDs), pq.Array(channels))
+	if err != nil {
+		return fmt.Errorf("error querying users_notification_channels, err: %w", err)
+	}
+
+	for _, uc := range userChannels {
+		for _, content := range formatChatMessages(uc.Channel, notificationsByUserID[uc.UserID]) {
+			content.UserID = uc.UserID
+			_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content, next_attempt) VALUES (now(), $1, $2, $3)`, uc.Channel, content, deliverAfter[uc.UserID])
+			if err != nil {
+				logger.WithError(err).Errorf("error queueing %v notification for user %v", uc.Channel, uc.UserID)
+			}
+		}
+	}
+	return nil
+}
+
+// sendChatNotifications will post the due messages of a chat channel. Messages that hit the rate limit of the platform
+// are delayed as requested by it, failed messages are retried with an exponential backoff until chatMaxAttempts is reached
+func sendChatNotifications(useDB *sqlx.DB, channel types.NotificationChannel) error {
+	var items []*chatQueueItem
+	err := useDB.Select(&items, `
+		SELECT
+			nq.id,
+			nq.content,
+			nq.attempts,
+			uc.destination,
+			uc.server,
+			uc.token
+		FROM notification_queue nq
+		LEFT JOIN users_notification_channels uc ON uc.user_id = (nq.content->>'UserID')::int AND uc.channel = nq.channel AND uc.active
+		WHERE nq.sent IS NULL AND nq.channel = $1 AND nq.attempts < $2 AND (nq.next_attempt IS NULL OR nq.next_attempt <= now())
+		ORDER BY nq.created ASC`, channel, chatMaxAttempts)
+	if err != nil {
+		return fmt.Errorf("error querying notification queue, err: %w", err)
+	}
+
+	logger.Infof("processing %v %v notifications", len(items), channel)
+
+	wg := &sync.WaitGroup{}
+	sem := make(chan struct{}, 10)
+	for _, n := range items {
+		// the user removed or deactivated the channel since the message was queued, or its destination is not accepted anymore
+		if !n.Destination.Valid || n.Destination.String == "" ||
+			(channel == types.TelegramNotificationChannel && utils.Config.Notifications.TelegramBotToken == "") ||
+			(channel == types.SlackNotificationChannel && !notify.IsSlackWebhookUrl(n.Destination.String)) ||
+			(channel == types.MatrixNotificationChannel && (!n.Server.Valid || !n.Token.Valid)) {
+			_, err := useDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
+			if err != nil {
+				return fmt.Errorf("error deleting from notification queue: %w", err)
+			}
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n *chatQueueItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			deliverChatMessage(useDB, channel, n)
+		}(n)
+	}
+	wg.Wait()
+	return nil
+}
+
+func deliverChatMessage(useDB *sqlx.DB, channel types.NotificationChannel, n *chatQueueItem) {
+	var err error
+	switch channel {
+	case types.TelegramNotificationChannel:
+		err = getTelegramClient().Send(n.Destination.String, n.Content.Text)
+	case types.SlackNotificationChannel:
+		err = slackClient.Send(n.Destination.String, n.Content.Text)
+	case types.MatrixNotificationChannel:
+		err = matrixClient.Send(n.Server.String, n.Token.String, n.Destination.String, fmt.Sprintf("%v-%v", utils.Config.Frontend.SiteDomain, n.Id), n.Content.Text, n.Content.FormattedText)
+	default:
+		err = fmt.Errorf("error unknown chat channel %v", channel)
+	}
+
+	if err == nil {
+		metrics.NotificationsSent.WithLabelValues(string(channel), "OK").Inc()
+		_, err = useDB.Exec(`UPDATE notification_queue SET sent = now(), attempts = attempts + 1 WHERE id = $1`, n.Id)
+		if err != nil {
+			logger.WithError(err).Errorf("error updating notification_queue table")
+		}
+		return
+	}
+
+	var rateLimitErr *notify.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		metrics.NotificationsSent.WithLabelValues(string(channel), "rate limited").Inc()
+		retryAfter := rateLimitErr.RetryAfter
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		// hitting the rate limit does not count as a failed attempt
+		_, err = useDB.Exec(`UPDATE notification_queue SET next_attempt = $2 WHERE id = $1`, n.Id, time.Now().Add(retryAfter))
+		if err != nil {
+			logger.WithError(err).Errorf("error updating notification_queue table; delaying rate limited message")
+		}
+		return
+	}
+
+	logger.WithError(err).Errorf("error sending %v notification %v", channel, n.Id)
+	metrics.NotificationsSent.WithLabelValues(string(channel), "error").Inc()
+	nextAttempt := time.Now().Add(chatRetryBackoff * time.Duration(uint64(1)<<n.Attempts))
+	_, err = useDB.Exec(`UPDATE notification_queue SET attempts = attempts + 1, next_attempt = $2 WHERE id = $1`, n.Id, nextAttempt)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating notification_queue table; scheduling next attempt")
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testChatNotification struct {
+	event    types.EventName
+	markdown string
+}
+
+func (n *testChatNotification) GetSubscriptionID() uint64                  { return 0 }
+func (n *testChatNotification) GetEventName() types.EventName              { return n.event }
+func (n *testChatNotification) GetEpoch() uint64                           { return 0 }
+func (n *testChatNotification) GetInfo(includeUrl bool) string             { return n.markdown }
+func (n *testChatNotification) GetTitle() string                           { return "" }
+func (n *testChatNotification) GetEventFilter() string                     { return "" }
+func (n *testChatNotification) GetEmailAttachment() *types.EmailAttachment { return nil }
+func (n *testChatNotification) GetUnsubscribeHash() string                 { return "" }
+func (n *testChatNotification) GetInfoMarkdown() string                    { return n.markdown }
+
+func testChatNotifications(event types.EventName, count, length int) []types.Notification {
+	notifications := make([]types.Notification, 0, count)
+	for i := 0; i < count; i++ {
+		prefix := fmt.Sprintf("[Validator %d](https://example.org/validator/%d) ", i, i)
+		notifications = append(notifications, &testChatNotification{event: event, markdown: prefix + strings.Repeat("x", length-len(prefix))})
+	}
+	return notifications
+}
+
+func TestFormatChatMessagesBatching(t *testing.T) {
+	tests := []struct {
+		name          string
+		notifications map[types.EventName][]types.Notification
+		wantSingle    bool
+	}{
+		{
+			name: "single message",
+			notifications: map[types.EventName][]types.Notification{
+				types.ValidatorMissedProposalEventName: testChatNotifications(types.ValidatorMissedProposalEventName, 3, 80),
+			},
+			wantSingle: true,
+		},
+		{
+			name: "batched at the limit",
+			notifications: map[types.EventName][]types.Notification{
+				types.ValidatorMissedProposalEventName:    testChatNotifications(types.ValidatorMissedProposalEventName, 300, 150),
+				types.ValidatorMissedAttestationEventName: testChatNotifications(types.ValidatorMissedAttestationEventName, 300, 150),
+			},
+		},
+	}
+
+	for _, channel := range types.ChatNotificationChannels {
+		limit := chatMessageLimits[channel]
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%v %v", channel, tt.name), func(t *testing.T) {
+				messages := formatChatMessages(channel, tt.notifications)
+				if len(messages) == 0 {
+					t.Fatal("expected messages")
+				}
+				if tt.wantSingle && len(messages) != 1 {
+					t.Fatalf("expected a single message, got %v", len(messages))
+				}
+				if !tt.wantSingle && len(messages) < 2 {
+					t.Fatalf("expected several messages, got %v", len(messages))
+				}
+
+				lines := 0
+				for i, message := range messages {
+					if len(message.Text) > limit || len(message.FormattedText) > limit {
+						t.Errorf("message %v exceeds the limit of %v: %v / %v", i, limit, len(message.Text), len(message.FormattedText))
+					}
+					if (channel == types.MatrixNotificationChannel) != (message.FormattedText != "") {
+						t.Errorf("message %v has unexpected formatted text %q", i, message.FormattedText)
+					}
+					lines += strings.Count(message.Text, "\n") + 1
+
+					// a message is only started if the next line did not fit into the previous one
+					if i > 0 {
+						prev := messages[i-1]
+						nextText := strings.SplitN(message.Text, "\n", 2)[0]
+						nextHtml := strings.SplitN(message.FormattedText, "<br>", 2)[0]
+						if len(prev.Text)+1+len(nextText) <= limit && len(prev.FormattedText)+4+len(nextHtml) <= limit {
+							t.Errorf("line %q would have fit into message %v", nextText, i-1)
+						}
+					}
+				}
+
+				wantLines := 0
+				for _, ns := range tt.notifications {
+					wantLines += len(ns) + 1
+				}
+				if lines != wantLines {
+					t.Errorf("expected %v lines, got %v", wantLines, lines)
+				}
+			})
+		}
+	}
+}
+
+func TestFormatChatMessagesLinks(t *testing.T) {
+	notifications := map[types.EventName][]types.Notification{
+		types.ValidatorGotSlashedEventName: {&testChatNotification{event: types.ValidatorGotSlashedEventName, markdown: "[Validator 1](https://example.org/validator/1) <slashed> & gone"}},
+	}
+	tests := []struct {
+		channel       types.NotificationChannel
+		wantText      string
+		wantFormatted string
+	}{
+		{types.TelegramNotificationChannel, `• <a href="https://example.org/validator/1">Validator 1</a> &lt;slashed&gt; &amp; gone`, ""},
+		{types.SlackNotificationChannel, `• <https://example.org/validator/1|Validator 1> &lt;slashed&gt; &amp; gone`, ""},
+		{types.MatrixNotificationChannel, `- [Validator 1](https://example.org/validator/1) <slashed> & gone`, `• <a href="https://example.org/validator/1">Validator 1</a> &lt;slashed&gt; &amp; gone`},
+	}
+	for _, tt := range tests {
+		messages := formatChatMessages(tt.channel, notifications)
+		if len(messages) != 1 {
+			t.Fatalf("%v: expected a single message, got %v", tt.channel, len(messages))
+		}
+		if !strings.HasSuffix(messages[0].Text, "\n"+tt.wantText) {
+			t.Errorf("%v: unexpected text %q", tt.channel, messages[0].Text)
+		}
+		if !strings.HasSuffix(messages[0].FormattedText, tt.wantFormatted) {
+			t.Errorf("%v: unexpected formatted text %q", tt.channel, messages[0].FormattedText)
+		}
+	}
+}
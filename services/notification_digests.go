@@ -0,0 +1,232 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html"
+	"html/template"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// dailyDigestHour is the hour of the day in the timezone of a user at which daily digests are sent
+const dailyDigestHour = 8
+
+func userLocation(s *types.UserNotificationSettings) *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// quietHoursEnd will return the end of the quiet hours of a user, the second return value is false if the user is not
+// in quiet hours at the given time
+func quietHoursEnd(s *types.UserNotificationSettings, now time.Time) (time.Time, bool) {
+	if s == nil || s.QuietHoursStart == s.QuietHoursEnd {
+		return time.Time{}, false
+	}
+
+	local := now.In(userLocation(s))
+	hour := uint64(local.Hour())
+	quiet := false
+	if s.QuietHoursStart < s.QuietHoursEnd {
+		quiet = hour >= s.QuietHoursStart && hour < s.QuietHoursEnd
+	} else {
+		// the quiet hours span midnight
+		quiet = hour >= s.QuietHoursStart || hour < s.QuietHoursEnd
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	end := time.Date(local.Year(), local.Month(), local.Day(), int(s.QuietHoursEnd), 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
+
+// notificationsDeliverAfter will return the time until which the notifications of every user that is in quiet hours
+// are held back in the queue
+func notificationsDeliverAfter(settingsByUserID map[uint64]*types.UserNotificationSettings) map[uint64]sql.NullTime {
+	now := time.Now()
+	deliverAfter := make(map[uint64]sql.NullTime)
+	for userID, s := range settingsByUserID {
+		if end, quiet := quietHoursEnd(s, now); quiet {
+			// next_attempt has no time zone, it is compared against now() in utc
+			deliverAfter[userID] = sql.NullTime{Time: end.UTC(), Valid: true}
+		}
+	}
+	return deliverAfter
+}
+
+// digestDue checks if the next digest of a user is due, daily digests are sent at dailyDigestHour in the timezone of the user
+func digestDue(s *types.UserNotificationSettings, now time.Time) bool {
+	if !s.LastDigest.Valid {
+		return true
+	}
+
+	switch s.Delivery {
+	case types.HourlyNotificationDelivery:
+		return now.Sub(s.LastDigest.Time) >= time.Hour
+	case types.DailyNotificationDelivery:
+		local := now.In(userLocation(s))
+		digestTime := time.Date(local.Year(), local.Month(), local.Day(), dailyDigestHour, 0, 0, 0, local.Location())
+		if local.Before(digestTime) {
+			digestTime = digestTime.AddDate(0, 0, -1)
+		}
+		return s.LastDigest.Time.Before(digestTime)
+	}
+	return false
+}
+
+// queueNotificationDigestItems will collect the notifications of users that receive digests instead of immediate emails
+func queueNotificationDigestItems(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) error {
+	for userID, userNotifications := range notificationsByUserID {
+		notifications := []types.Notification{}
+		for event, ns := range userNotifications {
+			notifications = append(notifications, ns...)
+			metrics.NotificationsQueued.WithLabelValues("digest", string(event)).Add(float64(len(ns)))
+		}
+		err := db.AddNotificationDigestItems(userID, notifications, useDB)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueNotificationDigests will queue the digest email of every user whose digest is due and who is not in quiet hours
+func queueNotificationDigests(useDB *sqlx.DB) error {
+	settings, err := db.GetDigestNotificationSettings()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due := make([]*types.UserNotificationSettings, 0, len(settings))
+	userIDs := make([]uint64, 0, len(settings))
+	for _, s := range settings {
+		if _, quiet := quietHoursEnd(s, now); quiet || !digestDue(s, now) {
+			continue
+		}
+		due = append(due, s)
+		userIDs = append(userIDs, s.UserID)
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	// users that deactivated the email channel are missing, their collected notifications are dropped
+	emailsByUserID, err := db.GetUserEmailsByIds(userIDs)
+	if err != nil {
+		return fmt.Errorf("error retrieving emails of digest users: %w", err)
+	}
+
+	for _, s := range due {
+		err := queueNotificationDigest(s, emailsByUserID[s.UserID], useDB)
+		if err != nil {
+			logger.WithError(err).Errorf("error queueing notification digest of user %v", s.UserID)
+		}
+	}
+	return nil
+}
+
+// queueNotificationDigest will aggregate the collected notifications of a user into a digest email and queue it
+func queueNotificationDigest(s *types.UserNotificationSettings, email string, useDB *sqlx.DB) error {
+	tx, err := useDB.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var maxID sql.NullInt64
+	err = tx.Get(&maxID, `SELECT max(id) FROM users_notification_digest_items WHERE user_id = $1`, s.UserID)
+	if err != nil {
+		return fmt.Errorf("error retrieving latest digest item: %w", err)
+	}
+
+	if maxID.Valid && email != "" {
+		items, err := db.GetNotificationDigestItems(s.UserID, uint64(maxID.Int64), tx)
+		if err != nil {
+			return err
+		}
+		content, err := formatNotificationDigest(s, items, email)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES (now(), 'email', $1)`, content)
+		if err != nil {
+			return fmt.Errorf("error writing digest email to db: %w", err)
+		}
+		metrics.NotificationsQueued.WithLabelValues("email", "digest").Inc()
+	}
+
+	if maxID.Valid {
+		_, err = tx.Exec(`DELETE FROM users_notification_digest_items WHERE user_id = $1 AND id <= $2`, s.UserID, maxID.Int64)
+		if err != nil {
+			return fmt.Errorf("error deleting digest items: %w", err)
+		}
+	}
+	_, err = tx.Exec(`UPDATE users_notification_settings SET last_digest = now() WHERE user_id = $1`, s.UserID)
+	if err != nil {
+		return fmt.Errorf("error updating last digest: %w", err)
+	}
+	return tx.Commit()
+}
+
+// formatNotificationDigest will create the digest email of a user with the number of notifications per event and validator
+func formatNotificationDigest(s *types.UserNotificationSettings, items []*types.NotificationDigestItem, email string) (*types.TransitEmailContent, error) {
+	pubkeys := [][]byte{}
+	for _, item := range items {
+		if pubkey, err := hex.DecodeString(item.EventFilter); err == nil && len(pubkey) == 48 {
+			pubkeys = append(pubkeys, pubkey)
+		}
+	}
+	indices, err := db.GetValidatorIndicesByPubkeys(pubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	total := uint64(0)
+	var msg types.Email
+	for i, item := range items {
+		if i == 0 || items[i-1].EventName != item.EventName {
+			if i > 0 {
+				msg.Body += "<br>"
+			}
+			label := types.EventLabel[item.EventName]
+			if label == "" {
+				label = string(item.EventName)
+			}
+			msg.Body += template.HTML(fmt.Sprintf("%s<br>====<br><br>", html.EscapeString(label)))
+		}
+
+		target := html.EscapeString(item.EventFilter)
+		if index, ok := indices[item.EventFilter]; ok {
+			target = fmt.Sprintf(`<a href="https://%[1]v/validator/%[2]v">Validator %[2]v</a>`, utils.Config.Frontend.SiteDomain, index)
+		} else if item.EventFilter == "" {
+			target = "Network"
+		}
+		epochs := fmt.Sprintf("epoch %v", item.FirstEpoch)
+		if item.FirstEpoch != item.LastEpoch {
+			epochs = fmt.Sprintf("epochs %v - %v", item.FirstEpoch, item.LastEpoch)
+		}
+		msg.Body += template.HTML(fmt.Sprintf("%s: %d times in %s<br>", target, item.Count, epochs))
+		total += item.Count
+	}
+	msg.SubscriptionManageURL = template.HTML(fmt.Sprintf(`<a href="%v" style="color: white" onMouseOver="this.style.color='#F5B498'" onMouseOut="this.style.color='#FFFFFF'">Manage</a>`, "https://"+utils.Config.Frontend.SiteDomain+"/user/notifications"))
+
+	return &types.TransitEmailContent{
+		Address: email,
+		Subject: fmt.Sprintf("%s: %s with %d notifications", utils.Config.Frontend.SiteDomain, s.Delivery.Label(), total),
+		Email:   msg,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"database/sql"
+	"eth2-exporter/types"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		settings  *types.UserNotificationSettings
+		now       time.Time
+		wantQuiet bool
+		wantEnd   time.Time
+	}{
+		{
+			name:     "no settings",
+			settings: nil,
+			now:      time.Date(2023, 5, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "disabled",
+			settings: &types.UserNotificationSettings{Timezone: "UTC", QuietHoursStart: 22, QuietHoursEnd: 22},
+			now:      time.Date(2023, 5, 1, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "within the same day",
+			settings:  &types.UserNotificationSettings{Timezone: "UTC", QuietHoursStart: 12, QuietHoursEnd: 14},
+			now:       time.Date(2023, 5, 1, 13, 15, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantEnd:   time.Date(2023, 5, 1, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "end is exclusive",
+			settings: &types.UserNotificationSettings{Timezone: "UTC", QuietHoursStart: 12, QuietHoursEnd: 14},
+			now:      time.Date(2023, 5, 1, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "spanning midnight before midnight",
+			settings:  &types.UserNotificationSettings{Timezone: "UTC", QuietHoursStart: 22, QuietHoursEnd: 7},
+			now:       time.Date(2023, 5, 1, 23, 30, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantEnd:   time.Date(2023, 5, 2, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "spanning midnight after midnight",
+			settings:  &types.UserNotificationSettings{Timezone: "UTC", QuietHoursStart: 22, QuietHoursEnd: 7},
+			now:       time.Date(2023, 5, 2, 6, 59, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantEnd:   time.Date(2023, 5, 2, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "spanning midnight outside",
+			settings: &types.UserNotificationSettings{Timezone: "UTC", QuietHoursStart: 22, QuietHoursEnd: 7},
+			now:      time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "timezone of the user",
+			settings:  &types.UserNotificationSettings{Timezone: "Europe/Berlin", QuietHoursStart: 22, QuietHoursEnd: 7},
+			now:       time.Date(2023, 5, 1, 21, 0, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantEnd:   time.Date(2023, 5, 2, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:     "unknown timezone falls back to utc",
+			settings: &types.UserNotificationSettings{Timezone: "Mars/Olympus", QuietHoursStart: 22, QuietHoursEnd: 7},
+			now:      time.Date(2023, 5, 1, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet := quietHoursEnd(tt.settings, tt.now)
+			if quiet != tt.wantQuiet {
+				t.Fatalf("expected quiet %v, got %v", tt.wantQuiet, quiet)
+			}
+			if quiet && !end.Equal(tt.wantEnd) {
+				t.Errorf("expected the quiet hours to end at %v, got %v", tt.wantEnd, end)
+			}
+		})
+	}
+}
+
+func TestDigestDue(t *testing.T) {
+	lastDigest := func(t time.Time) sql.NullTime {
+		return sql.NullTime{Time: t, Valid: true}
+	}
+
+	tests := []struct {
+		name     string
+		settings *types.UserNotificationSettings
+		now      time.Time
+		want     bool
+	}{
+		{
+			name:     "first digest",
+			settings: &types.UserNotificationSettings{Delivery: types.DailyNotificationDelivery, Timezone: "UTC"},
+			now:      time.Date(2023, 5, 1, 3, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "hourly within the hour",
+			settings: &types.UserNotificationSettings{Delivery: types.HourlyNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 1, 3, 0, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 1, 3, 59, 0, 0, time.UTC),
+		},
+		{
+			name:     "hourly after an hour",
+			settings: &types.UserNotificationSettings{Delivery: types.HourlyNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 1, 3, 0, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 1, 4, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "hourly compares instants",
+			settings: &types.UserNotificationSettings{Delivery: types.HourlyNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 1, 3, 30, 0, 0, time.FixedZone("UTC+2", 2*3600)))},
+			now:      time.Date(2023, 5, 1, 1, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily before the digest hour",
+			settings: &types.UserNotificationSettings{Delivery: types.DailyNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 2, 7, 59, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily at the digest hour",
+			settings: &types.UserNotificationSettings{Delivery: types.DailyNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 2, 8, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "daily already sent today",
+			settings: &types.UserNotificationSettings{Delivery: types.DailyNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 2, 8, 5, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 2, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily in the timezone of the user",
+			settings: &types.UserNotificationSettings{Delivery: types.DailyNotificationDelivery, Timezone: "Europe/Berlin", LastDigest: lastDigest(time.Date(2023, 5, 1, 6, 0, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 2, 6, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "immediate delivery",
+			settings: &types.UserNotificationSettings{Delivery: types.ImmediateNotificationDelivery, Timezone: "UTC", LastDigest: lastDigest(time.Date(2023, 5, 1, 6, 0, 0, 0, time.UTC))},
+			now:      time.Date(2023, 5, 3, 6, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digestDue(tt.settings, tt.now); got != tt.want {
+				t.Errorf("expected due %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/sha256"
 	"database/sql"
 	"database/sql/driver"
@@ -25,6 +26,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"firebase.google.com/go/messaging"
@@ -182,6 +184,11 @@ func notificationSender() {
 			logger.WithError(err).Error("error dispatching notifications")
 		}
 
+		err = queueNotificationDigests(db.FrontendWriterDB)
+		if err != nil {
+			logger.WithError(err).Error("error queueing notification digests")
+		}
+
 		err = garbageCollectNotificationQueue(db.FrontendWriterDB)
 		if err != nil {
 			logger.WithError(err).Errorf("error garbage collecting the notification queue")
@@ -235,6 +242,34 @@ func collectNotifications() map[uint64]map[types.EventName][]types.Notification
 	}
 	logger.Infof("Collecting validator got slashed notifications took: %v\n", time.Since(start))
 
+	err = collectWithdrawalNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting withdrawal notifications: %v", err)
+		metrics.Errors.WithLabelValues("notifications_collect_validator_withdrawal").Inc()
+	}
+	logger.Infof("Collecting withdrawal notifications took: %v\n", time.Since(start))
+
+	err = collectValidatorDidSlashNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting validator_did_slash notifications: %v", err)
+		metrics.Errors.WithLabelValues("notifications_collect_validator_did_slash").Inc()
+	}
+	logger.Infof("Collecting validator did slash notifications took: %v\n", time.Since(start))
+
+	err = collectValidatorStateChangedNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting validator_state_changed notifications: %v", err)
+		metrics.Errors.WithLabelValues("notifications_collect_validator_state_changed").Inc()
+	}
+	logger.Infof("Collecting validator state changed notifications took: %v\n", time.Since(start))
+
+	err = collectValidatorReceivedDepositNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting validator_received_deposit notifications: %v", err)
+		metrics.Errors.WithLabelValues("notifications_collect_validator_received_deposit").Inc()
+	}
+	logger.Infof("Collecting validator received deposit notifications took: %v\n", time.Since(start))
+
 	// executed Proposals
 	err = collectBlockProposalNotifications(notificationsByUserID, 1, types.ValidatorExecutedProposalEventName)
 	if err != nil {
@@ -358,12 +393,40 @@ func collectUserDbNotifications() map[uint64]map[types.EventName][]types.Notific
 func queueNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) {
 	subByEpoch := map[uint64][]uint64{}
 
-	err := queueEmailNotifications(notificationsByUserID, useDB)
+	userIDs := make([]uint64, 0, len(notificationsByUserID))
+	for userID := range notificationsByUserID {
+		userIDs = append(userIDs, userID)
+	}
+	settingsByUserID, err := db.GetUserNotificationSettings(userIDs)
+	if err != nil {
+		// notifications are still delivered, just without the preferences of the users
+		logger.WithError(err).Error("error retrieving notification settings")
+		settingsByUserID = map[uint64]*types.UserNotificationSettings{}
+	}
+	deliverAfter := notificationsDeliverAfter(settingsByUserID)
+
+	// users that receive digests get their email notifications collected instead of sent
+	immediateEmails := map[uint64]map[types.EventName][]types.Notification{}
+	digestEmails := map[uint64]map[types.EventName][]types.Notification{}
+	for userID, userNotifications := range notificationsByUserID {
+		if s, exists := settingsByUserID[userID]; exists && s.Delivery != types.ImmediateNotificationDelivery {
+			digestEmails[userID] = userNotifications
+		} else {
+			immediateEmails[userID] = userNotifications
+		}
+	}
+
+	err = queueEmailNotifications(immediateEmails, deliverAfter, useDB)
 	if err != nil {
 		logger.WithError(err).Error("error queuing email notifications")
 	}
 
-	err = queuePushNotification(notificationsByUserID, useDB)
+	err = queueNotificationDigestItems(digestEmails, useDB)
+	if err != nil {
+		logger.WithError(err).Error("error queuing notification digest items")
+	}
+
+	err = queuePushNotification(notificationsByUserID, deliverAfter, useDB)
 	if err != nil {
 		logger.WithError(err).Error("error queuing push notifications")
 	}
@@ -373,18 +436,25 @@ func queueNotifications(notificationsByUserID map[uint64]map[types.EventName][]t
 		logger.WithError(err).Error("error queuing webhook notifications")
 	}
 
+	err = queueChatNotifications(notificationsByUserID, deliverAfter, useDB)
+	if err != nil {
+		logger.WithError(err).Error("error queuing chat notifications")
+	}
+
+	// a subscription can have several notifications, it is marked with the latest epoch so none of them is sent twice
+	epochBySub := map[uint64]uint64{}
 	for _, events := range notificationsByUserID {
 		for _, notifications := range events {
 			for _, n := range notifications {
-				e := n.GetEpoch()
-				if _, exists := subByEpoch[e]; !exists {
-					subByEpoch[e] = []uint64{n.GetSubscriptionID()}
-				} else {
-					subByEpoch[e] = append(subByEpoch[e], n.GetSubscriptionID())
+				if e, exists := epochBySub[n.GetSubscriptionID()]; !exists || n.GetEpoch() > e {
+					epochBySub[n.GetSubscriptionID()] = n.GetEpoch()
 				}
 			}
 		}
 	}
+	for subID, e := range epochBySub {
+		subByEpoch[e] = append(subByEpoch[e], subID)
+	}
 	for epoch, subIDs := range subByEpoch {
 		// update that we've queued the subscription (last sent rather means last queued)
 		err := db.UpdateSubscriptionsLastSent(subIDs, time.Now(), epoch, useDB)
@@ -421,6 +491,13 @@ func dispatchNotifications(useDB *sqlx.DB) error {
 		return fmt.Errorf("error sending webhook discord notifications, err: %w", err)
 	}
 
+	for _, channel := range types.ChatNotificationChannels {
+		err = sendChatNotifications(useDB, channel)
+		if err != nil {
+			return fmt.Errorf("error sending %v notifications, err: %w", channel, err)
+		}
+	}
+
 	return nil
 }
 
@@ -432,7 +509,13 @@ func garbageCollectNotificationQueue(useDB *sqlx.DB) error {
 	}
 	defer tx.Rollback()
 
-	rows, err := tx.Exec(`DELETE FROM notification_queue where (sent < now() - INTERVAL '30 minutes') OR (created < now() - INTERVAL '1 hour')`)
+	// unsent webhook and chat messages are kept until their retries are exhausted, messages held back during the
+	// quiet hours of a user expire an hour after they became due
+	rows, err := tx.Exec(`
+		DELETE FROM notification_queue
+		WHERE (sent < now() - INTERVAL '30 minutes')
+			OR (GREATEST(created, next_attempt) < now() - INTERVAL '1 hour' AND channel NOT IN ('webhook', 'webhook_discord', 'telegram', 'slack', 'matrix'))
+			OR (GREATEST(created, next_attempt) < now() - INTERVAL '1 day')`)
 	if err != nil {
 		return fmt.Errorf("error deleting from notification_queue %w", err)
 	}
@@ -441,6 +524,17 @@ func garbageCollectNotificationQueue(useDB *sqlx.DB) error {
 
 	logger.Infof("Deleting %v rows from the notification_queue", rowsAffected)
 
+	_, err = tx.Exec(`DELETE FROM users_webhook_deliveries WHERE ts < now() - INTERVAL '7 days'`)
+	if err != nil {
+		return fmt.Errorf("error deleting from users_webhook_deliveries %w", err)
+	}
+
+	// items of users that switched back to immediate notifications are never sent as a digest
+	_, err = tx.Exec(`DELETE FROM users_notification_digest_items WHERE created < now() - INTERVAL '2 days'`)
+	if err != nil {
+		return fmt.Errorf("error deleting from users_notification_digest_items %w", err)
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return fmt.Errorf("error committing transaction")
@@ -457,7 +551,7 @@ func getNetwork() string {
 	return ""
 }
 
-func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) error {
+func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, deliverAfter map[uint64]sql.NullTime, useDB *sqlx.DB) error {
 	userIDs := []uint64{}
 	for userID := range notificationsByUserID {
 		userIDs = append(userIDs, userID)
@@ -475,7 +569,7 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 			continue
 		}
 
-		go func(userTokens []string, userNotifications map[types.EventName][]types.Notification) {
+		go func(userTokens []string, userNotifications map[types.EventName][]types.Notification, deliverAfter sql.NullTime) {
 			var batch []*messaging.Message
 			for event, ns := range userNotifications {
 				for _, n := range ns {
@@ -516,7 +610,7 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 				Messages: batch,
 			}
 
-			_, err = tx.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES ($1, 'push', $2)`, time.Now(), transitPushContent)
+			_, err = tx.Exec(`INSERT INTO notification_queue (created, channel, content, next_attempt) VALUES ($1, 'push', $2, $3)`, time.Now(), transitPushContent, deliverAfter)
 			if err != nil {
 				logger.WithError(err).Errorf("error writing transit push notification to db")
 				tx.Rollback()
@@ -529,7 +623,7 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 				tx.Rollback()
 				return
 			}
-		}(userTokens, userNotifications)
+		}(userTokens, userNotifications, deliverAfter[userID])
 	}
 	return nil
 }
@@ -543,7 +637,7 @@ func sendPushNotifications(useDB *sqlx.DB) error {
 		sent,
 		channel,
 		content
-	FROM notification_queue where sent is null and channel = 'push' order by created asc`)
+	FROM notification_queue where sent is null and channel = 'push' and (next_attempt is null or next_attempt <= now()) order by created asc`)
 	if err != nil {
 		return fmt.Errorf("error querying notification queue, err: %w", err)
 	}
@@ -577,7 +671,7 @@ func sendPushNotifications(useDB *sqlx.DB) error {
 	return nil
 }
 
-func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) error {
+func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, deliverAfter map[uint64]sql.NullTime, useDB *sqlx.DB) error {
 	userIDs := []uint64{}
 	for userID := range notificationsByUserID {
 		userIDs = append(userIDs, userID)
@@ -596,7 +690,7 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 			// metrics.Errors.WithLabelValues("notifications_mail_not_found").Inc()
 			continue
 		}
-		go func(userEmail string, userNotifications map[types.EventName][]types.Notification) {
+		go func(userEmail string, userNotifications map[types.EventName][]types.Notification, deliverAfter sql.NullTime) {
 			notification := ""
 			othernotifications := ""
 			i := 0
@@ -706,7 +800,7 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 				Attachments: attachments,
 			}
 
-			_, err = tx.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES ($1, 'email', $2)`, time.Now(), transitEmailContent)
+			_, err = tx.Exec(`INSERT INTO notification_queue (created, channel, content, next_attempt) VALUES ($1, 'email', $2, $3)`, time.Now(), transitEmailContent, deliverAfter)
 			if err != nil {
 				logger.WithError(err).Errorf("error writing transit email to db")
 				tx.Rollback()
@@ -718,7 +812,7 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 				tx.Rollback()
 				return
 			}
-		}(userEmail, userNotifications)
+		}(userEmail, userNotifications, deliverAfter[userID])
 	}
 	return nil
 }
@@ -732,7 +826,7 @@ func sendEmailNotifications(useDb *sqlx.DB) error {
 		sent,
 		channel,
 		content
-	FROM notification_queue where sent is null and channel = 'email' order by created asc`)
+	FROM notification_queue where sent is null and channel = 'email' and (next_attempt is null or next_attempt <= now()) order by created asc`)
 	if err != nil {
 		return fmt.Errorf("error querying notification queue, err: %w", err)
 	}
@@ -781,6 +875,8 @@ func sendEmailNotifications(useDb *sqlx.DB) error {
 	return nil
 }
 
+// queueWebhookNotifications will queue the notifications for the webhooks of the users, webhooks usually feed
+// automations and are not held back during the quiet hours of a user
 func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) error {
 	for userID, userNotifications := range notificationsByUserID {
 		var webhooks []types.UserWebhook
@@ -913,26 +1009,12 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 								},
 							}
 						}
-						// reset Retries
-						if w.Retries > 5 && w.LastSent.Valid && w.LastSent.Time.Add(time.Hour).Before(time.Now()) {
-							_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0 WHERE id = $1;`, w.ID)
-							if err != nil {
-								logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
-								continue
-							}
-						} else if w.Retries > 5 && !w.LastSent.Valid {
-							logger.Error("error webhook has more than 5 retries and does not have a valid last_sent timestamp")
+						_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content, idempotency_key) VALUES (now(), $1, $2, $3);`, channel, content, webhookIdempotencyKey(w.ID, n))
+						if err != nil {
+							logger.WithError(err).Errorf("error inserting into webhooks_queue")
 							continue
-						}
-
-						if w.Retries <= 5 {
-							_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES (now(), $1, $2);`, channel, content)
-							if err != nil {
-								logger.WithError(err).Errorf("error inserting into webhooks_queue")
-								continue
-							} else {
-								metrics.NotificationsQueued.WithLabelValues(channel, string(event)).Inc()
-							}
+						} else {
+							metrics.NotificationsQueued.WithLabelValues(channel, string(event)).Inc()
 						}
 
 					}
@@ -943,202 +1025,201 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 	return nil
 }
 
+// webhookIdempotencyKey will return a key that identifies a notification sent to a webhook, it stays the same for all
+// attempts and resends of the message so receivers can drop duplicates
+func webhookIdempotencyKey(webhookID uint64, n types.Notification) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%v:%v:%v:%v:%v", webhookID, n.GetSubscriptionID(), n.GetEventName(), n.GetEpoch(), n.GetInfo(false))))
+	return hex.EncodeToString(h[:])
+}
+
+// webhookMaxAttempts is the number of delivery attempts of a webhook message before it is dropped
+const webhookMaxAttempts = 8
+
+// webhookRetryBackoff is the delay before the second delivery attempt of a webhook message, it doubles with every further attempt
+const webhookRetryBackoff = time.Second * 30
+
+// webhookMaxResponseSize limits the part of the response of a webhook that is stored in the delivery log
+const webhookMaxResponseSize = 4096
+
+// webhookQueueItem is a queued webhook message together with the current url and secret of its webhook
+type webhookQueueItem struct {
+	Id             uint64         `db:"id"`
+	Content        []byte         `db:"content"`
+	Attempts       uint64         `db:"attempts"`
+	IdempotencyKey sql.NullString `db:"idempotency_key"`
+	WebhookID      sql.NullInt64  `db:"webhook_id"`
+	Url            sql.NullString `db:"url"`
+	Secret         []byte         `db:"secret"`
+}
+
 func sendWebhookNotifications(useDB *sqlx.DB) error {
-	var notificationQueueItem []types.TransitWebhook
+	return deliverWebhookNotifications(useDB, types.WebhookNotificationChannel)
+}
 
-	err := useDB.Select(&notificationQueueItem, `SELECT
-		id,
-		created,
-		sent,
-		channel,
-		content
-	FROM notification_queue where sent is null and channel = 'webhook' order by created asc`)
+func sendDiscordNotifications(useDB *sqlx.DB) error {
+	return deliverWebhookNotifications(useDB, types.WebhookDiscordNotificationChannel)
+}
+
+// deliverWebhookNotifications will send the due messages of a webhook channel. Failed messages are retried with an
+// exponential backoff until webhookMaxAttempts is reached, every attempt is recorded in the delivery log of the webhook
+func deliverWebhookNotifications(useDB *sqlx.DB, channel types.NotificationChannel) error {
+	var items []*webhookQueueItem
+	err := useDB.Select(&items, `
+		SELECT
+			nq.id,
+			nq.content,
+			nq.attempts,
+			nq.idempotency_key,
+			uw.id AS webhook_id,
+			uw.url,
+			uw.secret
+		FROM notification_queue nq
+		LEFT JOIN users_webhooks uw ON uw.id = (nq.content->'Webhook'->>'id')::int
+		WHERE nq.sent IS NULL AND nq.channel = $1 AND nq.attempts < $2 AND (nq.next_attempt IS NULL OR nq.next_attempt <= now())
+		ORDER BY nq.created ASC`, channel, webhookMaxAttempts)
 	if err != nil {
 		return fmt.Errorf("error querying notification queue, err: %w", err)
 	}
 	client := &http.Client{Timeout: time.Second * 30}
 
-	logger.Infof("processing %v webhook notifications", len(notificationQueueItem))
-
-	// now := time.Now()
-	for _, n := range notificationQueueItem {
-		// do not retry after 5 attempts
-		if n.Content.Webhook.Retries > 5 {
-			// if n.Content.Webhook.LastSent.Valid && n.Content.Webhook.LastSent.Time.Add(time.Hour*1).Before(now) {
-			// 	_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0 WHERE id = $1;`, n.Content.Webhook.ID)
-			// 	if err != nil {
-			// 		logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
-			// 		continue
-			// 	}
-			// } else {
-			_, err := db.FrontendWriterDB.Exec(`DELETE FROM notification_queue where id = $1`, n.Id)
-			if err != nil {
-				return fmt.Errorf("error deleting from notification queue: %w", err)
-			}
-			continue
-			// }
-		}
-
-		reqBody := new(bytes.Buffer)
+	logger.Infof("processing %v %v notifications", len(items), channel)
 
-		err := json.NewEncoder(reqBody).Encode(n.Content)
+	wg := &sync.WaitGroup{}
+	sem := make(chan struct{}, 10)
+	for _, n := range items {
+		body, err := webhookRequestBody(channel, n.Content)
 		if err != nil {
-			logger.WithError(err).Errorf("error marschalling webhook event")
+			logger.WithError(err).Errorf("error encoding webhook message %v", n.Id)
 		}
-
-		_, err = url.Parse(n.Content.Webhook.Url)
-		if err != nil {
-			_, err := db.FrontendWriterDB.Exec(`DELETE FROM notification_queue where id = $1`, n.Id)
+		// messages of removed webhooks or with an invalid url can never be delivered
+		if err != nil || !n.WebhookID.Valid || !isValidWebhookUrl(n.Url.String) {
+			_, err := useDB.Exec(`DELETE FROM notification_queue WHERE id = $1`, n.Id)
 			if err != nil {
 				return fmt.Errorf("error deleting from notification queue: %w", err)
 			}
 			continue
 		}
 
-		go func(n types.TransitWebhook) {
-			if n.Content.Webhook.Retries > 0 {
-				time.Sleep(time.Duration(n.Content.Webhook.Retries) * time.Second)
-			}
-			resp, err := client.Post(n.Content.Webhook.Url, "application/json", reqBody)
-			if err != nil {
-				logger.WithError(err).Errorf("error sending request")
-			} else {
-				metrics.NotificationsSent.WithLabelValues("webhook", resp.Status).Inc()
-			}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n *webhookQueueItem, body []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			deliverWebhookMessage(useDB, client, channel, n, body)
+		}(n, body)
+	}
+	wg.Wait()
+	return nil
+}
 
-			if resp != nil && resp.StatusCode < 400 {
-				_, err := useDB.Exec(`UPDATE notification_queue SET sent = now();`)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating notification_queue table")
-					return
-				}
+// webhookRequestBody will return the body that is posted to the webhook for the content of a queued message
+func webhookRequestBody(channel types.NotificationChannel, content []byte) ([]byte, error) {
+	if channel == types.WebhookDiscordNotificationChannel {
+		var c types.TransitDiscordContent
+		err := json.Unmarshal(content, &c)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(c.DiscordRequest)
+	}
+	var c types.TransitWebhookContent
+	err := json.Unmarshal(content, &c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(c)
+}
 
-				_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0, last_sent = now() WHERE id = $1;`, n.Content.Webhook.ID)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
-					return
-				}
-			} else {
-				var errResp types.ErrorResponse
+func isValidWebhookUrl(rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
 
-				if resp != nil {
-					b, err := io.ReadAll(resp.Body)
-					if err != nil {
-						logger.WithError(err).Error("error reading body")
-					}
+// signWebhookMessage will return the hex encoded hmac-sha256 of the timestamp and body of a request, receivers verify
+// it with the secret of their webhook. The timestamp is part of the signature to prevent replays of old requests.
+// The hmac is keyed with the hex encoded secret exactly as it is shown to the user, not with the decoded bytes.
+func signWebhookMessage(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
 
-					errResp.Status = resp.Status
-					errResp.Body = string(b)
-				}
+// deliverWebhookMessage will post a single message to its webhook, record the attempt in the delivery log and either
+// mark the message as sent or schedule its next attempt
+func deliverWebhookMessage(useDB *sqlx.DB, client *http.Client, channel types.NotificationChannel, n *webhookQueueItem, body []byte) {
+	attempt := n.Attempts + 1
+	var statusCode sql.NullInt64
+	var response, deliveryErr sql.NullString
+	var errResp types.ErrorResponse
 
-				_, err = useDB.Exec(`UPDATE users_webhooks SET retries = retries + 1, last_sent = now(), request = $2, response = $3 WHERE id = $1;`, n.Content.Webhook.ID, n.Content, errResp)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating users_webhooks table; increasing retries")
-					return
-				}
-			}
-		}(n)
+	resp, err := postWebhookMessage(client, n, body)
+	if err != nil {
+		logger.WithError(err).Errorf("error sending request")
+		deliveryErr = sql.NullString{String: err.Error(), Valid: true}
+	} else {
+		defer resp.Body.Close()
+		metrics.NotificationsSent.WithLabelValues(string(channel), resp.Status).Inc()
 
+		statusCode = sql.NullInt64{Int64: int64(resp.StatusCode), Valid: true}
+		b, err := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseSize))
+		if err != nil {
+			logger.WithError(err).Error("error reading body")
+		}
+		response = sql.NullString{String: string(b), Valid: true}
+		errResp.Status = resp.Status
+		errResp.Body = string(b)
 	}
-	return nil
-}
-
-func sendDiscordNotifications(useDB *sqlx.DB) error {
-	var notificationQueueItem []types.TransitDiscord
 
-	err := useDB.Select(&notificationQueueItem, `SELECT
-		id,
-		created,
-		sent,
-		channel,
-		content
-	FROM notification_queue where sent is null and channel = 'webhook_discord' order by created asc`)
+	_, err = useDB.Exec(`
+		INSERT INTO users_webhook_deliveries (webhook_id, idempotency_key, channel, content, attempt, ts, status_code, response, error)
+		VALUES ($1, $2, $3, $4, $5, now(), $6, $7, $8)`,
+		n.WebhookID.Int64, n.IdempotencyKey.String, channel, string(n.Content), attempt, statusCode, response, deliveryErr)
 	if err != nil {
-		return fmt.Errorf("error querying notification queue, err: %w", err)
+		logger.WithError(err).Errorf("error inserting into users_webhook_deliveries")
 	}
-	client := &http.Client{Timeout: time.Second * 30}
-
-	logger.Infof("processing %v discord webhook notifications", len(notificationQueueItem))
-	// now := time.Now()
-	for _, n := range notificationQueueItem {
-
-		// rate limit for 1 hour after 5 retries
-		if n.Content.Webhook.Retries > 5 {
-			// if n.Content.Webhook.LastSent.Valid && n.Content.Webhook.LastSent.Time.Add(time.Hour*1).Before(now) {
-			// 	_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0 WHERE id = $1;`, n.Content.Webhook.ID)
-			// 	if err != nil {
-			// 		logger.WithError(err).Errorf("error updating users_webhooks table; resetting retries")
-			// 		continue
-			// 	}
-			// } else {
-			_, err := db.FrontendWriterDB.Exec(`DELETE FROM notification_queue where id = $1`, n.Id)
-			if err != nil {
-				return fmt.Errorf("error deleting from notification queue: %w", err)
-			}
-			continue
-			// }
-		}
 
-		reqBody := new(bytes.Buffer)
-		err := json.NewEncoder(reqBody).Encode(n.Content.DiscordRequest)
+	if statusCode.Valid && statusCode.Int64 < 400 {
+		_, err := useDB.Exec(`UPDATE notification_queue SET sent = now(), attempts = $2 WHERE id = $1;`, n.Id, attempt)
 		if err != nil {
-			logger.WithError(err).Errorf("error marschalling webhook event")
+			logger.WithError(err).Errorf("error updating notification_queue table")
+			return
 		}
 
-		_, err = url.Parse(n.Content.Webhook.Url)
+		_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0, last_sent = now() WHERE id = $1;`, n.WebhookID.Int64)
 		if err != nil {
-			_, err := db.FrontendWriterDB.Exec(`DELETE FROM notification_queue where id = $1`, n.Id)
-			if err != nil {
-				return fmt.Errorf("error deleting from notification queue: %w", err)
-			}
-			continue
+			logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
 		}
+		return
+	}
 
-		go func(n types.TransitDiscord) {
-			if n.Content.Webhook.Retries > 0 {
-				time.Sleep(time.Duration(n.Content.Webhook.Retries) * time.Second)
-			}
-
-			resp, err := client.Post(n.Content.Webhook.Url, "application/json", reqBody)
-			if err != nil {
-				logger.WithError(err).Errorf("error sending request")
-			} else {
-				metrics.NotificationsSent.WithLabelValues("webhook_discord", resp.Status).Inc()
-			}
-			if resp != nil && resp.StatusCode < 400 {
-				_, err := useDB.Exec(`UPDATE notification_queue SET sent = now();`)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating notification_queue table")
-					return
-				}
-				_, err = useDB.Exec(`UPDATE users_webhooks SET retries = 0, last_sent = now() WHERE id = $1;`, n.Content.Webhook.ID)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating users_webhooks table; setting retries to zero")
-					return
-				}
-			} else {
-				var errResp types.ErrorResponse
-
-				if resp != nil {
-					b, err := io.ReadAll(resp.Body)
-					if err != nil {
-						logger.WithError(err).Error("error reading body")
-					} else {
-						errResp.Body = string(b)
-					}
-					errResp.Status = resp.Status
-				}
+	nextAttempt := time.Now().Add(webhookRetryBackoff * time.Duration(uint64(1)<<(attempt-1)))
+	_, err = useDB.Exec(`UPDATE notification_queue SET attempts = $2, next_attempt = $3 WHERE id = $1;`, n.Id, attempt, nextAttempt)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating notification_queue table; scheduling next attempt")
+		return
+	}
 
-				_, err = useDB.Exec(`UPDATE users_webhooks SET retries = retries + 1, last_sent = now(), request = $2, response = $3 WHERE id = $1;`, n.Content.Webhook.ID, n.Content.DiscordRequest, errResp)
-				if err != nil {
-					logger.WithError(err).Errorf("error updating users_webhooks table; increasing retries")
-					return
-				}
-			}
-		}(n)
+	_, err = useDB.Exec(`UPDATE users_webhooks SET retries = retries + 1, last_sent = now(), request = $2, response = $3 WHERE id = $1;`, n.WebhookID.Int64, string(body), errResp)
+	if err != nil {
+		logger.WithError(err).Errorf("error updating users_webhooks table; increasing retries")
+	}
+}
 
+func postWebhookMessage(client *http.Client, n *webhookQueueItem, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, n.Url.String, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signWebhookMessage(hex.EncodeToString(n.Secret), timestamp, body))
+	if n.IdempotencyKey.Valid {
+		req.Header.Set("Idempotency-Key", n.IdempotencyKey.String)
+	}
+	return client.Do(req)
 }
 
 type validatorBalanceDecreasedNotification struct {
@@ -2771,3 +2852,616 @@ type WebhookQueue struct {
 	Payload        []byte         `db:"payload"`
 	LastTry        time.Time      `db:"last_try"`
 }
+
+// validatorStateChangeLookback is the number of epochs in which state changes, deposits and slashings are considered for notifications
+const validatorStateChangeLookback = 20
+
+// addNotification will add a notification for a user to the collected notifications
+func addNotification(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, userID uint64, n types.Notification) {
+	if _, exists := notificationsByUserID[userID]; !exists {
+		notificationsByUserID[userID] = map[types.EventName][]types.Notification{}
+	}
+	if _, exists := notificationsByUserID[userID][n.GetEventName()]; !exists {
+		notificationsByUserID[userID][n.GetEventName()] = []types.Notification{}
+	}
+	notificationsByUserID[userID][n.GetEventName()] = append(notificationsByUserID[userID][n.GetEventName()], n)
+	metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+}
+
+// subscriptionWantsEvent checks that the subscription existed at the epoch of the event and has not been notified about it yet
+func subscriptionWantsEvent(sub types.Subscription, epoch uint64) bool {
+	if epoch < sub.CreatedEpoch {
+		return false
+	}
+	return sub.LastEpoch == nil || *sub.LastEpoch < epoch
+}
+
+const (
+	validatorStateActive       = "active"
+	validatorStateExiting      = "exiting"
+	validatorStateExited       = "exited"
+	validatorStateWithdrawable = "withdrawable"
+)
+
+type validatorStateChangedNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	State           string
+	ExitEpoch       uint64
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorStateChangedNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorStateChangedNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorStateChangedNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorStateChangedNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorStateChangedNotification) GetEventName() types.EventName {
+	return types.ValidatorStateChangedEventName
+}
+
+func (n *validatorStateChangedNotification) GetInfo(includeUrl bool) string {
+	generalPart := ""
+	switch n.State {
+	case validatorStateActive:
+		generalPart = fmt.Sprintf(`Validator %[1]v has been activated at epoch %[2]v.`, n.ValidatorIndex, n.Epoch)
+	case validatorStateExiting:
+		generalPart = fmt.Sprintf(`Validator %[1]v is exiting and will exit at epoch %[2]v.`, n.ValidatorIndex, n.ExitEpoch)
+	case validatorStateExited:
+		generalPart = fmt.Sprintf(`Validator %[1]v has exited at epoch %[2]v.`, n.ValidatorIndex, n.Epoch)
+	case validatorStateWithdrawable:
+		generalPart = fmt.Sprintf(`Validator %[1]v is withdrawable since epoch %[2]v, its balance will be withdrawn by the next withdrawal sweep.`, n.ValidatorIndex, n.Epoch)
+	}
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *validatorStateChangedNotification) GetTitle() string {
+	switch n.State {
+	case validatorStateActive:
+		return "Validator Activated"
+	case validatorStateExiting:
+		return "Validator Exiting"
+	case validatorStateExited:
+		return "Validator Exited"
+	case validatorStateWithdrawable:
+		return "Validator Withdrawable"
+	}
+	return "-"
+}
+
+func (n *validatorStateChangedNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorStateChangedNotification) GetInfoMarkdown() string {
+	generalPart := ""
+	switch n.State {
+	case validatorStateActive:
+		generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) has been activated at epoch [%[2]v](https://%[3]v/epoch/%[2]v).`, n.ValidatorIndex, n.Epoch, utils.Config.Frontend.SiteDomain)
+	case validatorStateExiting:
+		generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) is exiting and will exit at epoch %[2]v.`, n.ValidatorIndex, n.ExitEpoch, utils.Config.Frontend.SiteDomain)
+	case validatorStateExited:
+		generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) has exited at epoch [%[2]v](https://%[3]v/epoch/%[2]v).`, n.ValidatorIndex, n.Epoch, utils.Config.Frontend.SiteDomain)
+	case validatorStateWithdrawable:
+		generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) is withdrawable since epoch [%[2]v](https://%[3]v/epoch/%[2]v), its balance will be withdrawn by the next withdrawal sweep.`, n.ValidatorIndex, n.Epoch, utils.Config.Frontend.SiteDomain)
+	}
+	return generalPart
+}
+
+// collectValidatorStateChangedNotifications will create notifications for validators that became active, started exiting,
+// exited or became withdrawable within the lookback. The exit of a validator is initiated in the epoch its voluntary exit
+// was included, for slashed and ejected validators the earliest possible epoch given its exit epoch is assumed.
+func collectValidatorStateChangedNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if latestEpoch == 0 {
+		return nil
+	}
+	lookBack := uint64(0)
+	if latestEpoch > validatorStateChangeLookback {
+		lookBack = latestEpoch - validatorStateChangeLookback
+	}
+
+	type dbResult struct {
+		ValidatorIndex    uint64        `db:"validatorindex"`
+		Pubkey            []byte        `db:"pubkey"`
+		ActivationEpoch   uint64        `db:"activationepoch"`
+		ExitEpoch         uint64        `db:"exitepoch"`
+		WithdrawableEpoch uint64        `db:"withdrawableepoch"`
+		ExitRequestEpoch  sql.NullInt64 `db:"exit_request_epoch"`
+	}
+
+	pubkeys, subMap, err := db.GetSubsForEventFilter(types.ValidatorStateChangedEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for validator state changes %w", err)
+	}
+
+	events := make([]dbResult, 0)
+	batchSize := 5000
+	dataLen := len(pubkeys)
+	for i := 0; i < dataLen; i += batchSize {
+		end := i + batchSize
+		if dataLen < end {
+			end = dataLen
+		}
+
+		var partial []dbResult
+		err = db.WriterDb.Select(&partial, `
+			SELECT
+				v.validatorindex,
+				v.pubkey,
+				v.activationepoch,
+				v.exitepoch,
+				v.withdrawableepoch,
+				(
+					SELECT MIN(b.epoch)
+					FROM blocks_voluntaryexits ve
+					INNER JOIN blocks b ON b.slot = ve.block_slot AND b.status = '1'
+					WHERE ve.validatorindex = v.validatorindex
+				) AS exit_request_epoch
+			FROM validators v
+			WHERE v.pubkey = ANY($1) AND (v.activationepoch BETWEEN $2 AND $3 OR (v.exitepoch <> 9223372036854775807 AND v.withdrawableepoch >= $2))`,
+			pq.ByteaArray(pubkeys[i:end]), lookBack, latestEpoch)
+		if err != nil {
+			return err
+		}
+		events = append(events, partial...)
+	}
+
+	exitDelay := 1 + utils.Config.Chain.Config.MaxSeedLookahead
+	for _, event := range events {
+		transitions := map[string]uint64{}
+		transitions[validatorStateActive] = event.ActivationEpoch
+		if event.ExitEpoch != 9223372036854775807 {
+			if event.ExitRequestEpoch.Valid {
+				transitions[validatorStateExiting] = uint64(event.ExitRequestEpoch.Int64)
+			} else if event.ExitEpoch > exitDelay {
+				transitions[validatorStateExiting] = event.ExitEpoch - exitDelay
+			}
+			transitions[validatorStateExited] = event.ExitEpoch
+			transitions[validatorStateWithdrawable] = event.WithdrawableEpoch
+		}
+
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if !ok {
+			return fmt.Errorf("error event returned that does not exist: %x", event.Pubkey)
+		}
+		for state, epoch := range transitions {
+			if epoch < lookBack || epoch > latestEpoch {
+				continue
+			}
+			for _, sub := range subscribers {
+				if sub.UserID == nil || sub.ID == nil {
+					return fmt.Errorf("error expected userId or subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+				}
+				if !subscriptionWantsEvent(sub, epoch) {
+					continue
+				}
+				addNotification(notificationsByUserID, *sub.UserID, &validatorStateChangedNotification{
+					SubscriptionID: *sub.ID,
+					ValidatorIndex: event.ValidatorIndex,
+					Epoch:          epoch,
+					State:          state,
+					ExitEpoch:      event.ExitEpoch,
+					EventFilter:    hex.EncodeToString(event.Pubkey),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+type validatorReceivedDepositNotification struct {
+	SubscriptionID     uint64
+	ValidatorIndex     sql.NullInt64
+	ValidatorPublicKey string
+	Epoch              uint64
+	Amount             uint64
+	// IncludedInBlock is true if the deposit was processed by the beacon chain, otherwise it was only made on the execution layer
+	IncludedInBlock bool
+	ValidSignature  bool
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorReceivedDepositNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorReceivedDepositNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorReceivedDepositNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorReceivedDepositNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorReceivedDepositNotification) GetEventName() types.EventName {
+	return types.ValidatorReceivedDepositEventName
+}
+
+// validatorName returns the index of the validator or its public key if it is not in the beacon state yet
+func (n *validatorReceivedDepositNotification) validatorName() string {
+	if n.ValidatorIndex.Valid {
+		return fmt.Sprintf("%v", n.ValidatorIndex.Int64)
+	}
+	return "0x" + n.ValidatorPublicKey
+}
+
+func (n *validatorReceivedDepositNotification) GetInfo(includeUrl bool) string {
+	amount := fmt.Sprintf("%.4f BOA", float64(n.Amount)/1e9)
+	generalPart := ""
+	if n.IncludedInBlock {
+		generalPart = fmt.Sprintf(`A deposit of %[2]v for validator %[1]v has been processed at epoch %[3]v.`, n.validatorName(), amount, n.Epoch)
+	} else {
+		generalPart = fmt.Sprintf(`A deposit of %[2]v for validator %[1]v has been made on the execution layer and will be processed by the beacon chain.`, n.validatorName(), amount)
+		if !n.ValidSignature {
+			generalPart += ` The signature of the deposit is invalid.`
+		}
+	}
+	if includeUrl {
+		return generalPart + fmt.Sprintf(` For more information visit: https://%[2]s/validator/%[1]v`, n.validatorName(), utils.Config.Frontend.SiteDomain)
+	}
+	return generalPart
+}
+
+func (n *validatorReceivedDepositNotification) GetTitle() string {
+	if n.IncludedInBlock {
+		return "Deposit Processed"
+	}
+	return "Deposit Received"
+}
+
+func (n *validatorReceivedDepositNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorReceivedDepositNotification) GetInfoMarkdown() string {
+	amount := fmt.Sprintf("%.4f BOA", float64(n.Amount)/1e9)
+	generalPart := ""
+	if n.IncludedInBlock {
+		generalPart = fmt.Sprintf(`A deposit of %[2]v for validator [%[1]v](https://%[4]v/validator/%[1]v) has been processed at epoch [%[3]v](https://%[4]v/epoch/%[3]v).`, n.validatorName(), amount, n.Epoch, utils.Config.Frontend.SiteDomain)
+	} else {
+		generalPart = fmt.Sprintf(`A deposit of %[2]v for validator [%[1]v](https://%[3]v/validator/%[1]v) has been made on the execution layer and will be processed by the beacon chain.`, n.validatorName(), amount, utils.Config.Frontend.SiteDomain)
+		if !n.ValidSignature {
+			generalPart += ` The signature of the deposit is invalid.`
+		}
+	}
+	return generalPart
+}
+
+// collectValidatorReceivedDepositNotifications will create notifications for deposits to watched public keys that were made on the
+// execution layer or processed by the beacon chain within the lookback
+func collectValidatorReceivedDepositNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if latestEpoch == 0 {
+		return nil
+	}
+	lookBack := uint64(0)
+	if latestEpoch > validatorStateChangeLookback {
+		lookBack = latestEpoch - validatorStateChangeLookback
+	}
+
+	type dbResult struct {
+		ValidatorIndex  sql.NullInt64 `db:"validatorindex"`
+		Pubkey          []byte        `db:"pubkey"`
+		Amount          uint64        `db:"amount"`
+		Epoch           uint64        `db:"epoch"`
+		IncludedInBlock bool          `db:"included_in_block"`
+		ValidSignature  bool          `db:"valid_signature"`
+	}
+
+	pubkeys, subMap, err := db.GetSubsForEventFilter(types.ValidatorReceivedDepositEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for received deposits %w", err)
+	}
+
+	events := make([]dbResult, 0)
+	batchSize := 5000
+	dataLen := len(pubkeys)
+	for i := 0; i < dataLen; i += batchSize {
+		end := i + batchSize
+		if dataLen < end {
+			end = dataLen
+		}
+
+		var partial []dbResult
+		err = db.WriterDb.Select(&partial, `
+			SELECT v.validatorindex, d.pubkey, d.amount, d.epoch, d.included_in_block, d.valid_signature
+			FROM (
+				SELECT
+					bd.publickey AS pubkey,
+					bd.amount,
+					b.epoch,
+					true AS included_in_block,
+					true AS valid_signature
+				FROM blocks_deposits bd
+				INNER JOIN blocks b ON b.slot = bd.block_slot AND b.status = '1'
+				WHERE bd.publickey = ANY($1) AND bd.block_slot >= $2 * $4
+				UNION ALL
+				SELECT
+					ed.publickey AS pubkey,
+					ed.amount,
+					GREATEST(FLOOR((EXTRACT(EPOCH FROM ed.block_ts) - $5) / ($4 * $6)), 0)::bigint AS epoch,
+					false AS included_in_block,
+					ed.valid_signature
+				FROM eth1_deposits ed
+				WHERE ed.publickey = ANY($1) AND NOT ed.removed AND ed.block_ts >= TO_TIMESTAMP($5 + $2 * $4 * $6)
+			) d
+			LEFT JOIN validators v ON v.pubkey = d.pubkey
+			WHERE d.epoch <= $3`,
+			pq.ByteaArray(pubkeys[i:end]), lookBack, latestEpoch, utils.Config.Chain.Config.SlotsPerEpoch, utils.Config.Chain.GenesisTimestamp, utils.Config.Chain.Config.SecondsPerSlot)
+		if err != nil {
+			return err
+		}
+		events = append(events, partial...)
+	}
+
+	for _, event := range events {
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if !ok {
+			return fmt.Errorf("error event returned that does not exist: %x", event.Pubkey)
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId or subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if !subscriptionWantsEvent(sub, event.Epoch) {
+				continue
+			}
+			addNotification(notificationsByUserID, *sub.UserID, &validatorReceivedDepositNotification{
+				SubscriptionID:     *sub.ID,
+				ValidatorIndex:     event.ValidatorIndex,
+				ValidatorPublicKey: hex.EncodeToString(event.Pubkey),
+				Epoch:              event.Epoch,
+				Amount:             event.Amount,
+				IncludedInBlock:    event.IncludedInBlock,
+				ValidSignature:     event.ValidSignature,
+				EventFilter:        hex.EncodeToString(event.Pubkey),
+			})
+		}
+	}
+
+	return nil
+}
+
+type validatorDidSlashNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	Slashed         uint64
+	Reason          string
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorDidSlashNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorDidSlashNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorDidSlashNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorDidSlashNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorDidSlashNotification) GetEventName() types.EventName {
+	return types.ValidatorDidSlashEventName
+}
+
+func (n *validatorDidSlashNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`Validator %[1]v has slashed validator %[3]v at epoch %[2]v for %[4]s.`, n.ValidatorIndex, n.Epoch, n.Slashed, n.Reason)
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *validatorDidSlashNotification) GetTitle() string {
+	return "Validator did Slash"
+}
+
+func (n *validatorDidSlashNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorDidSlashNotification) GetInfoMarkdown() string {
+	generalPart := fmt.Sprintf(`Validator [%[1]v](https://%[5]v/validator/%[1]v) has slashed validator [%[3]v](https://%[5]v/validator/%[3]v) at epoch [%[2]v](https://%[5]v/epoch/%[2]v) for %[4]s.`, n.ValidatorIndex, n.Epoch, n.Slashed, n.Reason, utils.Config.Frontend.SiteDomain)
+	return generalPart
+}
+
+// collectValidatorDidSlashNotifications will create notifications for slashings that were included in blocks of watched proposers
+func collectValidatorDidSlashNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if latestEpoch == 0 {
+		return nil
+	}
+	lookBack := uint64(0)
+	if latestEpoch > validatorStateChangeLookback {
+		lookBack = latestEpoch - validatorStateChangeLookback
+	}
+
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorDidSlashEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for slashings %w", err)
+	}
+	if len(subMap) == 0 {
+		return nil
+	}
+
+	slashings, err := db.GetValidatorsGotSlashed(lookBack)
+	if err != nil {
+		return fmt.Errorf("error getting slashed validators from database, err: %w", err)
+	}
+
+	for _, slashing := range slashings {
+		eventFilter := hex.EncodeToString([]byte(slashing.SlasherPubkey))
+		for _, sub := range subMap[eventFilter] {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId or subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if !subscriptionWantsEvent(sub, slashing.Epoch) {
+				continue
+			}
+			addNotification(notificationsByUserID, *sub.UserID, &validatorDidSlashNotification{
+				SubscriptionID: *sub.ID,
+				ValidatorIndex: slashing.SlasherIndex,
+				Epoch:          slashing.Epoch,
+				Slashed:        slashing.SlashedValidatorIndex,
+				Reason:         slashing.Reason,
+				EventFilter:    eventFilter,
+			})
+		}
+	}
+
+	return nil
+}
+
+type validatorWithdrawalNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	Slot            uint64
+	Amount          uint64
+	Address         []byte
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorWithdrawalNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorWithdrawalNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorWithdrawalNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorWithdrawalNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorWithdrawalNotification) GetEventName() types.EventName {
+	return types.ValidatorWithdrawalEventName
+}
+
+func (n *validatorWithdrawalNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`A withdrawal of %.4[2]f BOA of validator %[1]v has been processed in slot %[3]v to address 0x%[4]x.`, n.ValidatorIndex, float64(n.Amount)/1e9, n.Slot, n.Address)
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *validatorWithdrawalNotification) GetTitle() string {
+	return "Withdrawal Processed"
+}
+
+func (n *validatorWithdrawalNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorWithdrawalNotification) GetInfoMarkdown() string {
+	generalPart := fmt.Sprintf(`A withdrawal of %.4[2]f BOA of validator [%[1]v](https://%[5]v/validator/%[1]v) has been processed in slot [%[3]v](https://%[5]v/block/%[3]v) to address [0x%[4]x](https://%[5]v/address/0x%[4]x).`, n.ValidatorIndex, float64(n.Amount)/1e9, n.Slot, n.Address, utils.Config.Frontend.SiteDomain)
+	return generalPart
+}
+
+// collectWithdrawalNotifications will create notifications for the withdrawals of the recent epochs. A subscription either watches
+// a validator by its public key or an execution address, the withdrawal is only notified if its amount reaches the threshold of the subscription
+func collectWithdrawalNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if !utils.ForkFeatureActive(utils.ForkFeatureWithdrawals, latestEpoch) {
+		return nil
+	}
+
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorWithdrawalEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for withdrawals %w", err)
+	}
+	if len(subMap) == 0 {
+		return nil
+	}
+
+	lookBack := uint64(0)
+	if latestEpoch > 5 {
+		lookBack = latestEpoch - 5
+	}
+	// the latest epoch may still be in progress, its withdrawals are collected once the next epoch has been exported
+	for epoch := lookBack; epoch < latestEpoch; epoch++ {
+		withdrawals, err := db.GetEpochWithdrawals(epoch)
+		if err != nil {
+			return err
+		}
+
+		for _, w := range withdrawals {
+			// a user watching both the validator and its withdrawal address is only notified once
+			notified := map[uint64]bool{}
+			for _, filter := range []string{hex.EncodeToString(w.Pubkey), hex.EncodeToString(w.Address)} {
+				for _, sub := range subMap[filter] {
+					if sub.UserID == nil || sub.ID == nil {
+						return fmt.Errorf("error expected userId or subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+					}
+					if notified[*sub.UserID] || !subscriptionWantsEvent(sub, epoch) {
+						continue
+					}
+					if float64(w.Amount) < sub.EventThreshold*1e9 {
+						continue
+					}
+					notified[*sub.UserID] = true
+					addNotification(notificationsByUserID, *sub.UserID, &validatorWithdrawalNotification{
+						SubscriptionID: *sub.ID,
+						ValidatorIndex: w.ValidatorIndex,
+						Epoch:          epoch,
+						Slot:           w.Slot,
+						Amount:         w.Amount,
+						Address:        w.Address,
+						EventFilter:    filter,
+					})
+				}
+			}
+		}
+	}
+
+	return nil
+}
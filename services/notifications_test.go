@@ -0,0 +1,94 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// verifyWebhookSignature verifies a signature the way a receiver does, with the secret as it is shown to the user
+func verifyWebhookSignature(displayedSecret, timestamp, signature string, body []byte) bool {
+	mac := hmac.New(sha256.New, []byte(displayedSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hmac.Equal([]byte(signature), []byte("sha256="+hex.EncodeToString(mac.Sum(nil))))
+}
+
+func TestSignWebhookMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    []byte
+		timestamp string
+		body      []byte
+		want      string
+	}{
+		{
+			name:      "empty body",
+			secret:    []byte{0xde, 0xad, 0xbe, 0xef},
+			timestamp: "1700000000",
+			body:      []byte{},
+			want:      "0ad535a3f3dcc86270d23707d08978e27f906677995883a3190707807dcaa340",
+		},
+		{
+			name:      "json body",
+			secret:    []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			timestamp: "1700000012",
+			body:      []byte(`[{"event":"validator_got_slashed","epoch":123}]`),
+			want:      "d8e6df5f8986cc1fa78b71bc81a3511d20da12a4abdffc39fc22128df7075aae",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			displayed := hex.EncodeToString(tt.secret)
+			signature := signWebhookMessage(displayed, tt.timestamp, tt.body)
+			if signature != tt.want {
+				t.Errorf("expected signature %v, got %v", tt.want, signature)
+			}
+
+			if !verifyWebhookSignature(displayed, tt.timestamp, "sha256="+signature, tt.body) {
+				t.Errorf("signature %v can not be verified with the displayed secret %v", signature, displayed)
+			}
+			if verifyWebhookSignature(displayed, tt.timestamp+"1", "sha256="+signature, tt.body) {
+				t.Errorf("signature %v is valid for another timestamp", signature)
+			}
+			if verifyWebhookSignature(string(tt.secret), tt.timestamp, "sha256="+signature, tt.body) {
+				t.Errorf("signature %v is valid for the decoded secret", signature)
+			}
+		})
+	}
+}
+
+func TestPostWebhookMessageSignature(t *testing.T) {
+	secret := []byte{0x0a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f}
+	body := []byte(`[{"event":"validator_balance_decreased","epoch":42}]`)
+
+	verified := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		verified = verifyWebhookSignature(hex.EncodeToString(secret), r.Header.Get("X-Webhook-Timestamp"), r.Header.Get("X-Webhook-Signature"), b)
+		if r.Header.Get("Idempotency-Key") != "key" {
+			t.Errorf("unexpected idempotency key %q", r.Header.Get("Idempotency-Key"))
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := postWebhookMessage(srv.Client(), &webhookQueueItem{
+		Url:            sql.NullString{String: srv.URL, Valid: true},
+		Secret:         secret,
+		IdempotencyKey: sql.NullString{String: "key", Valid: true},
+	}, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if !verified {
+		t.Errorf("the receiver could not verify the signature with the displayed secret")
+	}
+}
@@ -47,6 +47,27 @@ func GetValidatorHist(validatorArr []uint64, currency string, start uint64, end
 		logger.Errorf("error getting incomes: %v", err)
 	}
 
+	// priority fees of the proposed blocks in gwei per day
+	var execIncome []struct {
+		Day  int64 `db:"day"`
+		Fees int64 `db:"fees"`
+	}
+	slotsPerDay := 24 * 60 * 60 / utils.Config.Chain.Config.SecondsPerSlot
+	err = db.WriterDb.Select(&execIncome,
+		`select slot / $4 as day, trunc(sum(exec_priority_fees) / 1000000000)::bigint as fees
+		 from blocks
+		 where proposer=ANY($1) AND status = '1' AND exec_priority_fees is not null AND slot / $4 > $2 AND slot / $4 <= $3
+		 group by slot / $4`, validatorFilter, lowerBound, upperBound, slotsPerDay)
+	if err != nil {
+		logger.Errorf("error getting execution incomes: %v", err)
+	}
+	execIncomePerDay := map[string]int64{}
+	for _, item := range execIncome {
+		date := fmt.Sprintf("%v", utils.DayToTime(item.Day))
+		date = strings.Split(date, " ")[0]
+		execIncomePerDay[date] = item.Fees
+	}
+
 	prices := map[string]float64{}
 	for _, item := range pricesDb {
 		date := fmt.Sprintf("%v", item.TS)
@@ -97,13 +118,15 @@ func GetValidatorHist(validatorArr []uint64, currency string, start uint64, end
 			continue
 		}
 		iETH := (float64(item[1]) / 1e9) - (float64(item[0]) / 1e9)
-		tETH += iETH
-		iCur := ((float64(item[1]) / 1e9) - (float64(item[0]) / 1e9)) * prices[key]
+		iExecETH := float64(execIncomePerDay[key]) / 1e9
+		tETH += iETH + iExecETH
+		iCur := (iETH + iExecETH) * prices[key]
 		tCur += iCur
 		data[i] = []string{
 			key,
 			addCommas(float64(item[1])/1e9, "%.5f"), // end of day balance
-			addCommas(iETH, "%.5f"),                 // income of day ETH
+			addCommas(iETH, "%.5f"),                 // consensus income of day ETH
+			addCommas(iExecETH, "%.5f"),             // execution income of day ETH
 			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(prices[key], "%.2f")), //price will default to 0 if key does not exist
 			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(iCur, "%.2f")),        // income of day Currency
 		}
@@ -169,11 +192,10 @@ func GeneratePdfReport(hist rewardHistory) []byte {
 
 	// generating the table
 	const (
-		colCount = 5
-		colWd    = 40.0
-		marginH  = 5.0
-		lineHt   = 5.5
-		maxHt    = 5
+		colWd   = 32.0
+		marginH = 5.0
+		lineHt  = 5.5
+		maxHt   = 5
 	)
 
 	pdf.SetTextColor(24, 24, 24)
@@ -181,7 +203,8 @@ func GeneratePdfReport(hist rewardHistory) []byte {
 	// pdf.Ln(-1)
 	pdf.CellFormat(0, maxHt, fmt.Sprintf("Total Income BOA %s | %s", hist.TotalETH, hist.TotalCurrency), "", 0, "CM", true, 0, "")
 
-	header := [colCount]string{"Date", "End-of-date balance BOA", "Income for date BOA", "Price of BOA for date", "Income for date"}
+	header := []string{"Date", "End-of-date balance BOA", "Consensus income BOA", "Execution income BOA", "Price of BOA for date", "Income for date"}
+	colCount := len(header)
 
 	// pdf.SetMargins(marginH, marginH, marginH)
 	pdf.Ln(10)
@@ -239,7 +262,8 @@ func GeneratePdfReport(hist rewardHistory) []byte {
 	pdf.Ln(10)
 	pdf.SetFont("Times", "", 9)
 
-	header = [colCount]string{"Index", "Balance Activation BOA", "Balance BOA", "Income BOA", "Last Attestation"}
+	header = []string{"Index", "Balance Activation BOA", "Balance BOA", "Income BOA", "Last Attestation"}
+	colCount = len(header)
 
 	// pdf.SetMargins(marginH, marginH, marginH)
 	// pdf.Ln(10)
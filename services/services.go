@@ -62,6 +62,11 @@ func Init() {
 func InitNotifications() {
 	logger.Infof("starting notifications-sender")
 	go notificationsSender()
+
+	if utils.Config.Notifications.TelegramBotToken != "" {
+		logger.Infof("starting telegram chat linker")
+		go telegramChatLinker()
+	}
 }
 
 func epochUpdater() {
@@ -327,7 +332,7 @@ func getIndexPageData() (*types.IndexPageData, error) {
 	}
 
 	var epochs []*types.IndexPageDataEpochs
-	err = db.WriterDb.Select(&epochs, `SELECT epoch, finalized , eligibleether, globalparticipationrate, votedether FROM epochs ORDER BY epochs DESC LIMIT 15`)
+	err = db.WriterDb.Select(&epochs, `SELECT epoch, finalized , COALESCE(eligibleether, 0) AS eligibleether, COALESCE(globalparticipationrate, 0) AS globalparticipationrate, COALESCE(votedether, 0) AS votedether FROM epochs ORDER BY epochs DESC LIMIT 15`)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving index epoch data: %v", err)
 	}
@@ -417,7 +422,7 @@ func getIndexPageData() (*types.IndexPageData, error) {
 		epochLowerBound = epoch - 1600
 	}
 	var epochHistory []*types.IndexPageEpochHistory
-	err = db.WriterDb.Select(&epochHistory, "SELECT epoch, eligibleether, validatorscount, finalized FROM epochs WHERE epoch < $1 and epoch > $2 ORDER BY epoch", epoch, epochLowerBound)
+	err = db.WriterDb.Select(&epochHistory, "SELECT epoch, eligibleether, validatorscount, finalized FROM epochs WHERE epoch < $1 and epoch > $2 AND eligibleether IS NOT NULL ORDER BY epoch", epoch, epochLowerBound)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving staked BOA history: %v", err)
 	}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"time"
+)
+
+// withdrawalSweepPredictionDays is the period the expected payout of an address is calculated for
+const withdrawalSweepPredictionDays = 30
+
+// GetNextWithdrawals will return the predicted next withdrawals of the validators, validators without an expected
+// withdrawal are left out. The predictions are saved by the exporter after every simulation of the withdrawal sweep.
+func GetNextWithdrawals(validators []uint64) ([]*types.WithdrawalPrediction, error) {
+	// predictions before the latest slot are outdated until the next simulation
+	return db.GetWithdrawalPredictions(validators, LatestSlot())
+}
+
+// GetExpectedMonthlyPayout will return the amount in gwei the validators of an address are expected to withdraw within
+// the next 30 days, the second return value is false if no withdrawal of the address has been predicted
+func GetExpectedMonthlyPayout(address []byte) (uint64, bool, error) {
+	predictions, err := db.GetAddressWithdrawalPredictions(address)
+	if err != nil || len(predictions) == 0 {
+		return 0, false, err
+	}
+
+	latestSlot := LatestSlot()
+	endSlot := utils.TimeToSlot(uint64(time.Now().Add(time.Hour * 24 * withdrawalSweepPredictionDays).Unix()))
+	payout := uint64(0)
+	for _, prediction := range predictions {
+		if prediction.Slot > endSlot {
+			continue
+		}
+		if prediction.Slot > latestSlot {
+			payout += prediction.Amount
+		}
+		if prediction.IsFull || prediction.CycleSlots == 0 {
+			continue
+		}
+		// every following cycle withdraws the income of the validator since the previous withdrawal
+		cycles := (endSlot - prediction.Slot) / prediction.CycleSlots
+		payout += cycles * prediction.CycleSlots * prediction.IncomePerSlot
+	}
+	return payout, true, nil
+}
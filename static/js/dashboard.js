@@ -189,17 +189,29 @@ function addValidatorUpdateUI() {
   }).then((res) => {
     res.json().then((data) => {
       let eff = 0.0
-      for (let incDistance of data) {
-        if (incDistance === 0.0) {
-          continue
-        }
-        eff += (1.0 / incDistance) * 100.0
+      for (let validatorEff of data) {
+        eff += validatorEff
       }
       eff = eff / data.length
       setValidatorEffectiveness("validator-eff-total", eff)
     })
   })
+  fetch(`/dashboard/data/attestationcorrectness${getValidatorQueryString()}`, {
+    method: "GET",
+  }).then((res) => {
+    res.json().then((data) => {
+      if (!data.included) {
+        $("#validator-correctness-total").text("-")
+        return
+      }
+      let pct = (rate) => `${(rate * 100).toFixed(1)}%`
+      $("#validator-correctness-total").text(`${pct(data.head_correct)} / ${pct(data.target_correct)} / ${pct(data.source_correct)}`)
+      $("#validator-correctness-total").attr("title", `Average inclusion delay: ${data.avg_inclusion_delay.toFixed(2)} slots`)
+    })
+  })
   showProposedHistoryTable()
+  showWithdrawalCredentialsInfo()
+  showUpcomingDuties()
 }
 
 function showSelectedValidator() {
@@ -931,9 +943,9 @@ $(document).ready(function () {
           // addChange("#earnings-week", result.lastWeek)
           // addChange("#earnings-month", result.lastMonth)
 
-          document.querySelector("#earnings-day").innerHTML = result.lastDayFormatted || "0.000"
-          document.querySelector("#earnings-week").innerHTML = result.lastWeekFormatted || "0.000"
-          document.querySelector("#earnings-month").innerHTML = result.lastMonthFormatted || "0.000"
+          document.querySelector("#earnings-day").innerHTML = (result.lastDayFormatted || "0.000") + execEarnings(result.execLastDay, result.execLastDayFormatted)
+          document.querySelector("#earnings-week").innerHTML = (result.lastWeekFormatted || "0.000") + execEarnings(result.execLastWeek, result.execLastWeekFormatted)
+          document.querySelector("#earnings-month").innerHTML = (result.lastMonthFormatted || "0.000") + execEarnings(result.execLastMonth, result.execLastMonthFormatted)
           document.querySelector("#earnings-total").innerHTML = (result.totalChangeFormatted || "0.000") + ` <span class="d-block" id="earnings-total-change">${result.totalFormatted}</span>`
           $("#earnings-total span:first").removeClass("text-success").removeClass("text-danger")
           $("#earnings-total span:first").html($("#earnings-total span:first").html().replace("+", ""))
@@ -1210,3 +1222,54 @@ function createProposedChart(data) {
     },
   })
 }
+
+function execEarnings(amount, formatted) {
+  if (!amount) return ""
+  return ` <span class="d-block small" data-toggle="tooltip" title="Execution layer rewards (priority fees) of proposed blocks">${formatted} EL</span>`
+}
+
+function showWithdrawalCredentialsInfo() {
+  fetch(`/dashboard/data/withdrawalcredentials${getValidatorQueryString()}`, {
+    method: "GET",
+  }).then((res) => {
+    res.json().then((data) => {
+      let bls = data.filter((v) => v.type === "bls")
+      if (!bls.length) {
+        $("#dashboard-bls-info").addClass("d-none")
+        return
+      }
+      let pending = bls.filter((v) => v.bls_change_status === "pending").length
+      let invalid = bls.filter((v) => v.bls_change_status === "invalid").length
+      let indices = bls.map((v) => v.validatorindex).join(", ")
+      let info = `${bls.length} validator${bls.length > 1 ? "s" : ""} still ${bls.length > 1 ? "have" : "has"} 0x00 withdrawal credentials`
+      if (pending) info += `, ${pending} change${pending > 1 ? "s" : ""} pending`
+      if (invalid) info += `, <span class="text-danger">${invalid} invalid change${invalid > 1 ? "s" : ""}</span>`
+      $("#dashboard-bls-info").html(`<span data-toggle="tooltip" title="${indices}"><i class="fas fa-key mr-1"></i>${info}</span>`)
+      $("#dashboard-bls-info").removeClass("d-none")
+    })
+  })
+}
+
+function showUpcomingDuties() {
+  fetch(`/dashboard/data/duties${getValidatorQueryString()}`, {
+    method: "GET",
+  }).then((res) => {
+    res.json().then((data) => {
+      let rows = []
+      for (let p of data.proposals) {
+        rows.push({ ts: p.timestamp, html: `<tr><td><a href="/validator/${p.validatorindex}">${p.validatorindex}</a></td><td><i class="fas fa-cubes mr-1"></i>Block Proposal</td><td><a href="/block/${p.slot}">${p.slot}</a></td><td>${luxon.DateTime.fromMillis(p.timestamp * 1000).toRelative()}</td></tr>` })
+      }
+      for (let s of data.sync_committees) {
+        let time = s.start_timestamp * 1000 > Date.now() ? luxon.DateTime.fromMillis(s.start_timestamp * 1000).toRelative() : "active"
+        rows.push({ ts: s.start_timestamp, html: `<tr><td><a href="/validator/${s.validatorindex}">${s.validatorindex}</a></td><td><i class="fas fa-sync mr-1"></i>Sync Committee</td><td><a href="/epoch/${s.start_epoch}">${s.start_epoch}</a> - <a href="/epoch/${s.end_epoch}">${s.end_epoch}</a></td><td>${time}</td></tr>` })
+      }
+      if (!rows.length) {
+        $("#dashboard-duties").addClass("d-none")
+        return
+      }
+      rows.sort((a, b) => a.ts - b.ts)
+      $("#dashboard-duties-table tbody").html(rows.map((r) => r.html).join(""))
+      $("#dashboard-duties").removeClass("d-none")
+    })
+  })
+}
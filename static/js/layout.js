@@ -297,7 +297,7 @@ $(document).ready(function () {
 
   $(".typeahead").on("typeahead:select", function (ev, sug) {
     if (sug.slot !== undefined) {
-      if (sug.txhash !== undefined) window.location = "/block/" + sug.slot + "#transactions"
+      if (sug.txhash !== undefined) window.location = "/tx/0x" + sug.txhash
       else window.location = "/block/" + sug.slot
     } else if (sug.index !== undefined) {
       if (sug.index === "deposited") window.location = "/validator/" + sug.pubkey
@@ -305,7 +305,7 @@ $(document).ready(function () {
     } else if (sug.epoch !== undefined) {
       window.location = "/epoch/" + sug.epoch
     } else if (sug.address !== undefined) {
-      window.location = "/validators/eth1deposits?q=" + sug.address
+      window.location = "/address/0x" + sug.address
     } else if (sug.graffiti !== undefined) {
       // sug.graffiti is html-escaped to prevent xss, we need to unescape it
       var el = document.createElement("textarea")
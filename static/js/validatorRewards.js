@@ -224,9 +224,8 @@ function showTable(data) {
       {
         targets: 3,
         data: "3",
-        orderable: false,
+        orderable: true,
         render: function (data, type, row, meta) {
-          // return `${currency} ${addCommas(parseFloat(data).toFixed(DECIMAL_POINTS_CURRENCY))}`
           return data
         },
       },
@@ -234,6 +233,15 @@ function showTable(data) {
         targets: 4,
         data: "4",
         orderable: false,
+        render: function (data, type, row, meta) {
+          // return `${currency} ${addCommas(parseFloat(data).toFixed(DECIMAL_POINTS_CURRENCY))}`
+          return data
+        },
+      },
+      {
+        targets: 5,
+        data: "5",
+        orderable: false,
         render: function (data, type, row, meta) {
           //    return `${currency} ${addCommas(parseFloat(data).toFixed(DECIMAL_POINTS_CURRENCY))}`
           return data
@@ -160,6 +160,124 @@ type ApiValidatorWithdrawalResponse struct {
 	Amount         uint64 `json:"amount"`
 }
 
+type ApiValidatorRewardsResponse struct {
+	Epoch                     uint64 `json:"epoch"`
+	ValidatorIndex            uint64 `json:"validatorindex"`
+	Source                    int64  `json:"source"`
+	Target                    int64  `json:"target"`
+	Head                      int64  `json:"head"`
+	InclusionDelay            int64  `json:"inclusion_delay"`
+	Inactivity                int64  `json:"inactivity"`
+	SyncCommittee             int64  `json:"sync_committee"`
+	ProposerAttestations      int64  `json:"proposer_attestations"`
+	ProposerSyncAggregate     int64  `json:"proposer_sync_aggregate"`
+	ProposerSlashingInclusion int64  `json:"proposer_slashing_inclusion"`
+	Total                     int64  `json:"total"`
+}
+
+type ApiExecutionTransactionLogResponse struct {
+	LogIndex uint64   `json:"log_index"`
+	Address  string   `json:"address"`
+	Topics   []string `json:"topics"`
+	Data     string   `json:"data"`
+}
+
+type ApiExecutionTransactionResponse struct {
+	TxHash               string                                `json:"txhash"`
+	BlockSlot            uint64                                `json:"block_slot"`
+	BlockIndex           uint64                                `json:"block_index"`
+	BlockRoot            string                                `json:"block_root"`
+	BlockNumber          uint64                                `json:"block_number"`
+	Orphaned             bool                                  `json:"orphaned"`
+	From                 string                                `json:"from"`
+	To                   string                                `json:"to"`
+	Nonce                uint64                                `json:"nonce"`
+	Value                string                                `json:"value"`
+	GasLimit             uint64                                `json:"gas_limit"`
+	GasPrice             string                                `json:"gas_price"`
+	MaxFeePerGas         *int64                                `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas *int64                                `json:"max_priority_fee_per_gas"`
+	Input                string                                `json:"input"`
+	Status               *int64                                `json:"status"`
+	GasUsed              *int64                                `json:"gas_used"`
+	EffectiveGasPrice    *string                               `json:"effective_gas_price"`
+	ContractAddress      string                                `json:"contract_address,omitempty"`
+	Logs                 []*ApiExecutionTransactionLogResponse `json:"logs"`
+}
+
+type ApiAddressTransactionResponse struct {
+	TxHash          string `json:"txhash"`
+	BlockSlot       uint64 `json:"block_slot"`
+	BlockIndex      uint64 `json:"block_index"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	Status          *int64 `json:"status"`
+	ContractAddress string `json:"contract_address,omitempty"`
+}
+
+type ApiAddressDepositResponse struct {
+	TxHash                string `json:"tx_hash"`
+	BlockNumber           uint64 `json:"block_number"`
+	BlockTs               int64  `json:"block_ts"`
+	PublicKey             string `json:"publickey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	ValidSignature        bool   `json:"valid_signature"`
+}
+
+type ApiAddressBlockResponse struct {
+	Slot             uint64 `json:"slot"`
+	BlockRoot        string `json:"blockroot"`
+	Proposer         uint64 `json:"proposer"`
+	ExecBlockNumber  uint64 `json:"exec_block_number"`
+	ExecPriorityFees string `json:"exec_priority_fees,omitempty"`
+}
+
+type ApiAddressValidatorResponse struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	PublicKey      string `json:"publickey"`
+	Balance        uint64 `json:"balance"`
+	Status         string `json:"status"`
+}
+
+type ApiAddressResponse struct {
+	Address      string                            `json:"address"`
+	Balance      string                            `json:"balance,omitempty"`
+	Transactions []*ApiAddressTransactionResponse  `json:"transactions"`
+	Withdrawals  []*ApiValidatorWithdrawalResponse `json:"withdrawals"`
+	Deposits     []*ApiAddressDepositResponse      `json:"deposits"`
+	Blocks       []*ApiAddressBlockResponse        `json:"blocks"`
+	Validators   []*ApiAddressValidatorResponse    `json:"validators"`
+	// ExpectedMonthlyPayout is the amount in gwei the validators of the address are expected to withdraw within the next 30 days
+	ExpectedMonthlyPayout *uint64 `json:"expected_monthly_payout,omitempty"`
+}
+
+type ApiValidatorNextWithdrawalResponse struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	Address        string `json:"address"`
+	Epoch          uint64 `json:"epoch"`
+	Slot           uint64 `json:"slot"`
+	Timestamp      int64  `json:"timestamp"`
+	Amount         uint64 `json:"amount"`
+	// Type is "full" for exited validators and "partial" for the withdrawal of the balance exceeding the max effective balance
+	Type string `json:"type"`
+}
+
+type ApiWithdrawalCredentialsResponse struct {
+	ValidatorIndex        uint64 `json:"validatorindex"`
+	WithdrawalCredentials string `json:"withdrawalcredentials"`
+	// Type is "bls" for 0x00 credentials and "execution" for 0x01 credentials
+	Type    string `json:"type"`
+	Address string `json:"address,omitempty"`
+	// BLSChangeStatus is one of "none", "pending", "invalid" or "included"
+	BLSChangeStatus       string `json:"bls_change_status"`
+	BLSChangeSlot         uint64 `json:"bls_change_slot,omitempty"`
+	PendingAddress        string `json:"pending_address,omitempty"`
+	PendingValidSignature *bool  `json:"pending_valid_signature,omitempty"`
+	PendingPubkeyMatches  *bool  `json:"pending_pubkey_matches,omitempty"`
+}
+
 type ApiValidatorTotalWithdrawalResponse struct {
 	Epoch          uint64 `json:"epoch,omitempty"`
 	Slot           uint64 `json:"slot,omitempty"`
@@ -167,3 +285,65 @@ type ApiValidatorTotalWithdrawalResponse struct {
 	Sum            uint64 `json:"sum"`
 	Count          uint64 `json:"count"`
 }
+
+type ApiSyncCommitteePerformanceResponse struct {
+	Period     uint64 `json:"period"`
+	StartEpoch uint64 `json:"start_epoch"`
+	EndEpoch   uint64 `json:"end_epoch"`
+	// ParticipationRate is the share of sync committee signatures included in the canonical blocks of the period
+	ParticipationRate float64                                         `json:"participation_rate"`
+	Participated      uint64                                          `json:"participated"`
+	MissedOwn         uint64                                          `json:"missed_own"`
+	MissedEmptySlot   uint64                                          `json:"missed_empty_slot"`
+	Rewards           int64                                           `json:"rewards"`
+	Penalties         int64                                           `json:"penalties"`
+	Validators        []*ApiSyncCommitteeValidatorPerformanceResponse `json:"validators"`
+}
+
+type ApiSyncCommitteeValidatorPerformanceResponse struct {
+	ValidatorIndex    uint64  `json:"validatorindex"`
+	Participated      uint64  `json:"participated"`
+	MissedOwn         uint64  `json:"missed_own"`
+	MissedEmptySlot   uint64  `json:"missed_empty_slot"`
+	Scheduled         uint64  `json:"scheduled"`
+	ParticipationRate float64 `json:"participation_rate"`
+	Rewards           int64   `json:"rewards"`
+	Penalties         int64   `json:"penalties"`
+}
+
+type ApiAttestationCorrectnessResponse struct {
+	// Included is the number of evaluated attestations, the rates are shares of it
+	Included          uint64  `json:"included"`
+	AvgInclusionDelay float64 `json:"avg_inclusion_delay"`
+	HeadCorrect       float64 `json:"head_correct"`
+	TargetCorrect     float64 `json:"target_correct"`
+	SourceCorrect     float64 `json:"source_correct"`
+}
+
+type ApiValidatorAttestationCorrectnessResponse struct {
+	ValidatorIndex uint64                             `json:"validatorindex"`
+	Last100Epochs  *ApiAttestationCorrectnessResponse `json:"last_100_epochs"`
+	// Total is aggregated from the daily statistics and does not include the current day
+	Total *ApiAttestationCorrectnessResponse `json:"total"`
+}
+
+type ApiDutiesResponse struct {
+	// Proposals only cover the current and the next epoch, the proposers of later epochs are not known yet
+	Proposals      []*ApiProposerDutyResponse      `json:"proposals"`
+	SyncCommittees []*ApiSyncCommitteeDutyResponse `json:"sync_committees"`
+}
+
+type ApiProposerDutyResponse struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	Slot           uint64 `json:"slot"`
+	Epoch          uint64 `json:"epoch"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+type ApiSyncCommitteeDutyResponse struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	Period         uint64 `json:"period"`
+	StartEpoch     uint64 `json:"start_epoch"`
+	EndEpoch       uint64 `json:"end_epoch"`
+	StartTimestamp int64  `json:"start_timestamp"`
+}
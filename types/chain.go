@@ -17,6 +17,8 @@ type ChainConfig struct {
 	BellatrixForkEpoch               uint64 `yaml:"BELLATRIX_FORK_EPOCH"`
 	CappellaForkVersion              string `yaml:"CAPELLA_FORK_VERSION"`
 	CappellaForkEpoch                uint64 `yaml:"CAPELLA_FORK_EPOCH"`
+	DenebForkVersion                 string `yaml:"DENEB_FORK_VERSION"`
+	DenebForkEpoch                   uint64 `yaml:"DENEB_FORK_EPOCH"`
 	ShardingForkVersion              string `yaml:"SHARDING_FORK_VERSION"`
 	ShardingForkEpoch                uint64 `yaml:"SHARDING_FORK_EPOCH"`
 	SecondsPerSlot                   uint64 `yaml:"SECONDS_PER_SLOT"`
@@ -94,4 +96,9 @@ type ChainConfig struct {
 	MaxWithdrawalsPerPayload        uint64 `yaml:"MAX_WITHDRAWALS_PER_PAYLOAD"`
 	MaxValidatorsPerWithdrawalSweep uint64 `yaml:"MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP"`
 	MaxBlsToExecutionChange         uint64 `yaml:"MAX_BLS_TO_EXECUTION_CHANGES"`
+
+	// deneb
+	// https://github.com/ethereum/consensus-specs/blob/dev/configs/mainnet.yaml
+	MaxBlobsPerBlock                 uint64 `yaml:"MAX_BLOBS_PER_BLOCK"`
+	MinEpochsForBlobSidecarsRequests uint64 `yaml:"MIN_EPOCHS_FOR_BLOB_SIDECARS_REQUESTS"`
 }
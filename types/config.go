@@ -20,6 +20,8 @@ type Config struct {
 		Name     string `yaml:"name" envconfig:"WRITER_DB_NAME"`
 		Host     string `yaml:"host" envconfig:"WRITER_DB_HOST"`
 		Port     string `yaml:"port" envconfig:"WRITER_DB_PORT"`
+		// MigrateOnStartup applies all pending schema migrations before the explorer starts
+		MigrateOnStartup bool `yaml:"migrateOnStartup" envconfig:"WRITER_DB_MIGRATE_ON_STARTUP"`
 	} `yaml:"writerDatabase"`
 	Chain struct {
 		Name             string `yaml:"name" envconfig:"CHAIN_NAME"`
@@ -28,20 +30,17 @@ type Config struct {
 		Config           ChainConfig
 	} `yaml:"chain"`
 	Indexer struct {
-		Enabled                     bool `yaml:"enabled" envconfig:"INDEXER_ENABLED"`
-		FixCanonOnStartup           bool `yaml:"fixCanonOnStartup" envconfig:"INDEXER_FIX_CANON_ON_STARTUP"`
-		FullIndexOnStartup          bool `yaml:"fullIndexOnStartup" envconfig:"INDEXER_FULL_INDEX_ON_STARTUP"`
-		IndexMissingEpochsOnStartup bool `yaml:"indexMissingEpochsOnStartup" envconfig:"INDEXER_MISSING_INDEX_ON_STARTUP"`
-		CheckAllBlocksOnStartup     bool `yaml:"checkAllBlocksOnStartup" envconfig:"INDEXER_CHECK_ALL_BLOCKS_ON_STARTUP"`
-		UpdateAllEpochStatistics    bool `yaml:"updateAllEpochStatistics" envconfig:"INDEXER_UPDATE_ALL_EPOCH_STATISTICS"`
-		Node                        struct {
-			Port     string `yaml:"port" envconfig:"INDEXER_NODE_PORT"`
-			Host     string `yaml:"host" envconfig:"INDEXER_NODE_HOST"`
-			Type     string `yaml:"type" envconfig:"INDEXER_NODE_TYPE"`
-			PageSize int32  `yaml:"pageSize" envconfig:"INDEXER_NODE_PAGE_SIZE"`
-			GRPCPort string `yaml:"grpcPort" envconfig:"INDEXER_NODE_GRPC_PORT"`
-		} `yaml:"node"`
-		Eth1Endpoint string `yaml:"eth1Endpoint" envconfig:"INDEXER_ETH1_ENDPOINT"`
+		Enabled                     bool          `yaml:"enabled" envconfig:"INDEXER_ENABLED"`
+		FixCanonOnStartup           bool          `yaml:"fixCanonOnStartup" envconfig:"INDEXER_FIX_CANON_ON_STARTUP"`
+		FullIndexOnStartup          bool          `yaml:"fullIndexOnStartup" envconfig:"INDEXER_FULL_INDEX_ON_STARTUP"`
+		IndexMissingEpochsOnStartup bool          `yaml:"indexMissingEpochsOnStartup" envconfig:"INDEXER_MISSING_INDEX_ON_STARTUP"`
+		CheckAllBlocksOnStartup     bool          `yaml:"checkAllBlocksOnStartup" envconfig:"INDEXER_CHECK_ALL_BLOCKS_ON_STARTUP"`
+		UpdateAllEpochStatistics    bool          `yaml:"updateAllEpochStatistics" envconfig:"INDEXER_UPDATE_ALL_EPOCH_STATISTICS"`
+		Node                        IndexerNode   `yaml:"node"`
+		Nodes                       []IndexerNode `yaml:"nodes"`
+		NodeQuorum                  bool          `yaml:"nodeQuorum" envconfig:"INDEXER_NODE_QUORUM"`
+		NodeCheckInterval           time.Duration `yaml:"nodeCheckInterval" envconfig:"INDEXER_NODE_CHECK_INTERVAL"`
+		Eth1Endpoint                string        `yaml:"eth1Endpoint" envconfig:"INDEXER_ETH1_ENDPOINT"`
 		// Deprecated Please use Phase0 config DEPOSIT_CONTRACT_ADDRESS
 		Eth1DepositContractAddress    string `yaml:"eth1DepositContractAddress" envconfig:"INDEXER_ETH1_DEPOSIT_CONTRACT_ADDRESS"`
 		Eth1DepositContractFirstBlock uint64 `yaml:"eth1DepositContractFirstBlock" envconfig:"INDEXER_ETH1_DEPOSIT_CONTRACT_FIRST_BLOCK"`
@@ -51,14 +50,24 @@ type Config struct {
 			EndEpoch   uint64   `yaml:"endEpoch" envconfig:"INDEXER_ONETIMEEXPORT_END_EPOCH"`
 			Epochs     []uint64 `yaml:"epochs" envconfig:"INDEXER_ONETIMEEXPORT_EPOCHS"`
 		} `yaml:"onetimeexport"`
+		Backfill struct {
+			Workers  int `yaml:"workers" envconfig:"INDEXER_BACKFILL_WORKERS"`
+			Prefetch int `yaml:"prefetch" envconfig:"INDEXER_BACKFILL_PREFETCH"`
+		} `yaml:"backfill"`
 		PubKeyTagsExporter struct {
 			Enabled bool `yaml:"enabled" envconfig:"PUBKEY_TAGS_EXPORTER_ENABLED"`
 		} `yaml:"pubkeyTagsExporter"`
+		RewardsExporter struct {
+			Enabled bool `yaml:"enabled" envconfig:"REWARDS_EXPORTER_ENABLED"`
+			// HistoryEpochs limits the export to the given number of epochs before the latest finalized epoch, 0 exports all epochs
+			HistoryEpochs uint64 `yaml:"historyEpochs" envconfig:"REWARDS_EXPORTER_HISTORY_EPOCHS"`
+		} `yaml:"rewardsExporter"`
 	} `yaml:"indexer"`
 	Frontend struct {
 		BeaconchainETHPoolBridgeSecret string `yaml:"beaconchainETHPoolBridgeSecret" envconfig:"FRONTEND_BEACONCHAIN_ETHPOOL_BRIDGE_SECRET"`
 		Kong                           string `yaml:"kong" envconfig:"FRONTEND_KONG"`
 		OnlyAPI                        bool   `yaml:"onlyAPI" envconfig:"FRONTEND_ONLY_API"`
+		AdminApiKey                    string `yaml:"adminApiKey" envconfig:"FRONTEND_ADMIN_API_KEY"`
 		CsrfAuthKey                    string `yaml:"csrfAuthKey" envconfig:"FRONTEND_CSRF_AUTHKEY"`
 		CsrfInsecure                   bool   `yaml:"csrfInsecure" envconfig:"FRONTEND_CSRF_INSECURE"`
 		DisableCharts                  bool   `yaml:"disableCharts" envconfig:"disableCharts"`
@@ -150,6 +159,9 @@ type Config struct {
 		UserDBNotifications                           bool   `yaml:"userDbNotifications" envconfig:"FRONTEND_USERDB_NOTIFICATIONS_ENABLED"`
 		FirebaseCredentialsPath                       string `yaml:"firebaseCredentialsPath" envconfig:"FRONTEND_NOTIFICATIONS_FIREBASE_CRED_PATH"`
 		ValidatorBalanceDecreasedNotificationsEnabled bool   `yaml:"validatorBalanceDecreasedNotificationsEnabled" envconfig:"FRONTEND_VALIDATOR_BALANCE_DECREASED_NOTIFICATIONS_ENABLED"`
+		TelegramBotToken                              string `yaml:"telegramBotToken" envconfig:"FRONTEND_NOTIFICATIONS_TELEGRAM_BOT_TOKEN"`
+		TelegramApiUrl                                string `yaml:"telegramApiUrl" envconfig:"FRONTEND_NOTIFICATIONS_TELEGRAM_API_URL"`
+		TelegramBotName                               string `yaml:"telegramBotName" envconfig:"FRONTEND_NOTIFICATIONS_TELEGRAM_BOT_NAME"`
 	} `yaml:"notifications"`
 	SSVExporter struct {
 		Enabled bool   `yaml:"enabled" envconfig:"SSV_EXPORTER_ENABLED"`
@@ -181,3 +193,12 @@ type DatabaseConfig struct {
 	Host     string
 	Port     string
 }
+
+// IndexerNode is the connection info of a beacon-node the indexer exports from
+type IndexerNode struct {
+	Port     string `yaml:"port" envconfig:"INDEXER_NODE_PORT"`
+	Host     string `yaml:"host" envconfig:"INDEXER_NODE_HOST"`
+	Type     string `yaml:"type" envconfig:"INDEXER_NODE_TYPE"`
+	PageSize int32  `yaml:"pageSize" envconfig:"INDEXER_NODE_PAGE_SIZE"`
+	GRPCPort string `yaml:"grpcPort" envconfig:"INDEXER_NODE_GRPC_PORT"`
+}
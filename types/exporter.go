@@ -1,6 +1,9 @@
 package types
 
 import (
+	"time"
+
+	"github.com/lib/pq"
 	ethpb "github.com/prysmaticlabs/prysm/v4/proto/prysm/v1alpha1"
 )
 
@@ -20,6 +23,30 @@ type ChainHead struct {
 	PreviousJustifiedBlockRoot []byte
 }
 
+// ChainEvent is a struct to hold a chain_reorg or finalized_checkpoint event received from the node
+type ChainEvent struct {
+	Reorg     *ChainReorg
+	Finalized *FinalizedCheckpoint
+}
+
+// ChainReorg is a struct to hold chain reorg data
+type ChainReorg struct {
+	Slot         uint64
+	Depth        uint64
+	Epoch        uint64
+	OldHeadBlock []byte
+	NewHeadBlock []byte
+	OldHeadState []byte
+	NewHeadState []byte
+}
+
+// FinalizedCheckpoint is a struct to hold finalized checkpoint data
+type FinalizedCheckpoint struct {
+	Epoch uint64
+	Block []byte
+	State []byte
+}
+
 type FinalityCheckpoints struct {
 	PreviousJustified struct {
 		Epoch uint64 `json:"epoch"`
@@ -129,6 +156,7 @@ type Block struct {
 	ExecutionPayload           *ExecutionPayload // warning: payload may be nil, for phase0/altair blocks
 	Canonical                  bool
 	SignedBLSToExecutionChange []*SignedBLSToExecutionChange
+	BlobKZGCommitments         [][]byte
 }
 
 type SignedBLSToExecutionChange struct {
@@ -142,6 +170,31 @@ type BLSToExecutionChange struct {
 	Address        []byte
 }
 
+// PendingBLSChange is a bls to execution change that is waiting in the operation pool together with the result of its validation
+type PendingBLSChange struct {
+	Validatorindex uint64    `db:"validatorindex"`
+	BlsPubkey      []byte    `db:"pubkey"`
+	Address        []byte    `db:"address"`
+	Signature      []byte    `db:"signature"`
+	ValidSignature bool      `db:"valid_signature"`
+	PubkeyMatches  bool      `db:"pubkey_matches"`
+	FirstSeen      time.Time `db:"first_seen"`
+}
+
+// Valid returns true if the change can be included in a block
+func (c *PendingBLSChange) Valid() bool {
+	return c.ValidSignature && c.PubkeyMatches
+}
+
+// WithdrawalCredentialsStatus is a struct to hold the withdrawal credentials of a validator and the state of its bls to execution change
+type WithdrawalCredentialsStatus struct {
+	Validatorindex        uint64 `db:"validatorindex"`
+	WithdrawalCredentials []byte `db:"withdrawalcredentials"`
+	// IncludedSlot is the slot of the block that included the change of the credentials, 0 if none has been included
+	IncludedSlot  uint64            `db:"included_slot"`
+	PendingChange *PendingBLSChange `db:"-"`
+}
+
 type Transaction struct {
 	Raw []byte
 	// Note: below values may be nil/0 if Raw fails to decode into a valid transaction
@@ -158,6 +211,10 @@ type Transaction struct {
 
 	MaxPriorityFeePerGas uint64
 	MaxFeePerGas         uint64
+
+	// only set for blob transactions
+	MaxFeePerBlobGas    uint64
+	BlobVersionedHashes [][]byte
 }
 
 type ExecutionPayload struct {
@@ -176,6 +233,8 @@ type ExecutionPayload struct {
 	BlockHash     []byte
 	Transactions  []*Transaction
 	Withdrawals   []*Withdrawals
+	BlobGasUsed   uint64
+	ExcessBlobGas uint64
 }
 
 type Withdrawals struct {
@@ -297,6 +356,41 @@ type MinimalBlock struct {
 	Canonical  bool   `db:"-"`
 }
 
+// ExecutionRewardsBlock is a struct to hold the data of a proposed block that is needed to compute its execution layer rewards
+type ExecutionRewardsBlock struct {
+	Slot              uint64        `db:"slot"`
+	BlockRoot         []byte        `db:"blockroot"`
+	BaseFeePerGas     uint64        `db:"exec_base_fee_per_gas"`
+	TransactionsCount uint64        `db:"exec_transactions_count"`
+	TxHashes          pq.ByteaArray `db:"txhashes"`
+}
+
+// TransactionReceipt is a struct to hold the execution outcome of a transaction
+type TransactionReceipt struct {
+	BlockSlot         uint64            `db:"block_slot"`
+	BlockIndex        uint64            `db:"block_index"`
+	BlockRoot         []byte            `db:"block_root"`
+	TxHash            []byte            `db:"txhash"`
+	Status            uint64            `db:"status"`
+	GasUsed           uint64            `db:"gas_used"`
+	EffectiveGasPrice string            `db:"effective_gas_price"`
+	ContractAddress   []byte            `db:"contract_address"`
+	LogsCount         uint64            `db:"logs_count"`
+	Logs              []*TransactionLog `db:"-"`
+}
+
+// TransactionLog is a struct to hold a log emitted by a transaction
+type TransactionLog struct {
+	BlockSlot  uint64        `db:"block_slot"`
+	BlockRoot  []byte        `db:"block_root"`
+	LogIndex   uint64        `db:"log_index"`
+	BlockIndex uint64        `db:"block_index"`
+	TxHash     []byte        `db:"txhash"`
+	Address    []byte        `db:"address"`
+	Topics     pq.ByteaArray `db:"topics"`
+	Data       []byte        `db:"data"`
+}
+
 // CanonBlock is a struct to hold canon block data
 type CanonBlock struct {
 	BlockRoot []byte `db:"blockroot"`
@@ -512,3 +606,148 @@ type HistoricEthPrice struct {
 	Name   string `json:"name"`
 	Symbol string `json:"symbol"`
 }
+
+// Reorg is a struct to hold a chain reorg detected by the exporter
+type Reorg struct {
+	ID                 uint64    `db:"id"`
+	Slot               uint64    `db:"slot"`
+	Depth              uint64    `db:"depth"`
+	CommonAncestorSlot uint64    `db:"common_ancestor_slot"`
+	OldHeadSlot        uint64    `db:"old_head_slot"`
+	OldHeadRoot        []byte    `db:"old_head_root"`
+	NewHeadSlot        uint64    `db:"new_head_slot"`
+	NewHeadRoot        []byte    `db:"new_head_root"`
+	OrphanedBlocks     uint64    `db:"orphaned_blocks"`
+	DetectedAt         time.Time `db:"detected_at"`
+}
+
+// ExportFailure is a struct to hold an epoch that failed to export together with its retry state
+type ExportFailure struct {
+	Epoch         uint64    `db:"epoch" json:"epoch"`
+	Error         string    `db:"error" json:"error"`
+	Attempts      uint64    `db:"attempts" json:"attempts"`
+	FirstFailedAt time.Time `db:"first_failed_at" json:"first_failed_at"`
+	LastFailedAt  time.Time `db:"last_failed_at" json:"last_failed_at"`
+	NextRetryAt   time.Time `db:"next_retry_at" json:"next_retry_at"`
+}
+
+// BlobSidecar is a struct to hold a blob sidecar of a block, the blob itself is not stored
+type BlobSidecar struct {
+	BlockSlot     uint64 `db:"block_slot"`
+	BlockRoot     []byte `db:"block_root"`
+	Index         uint64 `db:"blob_index"`
+	KZGCommitment []byte `db:"kzg_commitment"`
+	KZGProof      []byte `db:"kzg_proof"`
+	Size          uint64 `db:"size"`
+}
+
+// ValidatorRewards is a struct to hold the consensus layer rewards of a validator in an epoch split by component,
+// penalties are negative amounts. All amounts are in Gwei.
+type ValidatorRewards struct {
+	ValidatorIndex            uint64 `db:"validatorindex" json:"validatorindex"`
+	Epoch                     uint64 `db:"epoch" json:"epoch"`
+	Source                    int64  `db:"source" json:"source"`
+	Target                    int64  `db:"target" json:"target"`
+	Head                      int64  `db:"head" json:"head"`
+	InclusionDelay            int64  `db:"inclusion_delay" json:"inclusion_delay"`
+	Inactivity                int64  `db:"inactivity" json:"inactivity"`
+	SyncCommittee             int64  `db:"sync_committee" json:"sync_committee"`
+	ProposerAttestations      int64  `db:"proposer_attestations" json:"proposer_attestations"`
+	ProposerSyncAggregate     int64  `db:"proposer_sync_aggregate" json:"proposer_sync_aggregate"`
+	ProposerSlashingInclusion int64  `db:"proposer_slashing_inclusion" json:"proposer_slashing_inclusion"`
+}
+
+// Total returns the sum of all reward components
+func (r *ValidatorRewards) Total() int64 {
+	return r.Source + r.Target + r.Head + r.InclusionDelay + r.Inactivity + r.SyncCommittee + r.ProposerAttestations + r.ProposerSyncAggregate + r.ProposerSlashingInclusion
+}
+
+// IncludedAttestation is an attestation of an epoch that is included in a canonical block
+type IncludedAttestation struct {
+	BlockSlot       uint64        `db:"block_slot"`
+	Validators      pq.Int64Array `db:"validators"`
+	Slot            uint64        `db:"slot"`
+	BeaconBlockRoot []byte        `db:"beaconblockroot"`
+	SourceEpoch     uint64        `db:"source_epoch"`
+	SourceRoot      []byte        `db:"source_root"`
+	TargetEpoch     uint64        `db:"target_epoch"`
+	TargetRoot      []byte        `db:"target_root"`
+}
+
+// CanonicalBlockRoot is the root of a canonical block at a slot
+type CanonicalBlockRoot struct {
+	Slot      uint64 `db:"slot"`
+	BlockRoot []byte `db:"blockroot"`
+}
+
+// AttestationEvaluation holds the inclusion delay of the first included attestation of a validator in an epoch and
+// whether its head, target and source votes match the canonical chain
+type AttestationEvaluation struct {
+	Validatorindex uint64
+	InclusionDelay uint64
+	HeadCorrect    bool
+	TargetCorrect  bool
+	SourceCorrect  bool
+}
+
+// AttestationCorrectness holds the aggregated evaluations of the attestations of a validator
+type AttestationCorrectness struct {
+	Validatorindex    uint64 `db:"validatorindex"`
+	Included          uint64 `db:"included"`
+	InclusionDelaySum uint64 `db:"inclusion_delay_sum"`
+	CorrectHead       uint64 `db:"correct_head"`
+	CorrectTarget     uint64 `db:"correct_target"`
+	CorrectSource     uint64 `db:"correct_source"`
+}
+
+// AvgInclusionDelay returns the average number of slots between the attestations and their inclusion
+func (c *AttestationCorrectness) AvgInclusionDelay() float64 {
+	if c.Included == 0 {
+		return 0
+	}
+	return float64(c.InclusionDelaySum) / float64(c.Included)
+}
+
+// HeadRate returns the share of included attestations with a correct head vote
+func (c *AttestationCorrectness) HeadRate() float64 {
+	return c.rate(c.CorrectHead)
+}
+
+// TargetRate returns the share of included attestations with a correct target vote
+func (c *AttestationCorrectness) TargetRate() float64 {
+	return c.rate(c.CorrectTarget)
+}
+
+// SourceRate returns the share of included attestations with a correct source vote
+func (c *AttestationCorrectness) SourceRate() float64 {
+	return c.rate(c.CorrectSource)
+}
+
+// VoteRate returns the share of correct votes of the included attestations, head, target and source count equally
+func (c *AttestationCorrectness) VoteRate() float64 {
+	if c.Included == 0 {
+		return 0
+	}
+	return float64(c.CorrectHead+c.CorrectTarget+c.CorrectSource) / float64(3*c.Included)
+}
+
+func (c *AttestationCorrectness) rate(correct uint64) float64 {
+	if c.Included == 0 {
+		return 0
+	}
+	return float64(correct) / float64(c.Included)
+}
+
+// ProposerDuty is the scheduled proposer of a slot as reported by the beacon-node
+type ProposerDuty struct {
+	Slot           uint64 `db:"slot"`
+	Validatorindex uint64 `db:"validatorindex"`
+	// DependentRoot is the block root the duty depends on, the duty changes if this block is reorged
+	DependentRoot []byte `db:"dependent_root"`
+}
+
+// SyncCommitteeDuty is the membership of a validator in the sync committee of a period
+type SyncCommitteeDuty struct {
+	Period         uint64 `db:"period"`
+	Validatorindex uint64 `db:"validatorindex"`
+}
@@ -43,6 +43,7 @@ const (
 	RocketpoolColleteralMinReached                   EventName = "rocketpool_colleteral_min"
 	RocketpoolColleteralMaxReached                   EventName = "rocketpool_colleteral_max"
 	SyncCommitteeSoon                                EventName = "validator_synccommittee_soon"
+	ValidatorWithdrawalEventName                     EventName = "validator_withdrawal"
 )
 
 var UserIndexEvents = []EventName{
@@ -85,6 +86,7 @@ var EventLabel map[EventName]string = map[EventName]string{
 	RocketpoolColleteralMinReached:                   "You reached the rocketpool min collateral",
 	RocketpoolColleteralMaxReached:                   "You reached the rocketpool max collateral",
 	SyncCommitteeSoon:                                "Your validator(s) will soon be part of the sync committee",
+	ValidatorWithdrawalEventName:                     "A withdrawal was processed for your validator(s) or address(es)",
 }
 
 func IsUserIndexed(event EventName) bool {
@@ -124,6 +126,7 @@ var EventNames = []EventName{
 	RocketpoolColleteralMinReached,
 	RocketpoolColleteralMaxReached,
 	SyncCommitteeSoon,
+	ValidatorWithdrawalEventName,
 }
 
 type EventNameDesc struct {
@@ -153,6 +156,10 @@ var AddWatchlistEvents = []EventNameDesc{
 		Desc:  "Sync committee",
 		Event: SyncCommitteeSoon,
 	},
+	{
+		Desc:  "Withdrawals",
+		Event: ValidatorWithdrawalEventName,
+	},
 }
 
 // this is the source of truth for the network events that are supported by the user/notification page
@@ -364,6 +371,37 @@ func (a TransitDiscordContent) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
 
+type TransitChat struct {
+	Id      uint64             `db:"id,omitempty"`
+	Created sql.NullTime       `db:"created"`
+	Sent    sql.NullTime       `db:"sent"`
+	Channel string             `db:"channel"`
+	Content TransitChatContent `db:"content"`
+}
+
+// TransitChatContent is a batch of notifications of a user formatted for a chat platform, the destination of the
+// message is read from the channel settings of the user when it is sent
+type TransitChatContent struct {
+	UserID uint64
+	// Text is the message in the markup of the platform
+	Text string
+	// FormattedText is an html version of the message for platforms that support both
+	FormattedText string `json:",omitempty"`
+}
+
+func (e *TransitChatContent) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &e)
+}
+
+func (a TransitChatContent) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
 type TransitPush struct {
 	Id      uint64       `db:"id,omitempty"`
 	Created sql.NullTime `db:"created"`
@@ -412,6 +450,44 @@ type UserWebhook struct {
 	Request     sql.NullString `db:"request" json:"request"`
 	Destination sql.NullString `db:"destination" json:"destination"`
 	EventNames  pq.StringArray `db:"event_names" json:"-"`
+	// Secret is the key the requests to the webhook are signed with, it must never be part of a request
+	Secret []byte `db:"secret" json:"-"`
+}
+
+// UserWebhookDelivery is a single delivery attempt of a webhook message
+type UserWebhookDelivery struct {
+	ID             uint64         `db:"id"`
+	WebhookID      uint64         `db:"webhook_id"`
+	Url            string         `db:"url"`
+	IdempotencyKey string         `db:"idempotency_key"`
+	Channel        string         `db:"channel"`
+	Content        []byte         `db:"content"`
+	Attempt        uint64         `db:"attempt"`
+	Ts             time.Time      `db:"ts"`
+	StatusCode     sql.NullInt64  `db:"status_code"`
+	Response       sql.NullString `db:"response"`
+	Error          sql.NullString `db:"error"`
+}
+
+// Title returns the title of the event the message was sent for
+func (d *UserWebhookDelivery) Title() string {
+	if d.Channel == string(WebhookDiscordNotificationChannel) {
+		var content TransitDiscordContent
+		if json.Unmarshal(d.Content, &content) == nil && len(content.DiscordRequest.Embeds) > 0 {
+			return content.DiscordRequest.Embeds[0].Title
+		}
+		return ""
+	}
+	var content TransitWebhookContent
+	if json.Unmarshal(d.Content, &content) == nil {
+		return content.Event.Title
+	}
+	return ""
+}
+
+// Delivered returns true if the receiver accepted the message
+func (d *UserWebhookDelivery) Delivered() bool {
+	return d.StatusCode.Valid && d.StatusCode.Int64 < 400
 }
 
 type UserWebhookSubscriptions struct {
@@ -428,6 +504,9 @@ var NotificationChannelLabels map[NotificationChannel]string = map[NotificationC
 	PushNotificationChannel:           "Push Notification",
 	WebhookNotificationChannel:        "Webhook Notification",
 	WebhookDiscordNotificationChannel: "Discord Notification",
+	TelegramNotificationChannel:       "Telegram Notification",
+	SlackNotificationChannel:          "Slack Notification",
+	MatrixNotificationChannel:         "Matrix Notification",
 }
 
 const (
@@ -435,6 +514,9 @@ const (
 	PushNotificationChannel           NotificationChannel = "push"
 	WebhookNotificationChannel        NotificationChannel = "webhook"
 	WebhookDiscordNotificationChannel NotificationChannel = "webhook_discord"
+	TelegramNotificationChannel       NotificationChannel = "telegram"
+	SlackNotificationChannel          NotificationChannel = "slack"
+	MatrixNotificationChannel         NotificationChannel = "matrix"
 )
 
 var NotificationChannels = []NotificationChannel{
@@ -442,6 +524,17 @@ var NotificationChannels = []NotificationChannel{
 	PushNotificationChannel,
 	WebhookNotificationChannel,
 	WebhookDiscordNotificationChannel,
+	TelegramNotificationChannel,
+	SlackNotificationChannel,
+	MatrixNotificationChannel,
+}
+
+// ChatNotificationChannels are the channels that post notifications to a chat platform, they are inactive until the
+// user configured a destination
+var ChatNotificationChannels = []NotificationChannel{
+	TelegramNotificationChannel,
+	SlackNotificationChannel,
+	MatrixNotificationChannel,
 }
 
 func GetNotificationChannel(channel string) (NotificationChannel, error) {
@@ -453,6 +546,45 @@ func GetNotificationChannel(channel string) (NotificationChannel, error) {
 	return "", errors.Errorf("Could not convert channel from string to NotificationChannel type. %v is not a known channel type", channel)
 }
 
+// NotificationDelivery is the mode in which the email notifications of a user are delivered
+type NotificationDelivery string
+
+const (
+	ImmediateNotificationDelivery NotificationDelivery = "immediate"
+	HourlyNotificationDelivery    NotificationDelivery = "hourly"
+	DailyNotificationDelivery     NotificationDelivery = "daily"
+)
+
+var NotificationDeliveryLabels = map[NotificationDelivery]string{
+	ImmediateNotificationDelivery: "Immediately",
+	HourlyNotificationDelivery:    "Hourly Digest",
+	DailyNotificationDelivery:     "Daily Digest",
+}
+
+func (d NotificationDelivery) Label() string {
+	return NotificationDeliveryLabels[d]
+}
+
+// UserNotificationSettings are the delivery preferences of a user, the quiet hours are hours of the day in the
+// timezone of the user and disabled if start and end are equal
+type UserNotificationSettings struct {
+	UserID          uint64               `db:"user_id"`
+	Delivery        NotificationDelivery `db:"delivery"`
+	Timezone        string               `db:"timezone"`
+	QuietHoursStart uint64               `db:"quiet_hours_start"`
+	QuietHoursEnd   uint64               `db:"quiet_hours_end"`
+	LastDigest      sql.NullTime         `db:"last_digest"`
+}
+
+// NotificationDigestItem is the number of notifications of an event and filter that were collected for a digest
+type NotificationDigestItem struct {
+	EventName   EventName `db:"event_name"`
+	EventFilter string    `db:"event_filter"`
+	Count       uint64    `db:"count"`
+	FirstEpoch  uint64    `db:"first_epoch"`
+	LastEpoch   uint64    `db:"last_epoch"`
+}
+
 type ErrorResponse struct {
 	Status string // e.g. "200 OK"
 	Body   string
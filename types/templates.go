@@ -203,6 +203,7 @@ type IndexPageDataBlocks struct {
 	Votes                uint64        `db:"votes" json:"votes"`
 	Graffiti             []byte        `db:"graffiti"`
 	ProposerName         string        `db:"name"`
+	ExecPriorityFees     *string       `db:"exec_priority_fees" json:"-"`
 }
 
 // IndexPageEpochHistory is a struct to hold the epoch history for the main web page
@@ -334,6 +335,7 @@ type ValidatorPageData struct {
 	MissedSyncCount                     uint64
 	OrphanedSyncCount                   uint64
 	UnmissedSyncPercentage              float64 // missed/(participated+orphaned)
+	SyncCommitteePerformance            []*SyncCommitteePerformance
 	Income1d                            int64
 	Income7d                            int64
 	Income31d                           int64
@@ -351,6 +353,7 @@ type ValidatorPageData struct {
 	User                                *User
 	AverageAttestationInclusionDistance float64
 	AttestationInclusionEffectiveness   float64
+	AttestationCorrectness              *AttestationCorrectness // evaluated attestations of the last 100 epochs, nil if none
 	CsrfField                           template.HTML
 	NetworkStats                        *IndexPageData
 	EstimatedActivationTs               int64
@@ -361,8 +364,11 @@ type ValidatorPageData struct {
 	Rocketpool                          *RocketpoolValidatorPageData
 	CappellaHasHappened                 bool
 	BLSChange                           *BLSChange
+	PendingBLSChange                    *PendingBLSChange
 	IsWithdrawableAddress               bool
 	NextWithdrawalRow                   [][]interface{}
+	Rewards1d                           *ValidatorRewards
+	Rewards7d                           *ValidatorRewards
 }
 
 type RocketpoolValidatorPageData struct {
@@ -591,8 +597,14 @@ type BlockPageData struct {
 	ExecBaseFeePerGas     sql.NullInt64 `db:"exec_base_fee_per_gas"`
 	ExecBlockHash         []byte        `db:"exec_block_hash"`
 	ExecTransactionsCount uint64        `db:"exec_transactions_count"`
+	ExecBlobGasUsed       sql.NullInt64 `db:"exec_blob_gas_used"`
+	ExecExcessBlobGas     sql.NullInt64 `db:"exec_excess_blob_gas"`
+	BlobCount             uint64        `db:"blobcount"`
+	BlobsSize             uint64
+	BlobBaseFee           string
 
 	Transactions []*BlockPageTransaction
+	Blobs        []*BlockPageBlob
 
 	Attestations      []*BlockPageAttestation // Attestations included in this block
 	VoluntaryExits    []*BlockPageVoluntaryExits
@@ -628,6 +640,16 @@ type BlockPageMinMaxSlot struct {
 	MaxSlot uint64
 }
 
+// BlockPageBlob is a struct to hold a blob of a block on the block page, proof and size are only known once the sidecar is exported
+type BlockPageBlob struct {
+	Index         uint64        `db:"blob_index"`
+	KZGCommitment []byte        `db:"kzg_commitment"`
+	VersionedHash []byte        `db:"versioned_hash"`
+	TxHash        []byte        `db:"tx_hash"`
+	KZGProof      []byte        `db:"kzg_proof"`
+	Size          sql.NullInt64 `db:"size"`
+}
+
 // BlockPageTransaction is a struct to hold execution transactions on the block page
 type BlockPageTransaction struct {
 	BlockSlot    uint64 `db:"block_slot"`
@@ -651,6 +673,88 @@ type BlockPageTransaction struct {
 	MaxFeePerGas         uint64 `db:"max_fee_per_gas"`
 }
 
+// TxPageData is a struct to hold the data of an execution transaction on the transaction page, the receipt fields are
+// only valid once the receipt of the transaction has been exported
+type TxPageData struct {
+	BlockSlot            uint64         `db:"block_slot"`
+	BlockIndex           uint64         `db:"block_index"`
+	BlockRoot            []byte         `db:"block_root"`
+	BlockStatus          uint64         `db:"block_status"`
+	BlockNumber          uint64         `db:"exec_block_number"`
+	BaseFeePerGas        uint64         `db:"exec_base_fee_per_gas"`
+	TxHash               []byte         `db:"txhash"`
+	AccountNonce         uint64         `db:"nonce"`
+	Price                []byte         `db:"gas_price"`
+	GasLimit             uint64         `db:"gas_limit"`
+	Sender               []byte         `db:"sender"`
+	Recipient            []byte         `db:"recipient"`
+	Amount               []byte         `db:"amount"`
+	Payload              []byte         `db:"payload"`
+	MaxPriorityFeePerGas sql.NullInt64  `db:"max_priority_fee_per_gas"`
+	MaxFeePerGas         sql.NullInt64  `db:"max_fee_per_gas"`
+	ReceiptStatus        sql.NullInt64  `db:"receipt_status"`
+	GasUsed              sql.NullInt64  `db:"gas_used"`
+	EffectiveGasPrice    sql.NullString `db:"effective_gas_price"`
+	ContractAddress      []byte         `db:"contract_address"`
+	LogsCount            sql.NullInt64  `db:"logs_count"`
+
+	Epoch        uint64
+	Ts           time.Time
+	AmountPretty string
+	PricePretty  string
+	FeePretty    string
+	Logs         []*TransactionLog
+}
+
+// AddressPageData is a struct to hold the activity of an execution layer address on the address page
+type AddressPageData struct {
+	Address []byte
+	// BalancePretty is empty if the balance could not be retrieved from the execution endpoint
+	BalancePretty string
+	Balance       string
+
+	Transactions []*AddressPageTransaction
+	Withdrawals  []*Withdrawals
+	Deposits     []*Eth1Deposit
+	Blocks       []*AddressPageBlock
+	Validators   []*AddressPageValidator
+
+	// ExpectedMonthlyPayout is the amount in gwei the validators of the address are expected to withdraw within the
+	// next 30 days, nil if no prediction of the withdrawal sweep is available
+	ExpectedMonthlyPayout *uint64
+}
+
+// AddressPageTransaction is a struct to hold a transaction sent or received by an address on the address page
+type AddressPageTransaction struct {
+	BlockSlot       uint64        `db:"block_slot"`
+	BlockIndex      uint64        `db:"block_index"`
+	TxHash          []byte        `db:"txhash"`
+	Sender          []byte        `db:"sender"`
+	Recipient       []byte        `db:"recipient"`
+	Amount          []byte        `db:"amount"`
+	ReceiptStatus   sql.NullInt64 `db:"receipt_status"`
+	ContractAddress []byte        `db:"contract_address"`
+	AmountPretty    string
+}
+
+// AddressPageBlock is a struct to hold a block whose fee recipient is the address on the address page
+type AddressPageBlock struct {
+	Slot               uint64  `db:"slot"`
+	BlockRoot          []byte  `db:"blockroot"`
+	Proposer           uint64  `db:"proposer"`
+	ExecBlockNumber    uint64  `db:"exec_block_number"`
+	ExecPriorityFees   *string `db:"exec_priority_fees"`
+	PriorityFeesPretty string
+}
+
+// AddressPageValidator is a struct to hold a validator that withdraws to the address on the address page
+type AddressPageValidator struct {
+	Index     uint64 `db:"validatorindex"`
+	PublicKey []byte `db:"pubkey"`
+	Balance   uint64 `db:"balance"`
+	Status    string `db:"status"`
+}
+
 // BlockPageAttestation is a struct to hold attestations on the block page
 type BlockPageAttestation struct {
 	BlockSlot       uint64        `db:"block_slot"`
@@ -906,6 +1010,15 @@ type ValidatorEarnings struct {
 	LastMonthFormatted      template.HTML `json:"lastMonthFormatted"`
 	TotalFormatted          template.HTML `json:"totalFormatted"`
 	TotalChangeFormatted    template.HTML `json:"totalChangeFormatted"`
+	// execution layer rewards (priority fees) of proposed blocks in gwei
+	ExecTotal              int64         `json:"execTotal"`
+	ExecLastDay            int64         `json:"execLastDay"`
+	ExecLastWeek           int64         `json:"execLastWeek"`
+	ExecLastMonth          int64         `json:"execLastMonth"`
+	ExecLastDayFormatted   template.HTML `json:"execLastDayFormatted"`
+	ExecLastWeekFormatted  template.HTML `json:"execLastWeekFormatted"`
+	ExecLastMonthFormatted template.HTML `json:"execLastMonthFormatted"`
+	ExecTotalFormatted     template.HTML `json:"execTotalFormatted"`
 }
 
 // ValidatorAttestationSlashing is a struct to hold data of an attestation-slashing
@@ -1116,11 +1229,33 @@ type UserNotificationsCenterPageData struct {
 type NotificationChannelsModal struct {
 	CsrfField            template.HTML
 	NotificationChannels []UserNotificationChannels
+	Settings             *UserNotificationSettings
+	Deliveries           []NotificationDelivery
+	Hours                []uint64
 }
 
 type UserNotificationChannels struct {
 	Channel NotificationChannel `db:"channel"`
 	Active  bool                `db:"active"`
+	// Destination is the chat id, incoming webhook url or room id of chat channels
+	Destination sql.NullString `db:"destination"`
+	// Server is the homeserver of matrix channels
+	Server sql.NullString `db:"server"`
+	// HasToken is true if an access token is stored for the channel, the token itself is never shown
+	HasToken bool `db:"has_token"`
+	// LinkToken is sent to the telegram bot from a chat to link the chat, LinkURL opens the bot with the token
+	LinkToken string `db:"-"`
+	LinkURL   string `db:"-"`
+}
+
+// IsChat returns true if the channel posts to a chat platform
+func (c UserNotificationChannels) IsChat() bool {
+	for _, ch := range ChatNotificationChannels {
+		if c.Channel == ch {
+			return true
+		}
+	}
+	return false
 }
 
 type UserValidatorNotificationTableData struct {
@@ -1290,6 +1425,8 @@ type UserWebhookRow struct {
 	Events       []EventNameCheckbox     `db:"event_names" json:"-"`
 	Discord      bool
 	CsrfField    template.HTML
+	// Secret is the hex encoded key the requests to the webhook are signed with
+	Secret string
 }
 
 type UserWebhookRowError struct {
@@ -1302,6 +1439,7 @@ type UserWebhookRowError struct {
 type WebhookPageData struct {
 	WebhookRows  []UserWebhookRow
 	Webhooks     []UserWebhook
+	Deliveries   []*UserWebhookDelivery
 	Events       []EventNameCheckbox
 	CsrfField    template.HTML
 	Allowed      uint64
@@ -1404,3 +1542,64 @@ type ValidatorsBLSChange struct {
 	Signature                []byte `db:"signature" json:"signature,omitempty"`
 	WithdrawalCredentialsOld []byte `db:"withdrawalcredentials" json:"withdrawalcredentials,omitempty"`
 }
+
+// WithdrawalSweepValidator holds the state of a validator that is relevant for the withdrawal sweep
+type WithdrawalSweepValidator struct {
+	Validatorindex        uint64 `db:"validatorindex"`
+	WithdrawalCredentials []byte `db:"withdrawalcredentials"`
+	WithdrawableEpoch     uint64 `db:"withdrawableepoch"`
+	EffectiveBalance      uint64 `db:"effectivebalance"`
+	Balance               uint64 `db:"balance"`
+	Performance7d         int64  `db:"performance7d"`
+}
+
+// WithdrawalSweepPosition describes the latest canonical block containing withdrawals, it is used to derive the
+// position of the withdrawal sweep
+type WithdrawalSweepPosition struct {
+	Slot                uint64 `db:"slot"`
+	FirstValidatorindex uint64 `db:"first_validatorindex"`
+	LastValidatorindex  uint64 `db:"last_validatorindex"`
+	Count               uint64 `db:"count"`
+	// BlocksSince is the number of canonical blocks with an execution payload proposed after Slot, they contain no withdrawals
+	BlocksSince uint64 `db:"blocks_since"`
+	// LatestSlot is the latest slot the exporter has saved a block of
+	LatestSlot uint64 `db:"latest_slot"`
+}
+
+// WithdrawalPrediction is the predicted next withdrawal of a validator
+type WithdrawalPrediction struct {
+	Validatorindex uint64 `db:"validatorindex"`
+	Address        []byte `db:"address"`
+	Slot           uint64 `db:"slot"`
+	// Amount is the predicted amount in gwei, for partial withdrawals that happen after a full cycle of the sweep it
+	// is estimated from the income of the validator during the last 7 days
+	Amount uint64 `db:"amount"`
+	IsFull bool   `db:"is_full"`
+	// IncomePerSlot is the average income in gwei of the validator per slot during the last 7 days
+	IncomePerSlot uint64 `db:"income_per_slot"`
+	// CycleSlots is the number of slots the simulated sweep needed to pass all validators once
+	CycleSlots uint64 `db:"cycle_slots"`
+}
+
+// SyncCommitteePerformance holds the performance of a validator during its membership in the sync committee of a period
+type SyncCommitteePerformance struct {
+	Period         uint64 `db:"period"`
+	Validatorindex uint64 `db:"validatorindex"`
+	Participated   uint64 `db:"participated"`
+	// MissedOwn is the number of slots with a canonical block that does not include the signature of the validator
+	MissedOwn uint64 `db:"missed_own"`
+	// MissedEmptySlot is the number of slots the validator could not participate in as no canonical block was proposed
+	MissedEmptySlot uint64 `db:"missed_empty_slot"`
+	Scheduled       uint64 `db:"scheduled"`
+	// Rewards and Penalties are the sync committee rewards in gwei reported by the beacon-node, penalties are negative
+	Rewards   int64 `db:"rewards"`
+	Penalties int64 `db:"penalties"`
+}
+
+// ParticipationRate returns the share of slots with a canonical block the validator participated in
+func (p *SyncCommitteePerformance) ParticipationRate() float64 {
+	if p.Participated+p.MissedOwn == 0 {
+		return 0
+	}
+	return float64(p.Participated) / float64(p.Participated+p.MissedOwn)
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+// EIP-4844 parameters of the blob fee market, see https://eips.ethereum.org/EIPS/eip-4844#parameters
+const (
+	BlobSize                  = 131072
+	GasPerBlob                = 131072
+	MinBaseFeePerBlobGas      = 1
+	BlobBaseFeeUpdateFraction = 3338477
+	blobVersionedHashVersion  = 0x01
+)
+
+// BlobBaseFee returns the base fee per blob gas in wei of a block with the given excess blob gas
+func BlobBaseFee(excessBlobGas uint64) *big.Int {
+	return fakeExponential(big.NewInt(MinBaseFeePerBlobGas), new(big.Int).SetUint64(excessBlobGas), big.NewInt(BlobBaseFeeUpdateFraction))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using a taylor expansion as specified in EIP-4844
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	i := big.NewInt(1)
+	output := new(big.Int)
+	numeratorAccum := new(big.Int).Mul(factor, denominator)
+	for numeratorAccum.Sign() > 0 {
+		output.Add(output, numeratorAccum)
+
+		numeratorAccum.Mul(numeratorAccum, numerator)
+		numeratorAccum.Div(numeratorAccum, new(big.Int).Mul(denominator, i))
+		i.Add(i, big.NewInt(1))
+	}
+	return output.Div(output, denominator)
+}
+
+// KZGCommitmentToVersionedHash returns the versioned hash that references the blob of a kzg commitment in blob transactions
+func KZGCommitmentToVersionedHash(commitment []byte) []byte {
+	hash := sha256.Sum256(commitment)
+	hash[0] = blobVersionedHashVersion
+	return hash[:]
+}
+
+// BlobUsedSize returns the size of a blob without its trailing zero bytes
+func BlobUsedSize(blob []byte) int {
+	size := len(blob)
+	for size > 0 && blob[size-1] == 0 {
+		size--
+	}
+	return size
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// ForkFeature is a capability of the chain that is available from the fork that introduced it onwards
+type ForkFeature string
+
+const (
+	ForkFeatureSyncCommittees        ForkFeature = "sync_committees"
+	ForkFeatureExecutionPayload      ForkFeature = "execution_payload"
+	ForkFeatureWithdrawals           ForkFeature = "withdrawals"
+	ForkFeatureBLSToExecutionChanges ForkFeature = "bls_to_execution_changes"
+	ForkFeatureBlobs                 ForkFeature = "blobs"
+)
+
+// Fork is an entry of the fork schedule of the configured chain
+type Fork struct {
+	// Index is the position of the fork in the schedule, later forks have a higher index
+	Index    int
+	Name     string
+	Version  string
+	Epoch    uint64
+	Features map[ForkFeature]bool
+}
+
+// Has returns true if the feature is available in blocks and states of the fork
+func (f *Fork) Has(feature ForkFeature) bool {
+	return f.Features[feature]
+}
+
+type forkDefinition struct {
+	name     string
+	schedule func(cfg *types.ChainConfig) (epoch uint64, version string)
+	features []ForkFeature
+}
+
+var forkDefinitions = []*forkDefinition{}
+
+// cachedForkSchedule is the fork schedule built for the config it holds, it is rebuilt if the config is replaced
+type cachedForkSchedule struct {
+	config *types.Config
+	forks  []*Fork
+}
+
+var forkScheduleCache atomic.Value
+
+func init() {
+	RegisterFork("phase0", func(cfg *types.ChainConfig) (uint64, string) {
+		return 0, cfg.GenesisForkVersion
+	})
+	RegisterFork("altair", func(cfg *types.ChainConfig) (uint64, string) {
+		return cfg.AltairForkEpoch, cfg.AltairForkVersion
+	}, ForkFeatureSyncCommittees)
+	RegisterFork("bellatrix", func(cfg *types.ChainConfig) (uint64, string) {
+		return cfg.BellatrixForkEpoch, cfg.BellatrixForkVersion
+	}, ForkFeatureExecutionPayload)
+	RegisterFork("capella", func(cfg *types.ChainConfig) (uint64, string) {
+		return cfg.CappellaForkEpoch, cfg.CappellaForkVersion
+	}, ForkFeatureWithdrawals, ForkFeatureBLSToExecutionChanges)
+	RegisterFork("deneb", func(cfg *types.ChainConfig) (uint64, string) {
+		// chain configs written before deneb do not contain the fork, it must not be active from genesis then
+		if cfg.DenebForkVersion == "" {
+			return math.MaxUint64, ""
+		}
+		return cfg.DenebForkEpoch, cfg.DenebForkVersion
+	}, ForkFeatureBlobs)
+}
+
+// RegisterFork will add a fork to the end of the fork schedule. schedule returns the activation epoch and the fork
+// version of the fork from the chain config. Every fork keeps the features of the forks registered before it and adds
+// the given features. Forks have to be registered from an init function in the order they activate.
+func RegisterFork(name string, schedule func(cfg *types.ChainConfig) (epoch uint64, version string), features ...ForkFeature) {
+	for _, def := range forkDefinitions {
+		if def.name == name {
+			panic(fmt.Sprintf("fork %v is already registered", name))
+		}
+	}
+	forkDefinitions = append(forkDefinitions, &forkDefinition{name: name, schedule: schedule, features: features})
+	forkScheduleCache.Store(&cachedForkSchedule{})
+}
+
+// ForkSchedule will return all registered forks together with their activation epoch and version on the configured
+// chain. The schedule is built once per config, the returned forks must not be modified.
+func ForkSchedule() []*Fork {
+	if cached, ok := forkScheduleCache.Load().(*cachedForkSchedule); ok && cached.config == Config && cached.forks != nil {
+		return cached.forks
+	}
+	forks := buildForkSchedule()
+	forkScheduleCache.Store(&cachedForkSchedule{config: Config, forks: forks})
+	return forks
+}
+
+func buildForkSchedule() []*Fork {
+	forks := make([]*Fork, 0, len(forkDefinitions))
+	features := make(map[ForkFeature]bool)
+	for i, def := range forkDefinitions {
+		for _, feature := range def.features {
+			features[feature] = true
+		}
+		fork := &Fork{
+			Index:    i,
+			Name:     def.name,
+			Features: make(map[ForkFeature]bool, len(features)),
+		}
+		fork.Epoch, fork.Version = def.schedule(&Config.Chain.Config)
+		for feature := range features {
+			fork.Features[feature] = true
+		}
+		forks = append(forks, fork)
+	}
+	return forks
+}
+
+// ForkAtEpoch will return the fork that is active at the given epoch
+func ForkAtEpoch(epoch uint64) *Fork {
+	forks := ForkSchedule()
+	active := forks[0]
+	for _, fork := range forks[1:] {
+		if fork.Epoch <= epoch {
+			active = fork
+		}
+	}
+	return active
+}
+
+// ForkAtSlot will return the fork that is active at the given slot
+func ForkAtSlot(slot uint64) *Fork {
+	return ForkAtEpoch(EpochOfSlot(slot))
+}
+
+// ForkByName will return the fork with the given name as used in the Eth-Consensus-Version header of the beacon-node api
+func ForkByName(name string) (*Fork, error) {
+	for _, fork := range ForkSchedule() {
+		if fork.Name == name {
+			return fork, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown fork %q", name)
+}
+
+// ForkFeatureEpoch will return the first epoch the feature is available at, or math.MaxUint64 if no scheduled fork introduces it
+func ForkFeatureEpoch(feature ForkFeature) uint64 {
+	for _, fork := range ForkSchedule() {
+		if fork.Has(feature) {
+			return fork.Epoch
+		}
+	}
+	return math.MaxUint64
+}
+
+// ForkFeatureActive returns true if the feature is available at the given epoch
+func ForkFeatureActive(feature ForkFeature, epoch uint64) bool {
+	return ForkAtEpoch(epoch).Has(feature)
+}
@@ -297,6 +297,13 @@ func FormatEth1Address(addr []byte) template.HTML {
 	return template.HTML(fmt.Sprintf("<a href=\"%s/address/0x%x\" class=\"text-monospace\">%s…</a>%s", eth1ExplorerAddr, addr, eth1Addr.Hex()[:8], copyBtn))
 }
 
+// FormatExecutionAddress will return the execution layer address formated as html linking to its address page
+func FormatExecutionAddress(addr []byte) template.HTML {
+	copyBtn := CopyButton(hex.EncodeToString(addr))
+	eth1Addr := eth1common.BytesToAddress(addr)
+	return template.HTML(fmt.Sprintf("<a href=\"/address/0x%x\" class=\"text-monospace\">%s…</a>%s", addr, eth1Addr.Hex()[:8], copyBtn))
+}
+
 // FormatEth1Block will return the eth1-block formated as html
 func FormatEth1Block(block uint64) template.HTML {
 	if Config.Chain.Config.ConfigName == "prater" {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	securerand "crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
 	"encoding/base64"
 	"encoding/hex"
@@ -78,6 +79,7 @@ func GetTemplateFuncs() template.FuncMap {
 		"formatEth1Address":                       FormatEth1Address,
 		"formatEth1AddressStringLowerCase":        FormatEth1AddressStringLowerCase,
 		"formatEth1TxHash":                        FormatEth1TxHash,
+		"formatExecutionAddress":                  FormatExecutionAddress,
 		"formatGraffiti":                          FormatGraffiti,
 		"formatHash":                              FormatHash,
 		"formatBitvector":                         FormatBitvector,
@@ -176,7 +178,7 @@ func fixUtf(r rune) rune {
 }
 
 func SyncPeriodOfEpoch(epoch uint64) uint64 {
-	if epoch < Config.Chain.Config.AltairForkEpoch {
+	if !ForkFeatureActive(ForkFeatureSyncCommittees, epoch) {
 		return 0
 	}
 	return epoch / Config.Chain.Config.EpochsPerSyncCommitteePeriod
@@ -365,6 +367,22 @@ func CORSMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// AdminAPIMiddleware only passes requests that carry the configured admin api key in the Authorization header
+func AdminAPIMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := Config.Frontend.AdminApiKey
+		if key == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+key)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func IsApiRequest(r *http.Request) bool {
 	query, ok := r.URL.Query()["format"]
 	return ok && len(query) > 0 && query[0] == "json"
@@ -646,18 +664,6 @@ func ElementExists(arr []string, el string) bool {
 	return false
 }
 
-// GetTimeToNextWithdrawal calculates the time it takes for the validators next withdrawal to be processed.
-func GetTimeToNextWithdrawal(distance uint64) time.Time {
-	minTimeToWithdrawal := time.Now().Add(time.Second * time.Duration((distance/Config.Chain.Config.MaxValidatorsPerWithdrawalSweep)*Config.Chain.Config.SecondsPerSlot))
-	timeToWithdrawal := time.Now().Add(time.Second * time.Duration((float64(distance)/float64(Config.Chain.Config.MaxWithdrawalsPerPayload))*float64(Config.Chain.Config.SecondsPerSlot)))
-
-	if timeToWithdrawal.Before(minTimeToWithdrawal) {
-		return minTimeToWithdrawal
-	}
-
-	return timeToWithdrawal
-}
-
 // IsValidWithdrawalCredentials verifies whether a string represents valid withdrawal credentials.
 func IsValidWithdrawalCredentials(s string) bool {
 	return withdrawalCredentialsRE.MatchString(s) || withdrawalCredentialsAddressRE.MatchString(s)